@@ -4,14 +4,17 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator"
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/podsecurityreadinesscontroller"
 	"github.com/openshift/cluster-kube-apiserver-operator/pkg/version"
 	"github.com/openshift/library-go/pkg/controller/controllercmd"
 	"k8s.io/utils/clock"
 )
 
 func NewOperator() *cobra.Command {
+	podSecurityReadinessHealthChecker := podsecurityreadinesscontroller.NewSyncHealthChecker(podsecurityreadinesscontroller.DefaultSyncStaleness)
 	cmd := controllercmd.
-		NewControllerCommandConfig("kube-apiserver-operator", version.Get(), operator.RunOperator, clock.RealClock{}).
+		NewControllerCommandConfig("kube-apiserver-operator", version.Get(), operator.NewRunOperator(podSecurityReadinessHealthChecker), clock.RealClock{}).
+		WithHealthChecks(podSecurityReadinessHealthChecker).
 		NewCommand()
 	cmd.Use = "operator"
 	cmd.Short = "Start the Cluster kube-apiserver Operator"
@@ -0,0 +1,71 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+// copiedCSVLabel is set by OLM on the copies of a ClusterServiceVersion in the
+// namespaces targeted by its OperatorGroup. The original lives in the
+// namespace the operator is installed in.
+const copiedCSVLabel = "olm.copiedFrom"
+
+// clusterServiceVersionsResource describes the operators installed through
+// OLM.
+var clusterServiceVersionsResource = schema.GroupVersionResource{
+	Group:    "operators.coreos.com",
+	Version:  "v1alpha1",
+	Resource: "clusterserviceversions",
+}
+
+// newAddOnClient returns the client the add-on operators are discovered with.
+// It shares the rate limiter of the kube client, but not its warnings
+// handler, as warnings about ClusterServiceVersions must not be attributed to
+// an evaluation.
+func newAddOnClient(kubeConfig *rest.Config, rateLimiter *backpressureLimiter) (dynamic.Interface, error) {
+	dynamicClientCopy := rest.CopyConfig(kubeConfig)
+	dynamicClientCopy.RateLimiter = rateLimiter
+	dynamicClientCopy.Wrap(rateLimiter.wrapTransport)
+
+	return dynamic.NewForConfig(dynamicClientCopy)
+}
+
+// refreshAddOnNamespaces determines the namespaces add-on operators are
+// installed in, which are those with a ClusterServiceVersion that isn't a
+// copy. The namespaces an OperatorGroup merely targets hold customer
+// workloads and only get copies. Without OLM there are no add-on namespaces.
+// If the ClusterServiceVersions can't be listed, the previous namespaces are
+// kept so that namespaces don't flip between categories.
+func (c *PodSecurityReadinessController) refreshAddOnNamespaces(ctx context.Context) sets.Set[string] {
+	if c.addOnClient == nil {
+		return c.addOnNamespaces
+	}
+
+	csvs, err := c.addOnClient.Resource(clusterServiceVersionsResource).List(ctx, metav1.ListOptions{
+		LabelSelector: "!" + copiedCSVLabel,
+	})
+	if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+		c.addOnNamespaces = nil
+		return nil
+	}
+	if err != nil {
+		klog.V(2).ErrorS(err, "Failed to list the ClusterServiceVersions, keeping the previous add-on namespaces")
+		return c.addOnNamespaces
+	}
+
+	namespaces := sets.New[string]()
+	for _, csv := range csvs.Items {
+		namespaces.Insert(csv.GetNamespace())
+	}
+	c.addOnNamespaces = namespaces
+
+	return namespaces
+}
@@ -0,0 +1,75 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestRefreshAddOnNamespaces(t *testing.T) {
+	newCSV := func(namespace string, labels map[string]string) *unstructured.Unstructured {
+		csv := &unstructured.Unstructured{}
+		csv.SetAPIVersion("operators.coreos.com/v1alpha1")
+		csv.SetKind("ClusterServiceVersion")
+		csv.SetNamespace(namespace)
+		csv.SetName("addon-operator.v1.0.0")
+		csv.SetLabels(labels)
+		return csv
+	}
+	newClient := func() *dynamicfake.FakeDynamicClient {
+		return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+			map[schema.GroupVersionResource]string{clusterServiceVersionsResource: "ClusterServiceVersionList"},
+			newCSV("addon", nil),
+			newCSV("customer", map[string]string{copiedCSVLabel: "addon"}),
+		)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		listErr  error
+		previous sets.Set[string]
+		expected sets.Set[string]
+	}{
+		{
+			name:     "install namespaces",
+			expected: sets.New("addon"),
+		},
+		{
+			name:     "no OLM",
+			listErr:  apierrors.NewNotFound(clusterServiceVersionsResource.GroupResource(), ""),
+			previous: sets.New("addon"),
+		},
+		{
+			name:     "list failure",
+			listErr:  errors.New("connection refused"),
+			previous: sets.New("previous"),
+			expected: sets.New("previous"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient()
+			if tt.listErr != nil {
+				client.PrependReactor("list", "clusterserviceversions", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.listErr
+				})
+			}
+			controller := &PodSecurityReadinessController{addOnClient: client, addOnNamespaces: tt.previous}
+
+			namespaces := controller.refreshAddOnNamespaces(context.TODO())
+			if !namespaces.Equal(tt.expected) {
+				t.Errorf("expected add-on namespaces %v, got %v", sets.List(tt.expected), sets.List(namespaces))
+			}
+			if !controller.addOnNamespaces.Equal(tt.expected) {
+				t.Errorf("expected the controller to keep add-on namespaces %v, got %v", sets.List(tt.expected), sets.List(controller.addOnNamespaces))
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+)
+
+// namespaceErrorBackoff tracks the consecutive failed evaluations of a
+// namespace and how many more syncs it is skipped for.
+type namespaceErrorBackoff struct {
+	failures int
+	skipped  int
+}
+
+// backoffSyncs returns the number of syncs a namespace is skipped for after
+// the given number of consecutive failed evaluations: none after the first
+// failure, as most errors are transient, then 1, 3, 7 and so on, at most
+// limit.
+func backoffSyncs(failures, limit int) int {
+	if failures <= 1 {
+		return 0
+	}
+	if failures > 31 {
+		return limit
+	}
+
+	return min(1<<(failures-1)-1, limit)
+}
+
+// isBackedOff checks whether the evaluation of the namespace is skipped in
+// this sync because it failed persistently, and counts the skipped sync.
+func (c *PodSecurityReadinessController) isBackedOff(namespace string) bool {
+	backoff, ok := c.namespaceErrors[namespace]
+	if !ok || backoff.skipped == 0 {
+		return false
+	}
+
+	backoff.skipped--
+	c.namespaceErrors[namespace] = backoff
+	return true
+}
+
+// recordEvaluationError counts another consecutive failed evaluation of the
+// namespace and backs it off accordingly, if enabled. Errors that aren't
+// specific to the namespace aren't counted, as skipping it wouldn't help.
+func (c *PodSecurityReadinessController) recordEvaluationError(namespace string, err error) {
+	if c.config.Evaluation.ErrorBackoffLimit <= 0 || !isNamespaceSpecificError(err) {
+		return
+	}
+	if c.namespaceErrors == nil {
+		c.namespaceErrors = map[string]namespaceErrorBackoff{}
+	}
+
+	backoff := c.namespaceErrors[namespace]
+	backoff.failures++
+	backoff.skipped = backoffSyncs(backoff.failures, c.config.Evaluation.ErrorBackoffLimit)
+	c.namespaceErrors[namespace] = backoff
+	if backoff.skipped > 0 {
+		klog.V(2).InfoS("Backing off the evaluation of a persistently failing namespace", "namespace", namespace, "failures", backoff.failures, "skippedSyncs", backoff.skipped)
+	}
+}
+
+// isNamespaceSpecificError checks whether the evaluation error could be caused
+// by the namespace, rather than by an overloaded apiserver or a cancelled
+// sync.
+func isNamespaceSpecificError(err error) bool {
+	switch {
+	case apierrors.IsTooManyRequests(err), apierrors.IsServerTimeout(err), apierrors.IsServiceUnavailable(err):
+		return false
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	}
+
+	return true
+}
+
+// recordEvaluationSuccess puts the namespace back on the normal cadence.
+func (c *PodSecurityReadinessController) recordEvaluationSuccess(namespace string) {
+	delete(c.namespaceErrors, namespace)
+}
+
+// retainNamespaceErrors drops the failures of all namespaces that aren't
+// listed anymore.
+func (c *PodSecurityReadinessController) retainNamespaceErrors(namespaces sets.Set[string]) {
+	for namespace := range c.namespaceErrors {
+		if !namespaces.Has(namespace) {
+			delete(c.namespaceErrors, namespace)
+		}
+	}
+}
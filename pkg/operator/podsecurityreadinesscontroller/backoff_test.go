@@ -0,0 +1,170 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+)
+
+func TestBackoffSyncs(t *testing.T) {
+	for _, tt := range []struct {
+		failures int
+		limit    int
+		expected int
+	}{
+		{failures: 1, limit: 10, expected: 0},
+		{failures: 2, limit: 10, expected: 1},
+		{failures: 3, limit: 10, expected: 3},
+		{failures: 4, limit: 10, expected: 7},
+		{failures: 5, limit: 10, expected: 10},
+		{failures: 100, limit: 10, expected: 10},
+	} {
+		if got := backoffSyncs(tt.failures, tt.limit); got != tt.expected {
+			t.Errorf("expected %d skipped syncs after %d failures with limit %d, got %d", tt.expected, tt.failures, tt.limit, got)
+		}
+	}
+}
+
+func TestNamespaceErrorBackoff(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	}
+
+	failing := true
+	evaluations := 0
+	fakeClient := fake.NewSimpleClientset(namespace)
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		evaluations++
+		if failing {
+			return true, nil, errors.New("webhook unavailable")
+		}
+		return true, nil, nil
+	})
+
+	newController := func(errorBackoffLimit int) *PodSecurityReadinessController {
+		return &PodSecurityReadinessController{
+			kubeClient:      fakeClient,
+			operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			clock:           clock.RealClock{},
+			warningsHandler: &warningsHandler{},
+			dryRunVerified:  true,
+			config:          Config{Evaluation: EvaluationConfig{ErrorBackoffLimit: errorBackoffLimit}},
+		}
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+
+	t.Run("progression", func(t *testing.T) {
+		failing = true
+		controller := newController(3)
+
+		// Evaluated on the 1st, 2nd, 4th, 8th and 12th sync: skipped for
+		// 0, 1, 3 and then at most 3 syncs.
+		var evaluatedSyncs []int
+		for i := 1; i <= 12; i++ {
+			evaluations = 0
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.inconclusiveNamespaces, []string{namespace.Name}) {
+				t.Errorf("sync %d: expected the namespace to be inconclusive, got %v", i, conditions.inconclusiveNamespaces)
+			}
+			if evaluations > 0 {
+				evaluatedSyncs = append(evaluatedSyncs, i)
+				if len(conditions.backedOffNamespaces) != 0 {
+					t.Errorf("sync %d: expected no backed off namespaces, got %v", i, conditions.backedOffNamespaces)
+				}
+			} else if !reflect.DeepEqual(conditions.backedOffNamespaces, []string{namespace.Name}) {
+				t.Errorf("sync %d: expected the namespace to be backed off, got %v", i, conditions.backedOffNamespaces)
+			}
+		}
+
+		expected := []int{1, 2, 4, 8, 12}
+		if !reflect.DeepEqual(evaluatedSyncs, expected) {
+			t.Errorf("expected evaluations in syncs %v, got %v", expected, evaluatedSyncs)
+		}
+	})
+
+	t.Run("recovery", func(t *testing.T) {
+		failing = true
+		controller := newController(3)
+		for i := 0; i < 2; i++ {
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		// Backed off for the next sync, then retried.
+		failing = false
+		for _, expectedBackedOff := range []bool{true, false} {
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if backedOff := len(controller.snapshot().backedOffNamespaces) > 0; backedOff != expectedBackedOff {
+				t.Errorf("expected backed off %v, got %v", expectedBackedOff, backedOff)
+			}
+		}
+
+		conditions := controller.snapshot()
+		if len(conditions.inconclusiveNamespaces) != 0 {
+			t.Errorf("expected no inconclusive namespaces after recovery, got %v", conditions.inconclusiveNamespaces)
+		}
+		if len(controller.namespaceErrors) != 0 {
+			t.Errorf("expected the failures to be cleared, got %v", controller.namespaceErrors)
+		}
+	})
+
+	t.Run("apiserver errors", func(t *testing.T) {
+		for _, err := range []error{
+			apierrors.NewTooManyRequests("throttled", 1),
+			apierrors.NewServerTimeout(corev1.Resource("namespaces"), "patch", 1),
+			apierrors.NewServiceUnavailable("unavailable"),
+			context.Canceled,
+			fmt.Errorf("evaluating: %w", context.DeadlineExceeded),
+		} {
+			controller := newController(3)
+			for i := 0; i < 4; i++ {
+				controller.recordEvaluationError(namespace.Name, err)
+			}
+			if controller.isBackedOff(namespace.Name) {
+				t.Errorf("expected %v not to back off the namespace", err)
+			}
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		failing = true
+		controller := newController(0)
+		for i := 0; i < 4; i++ {
+			evaluations = 0
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if evaluations == 0 {
+				t.Errorf("sync %d: expected the namespace to be evaluated", i)
+			}
+		}
+	})
+}
@@ -0,0 +1,111 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"k8s.io/client-go/util/flowcontrol"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// defaultClientQPS and defaultClientBurst keep the controller from
+	// overwhelming the apiserver. On a cluster with 10k namespaces, we would
+	// send 10k + 1 requests to the apiserver.
+	defaultClientQPS   = 2
+	defaultClientBurst = 2
+
+	// minBackpressureQPS is the rate the client rate limiter is never reduced
+	// below, so that the sync keeps making progress.
+	minBackpressureQPS = 0.25
+)
+
+// backpressureLimiter is the rate limiter of the controller's client. It halves
+// its rate, down to minBackpressureQPS and without bursts, whenever the
+// apiserver responds with 429 Too Many Requests. The configured rate is
+// restored at the start of every sync.
+type backpressureLimiter struct {
+	lock sync.Mutex
+
+	qps     float32
+	burst   int
+	current flowcontrol.RateLimiter
+}
+
+func newBackpressureLimiter(qps float32, burst int) *backpressureLimiter {
+	return &backpressureLimiter{
+		qps:     qps,
+		burst:   burst,
+		current: flowcontrol.NewTokenBucketRateLimiter(qps, burst),
+	}
+}
+
+func (l *backpressureLimiter) limiter() flowcontrol.RateLimiter {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.current
+}
+
+func (l *backpressureLimiter) TryAccept() bool { return l.limiter().TryAccept() }
+
+func (l *backpressureLimiter) Accept() { l.limiter().Accept() }
+
+func (l *backpressureLimiter) Stop() { l.limiter().Stop() }
+
+func (l *backpressureLimiter) QPS() float32 { return l.limiter().QPS() }
+
+func (l *backpressureLimiter) Wait(ctx context.Context) error { return l.limiter().Wait(ctx) }
+
+// throttle halves the current rate. Requests already waiting for the previous
+// rate limiter aren't delayed any further.
+func (l *backpressureLimiter) throttle() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	qps := l.current.QPS() / 2
+	if qps < minBackpressureQPS {
+		qps = minBackpressureQPS
+	}
+	if qps >= l.current.QPS() {
+		return
+	}
+
+	klog.V(2).InfoS("Reducing the request rate as the apiserver is overloaded", "qps", qps)
+	l.current.Stop()
+	l.current = flowcontrol.NewTokenBucketRateLimiter(qps, 1)
+}
+
+// reset restores the configured rate, if it was reduced.
+func (l *backpressureLimiter) reset() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if l.current.QPS() == l.qps {
+		return
+	}
+
+	klog.V(4).InfoS("Restoring the configured request rate", "qps", l.qps)
+	l.current.Stop()
+	l.current = flowcontrol.NewTokenBucketRateLimiter(l.qps, l.burst)
+}
+
+// wrapTransport throttles the limiter for every 429 response, including the
+// ones that the client retries on its own.
+func (l *backpressureLimiter) wrapTransport(rt http.RoundTripper) http.RoundTripper {
+	return &backpressureRoundTripper{limiter: l, delegate: rt}
+}
+
+type backpressureRoundTripper struct {
+	limiter  *backpressureLimiter
+	delegate http.RoundTripper
+}
+
+func (rt *backpressureRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := rt.delegate.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+		rt.limiter.throttle()
+	}
+	return resp, err
+}
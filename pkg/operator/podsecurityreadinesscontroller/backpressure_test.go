@@ -0,0 +1,138 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/clock"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// acceptedRequests returns how many requests the limiter lets through without
+// waiting.
+func acceptedRequests(limiter *backpressureLimiter) int {
+	accepted := 0
+	for accepted < 100 && limiter.TryAccept() {
+		accepted++
+	}
+	return accepted
+}
+
+func TestBackpressureLimiter(t *testing.T) {
+	limiter := newBackpressureLimiter(4, 4)
+
+	var status int
+	transport := limiter.wrapTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: status}, nil
+	}))
+	roundTrip := func(code int) {
+		status = code
+		req, err := http.NewRequest(http.MethodGet, "https://apiserver", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := transport.RoundTrip(req); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	roundTrip(http.StatusOK)
+	if qps := limiter.QPS(); qps != 4 {
+		t.Errorf("expected the configured rate after a successful request, got %v", qps)
+	}
+	if accepted := acceptedRequests(limiter); accepted != 4 {
+		t.Errorf("expected a burst of 4 requests, got %d", accepted)
+	}
+
+	roundTrip(http.StatusTooManyRequests)
+	if qps := limiter.QPS(); qps != 2 {
+		t.Errorf("expected the rate to be halved, got %v", qps)
+	}
+	if accepted := acceptedRequests(limiter); accepted != 1 {
+		t.Errorf("expected no bursts while throttled, got %d requests", accepted)
+	}
+
+	for i := 0; i < 10; i++ {
+		roundTrip(http.StatusTooManyRequests)
+	}
+	if qps := limiter.QPS(); qps != minBackpressureQPS {
+		t.Errorf("expected the rate to be reduced to %v at most, got %v", minBackpressureQPS, qps)
+	}
+
+	limiter.reset()
+	if qps := limiter.QPS(); qps != 4 {
+		t.Errorf("expected the configured rate after a reset, got %v", qps)
+	}
+	if accepted := acceptedRequests(limiter); accepted != 4 {
+		t.Errorf("expected a burst of 4 requests after a reset, got %d", accepted)
+	}
+}
+
+func TestBackpressureLimiterBelowMinimum(t *testing.T) {
+	limiter := newBackpressureLimiter(0.1, 1)
+
+	limiter.throttle()
+	if qps := limiter.QPS(); qps != 0.1 {
+		t.Errorf("expected a configured rate below the minimum to be kept, got %v", qps)
+	}
+}
+
+func TestBackpressureClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		// Without a Retry-After header, the client doesn't retry.
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	limiter := newBackpressureLimiter(100, 100)
+	kubeClient, err := newWarningAwareKubeClient(&warningsHandler{}, &rest.Config{Host: server.URL}, limiter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = kubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{})
+	if !apierrors.IsTooManyRequests(err) {
+		t.Fatalf("expected a too many requests error, got %v", err)
+	}
+	if qps := limiter.QPS(); qps != 50 {
+		t.Errorf("expected the client to throttle its rate limiter to 50, got %v", qps)
+	}
+}
+
+func TestBackpressureResetOnSync(t *testing.T) {
+	limiter := newBackpressureLimiter(2, 2)
+	limiter.throttle()
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fake.NewSimpleClientset(),
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: &warningsHandler{},
+		dryRunVerified:  true,
+		rateLimiter:     limiter,
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if qps := limiter.QPS(); qps != 2 {
+		t.Errorf("expected the configured rate to be restored by the sync, got %v", qps)
+	}
+}
@@ -0,0 +1,137 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1listers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// evaluationCache remembers the evaluation results of namespaces, so that they
+// aren't evaluated again as long as neither the namespace nor its pods change.
+// The pods are fingerprinted from an informer, so that a hit costs no
+// requests.
+type evaluationCache struct {
+	entries map[string]evaluationCacheEntry
+	pods    corev1listers.PodLister
+}
+
+type evaluationCacheEntry struct {
+	namespaceResourceVersion string
+	podsFingerprint          string
+	clusterDefaultLevel      string
+
+	result EvaluationResult
+}
+
+func newEvaluationCache(pods corev1listers.PodLister) *evaluationCache {
+	return &evaluationCache{
+		entries: map[string]evaluationCacheEntry{},
+		pods:    pods,
+	}
+}
+
+// get returns the cached entry of the namespace if it is still valid for the
+// given key.
+func (e *evaluationCache) get(namespace string, key evaluationCacheEntry) (evaluationCacheEntry, bool) {
+	entry, ok := e.entries[namespace]
+	if !ok {
+		return evaluationCacheEntry{}, false
+	}
+
+	if entry.namespaceResourceVersion != key.namespaceResourceVersion ||
+		entry.podsFingerprint != key.podsFingerprint ||
+		entry.clusterDefaultLevel != key.clusterDefaultLevel {
+		return evaluationCacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (e *evaluationCache) set(namespace string, entry evaluationCacheEntry) {
+	e.entries[namespace] = entry
+}
+
+// reset drops all entries.
+func (e *evaluationCache) reset() {
+	e.entries = map[string]evaluationCacheEntry{}
+}
+
+// retain drops the entries of all namespaces that aren't listed anymore.
+func (e *evaluationCache) retain(namespaces sets.Set[string]) {
+	for namespace := range e.entries {
+		if !namespaces.Has(namespace) {
+			delete(e.entries, namespace)
+		}
+	}
+}
+
+// evaluateNamespaceCached evaluates the namespace, reusing the previous result
+// if neither the namespace nor its pods changed since. The dry run evaluates
+// the pods of the namespace as well, so the cache can't rely on the
+// namespace's resource version alone. Conflicting alert levels were reported
+// when the cached result was evaluated, as the labels haven't changed since.
+func (c *PodSecurityReadinessController) evaluateNamespaceCached(ctx context.Context, ns *corev1.Namespace) (EvaluationResult, error) {
+	if c.evaluationCache == nil {
+		return c.evaluateNamespaceViolation(ctx, ns)
+	}
+
+	fingerprint, err := c.evaluationCache.podsFingerprint(ns.Name)
+	if err != nil {
+		klog.V(4).InfoS("Unable to fingerprint pods, bypassing the evaluation cache", "namespace", ns.Name, "error", err)
+		return c.evaluateNamespaceViolation(ctx, ns)
+	}
+
+	key := evaluationCacheEntry{
+		namespaceResourceVersion: ns.ResourceVersion,
+		podsFingerprint:          fingerprint,
+		clusterDefaultLevel:      c.clusterDefaultEnforceLevel,
+	}
+	if entry, ok := c.evaluationCache.get(ns.Name, key); ok {
+		return entry.result, nil
+	}
+
+	result, err := c.evaluateNamespaceViolation(ctx, ns)
+	if err != nil {
+		return result, err
+	}
+
+	// Inconclusive results may change without any change to the namespace
+	// or its pods, e.g. when permissions are granted.
+	if !result.Inconclusive {
+		key.result = result
+		c.evaluationCache.set(ns.Name, key)
+	}
+
+	return result, nil
+}
+
+// podsFingerprint identifies the state of all pods in the namespace as last
+// observed by the informer. A change the informer hasn't observed yet is
+// picked up by the next sync.
+func (e *evaluationCache) podsFingerprint(namespace string) (string, error) {
+	pods, err := e.pods.Pods(namespace).List(labels.Everything())
+	if err != nil {
+		return "", err
+	}
+
+	podVersions := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		podVersions = append(podVersions, fmt.Sprintf("%s/%s", pod.UID, pod.ResourceVersion))
+	}
+	sort.Strings(podVersions)
+
+	hash := fnv.New64a()
+	for _, podVersion := range podVersions {
+		hash.Write([]byte(podVersion))
+		hash.Write([]byte{0})
+	}
+
+	return fmt.Sprintf("%d:%x", len(podVersions), hash.Sum64()), nil
+}
@@ -0,0 +1,170 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/utils/clock"
+)
+
+func TestEvaluationCache(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "cached-namespace",
+			ResourceVersion: "1",
+			// The alert levels conflict, which is reported on every sync.
+			Labels: map[string]string{
+				psapi.AuditLevelLabel: "privileged",
+				psapi.WarnLevelLabel:  "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "pod",
+			Namespace:       "cached-namespace",
+			UID:             "pod-uid",
+			ResourceVersion: "1",
+		},
+	}
+
+	fakeClient := fake.NewSimpleClientset(namespace, pod)
+	dryRuns := 0
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		dryRuns++
+		return true, nil, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	kubeInformers := informers.NewSharedInformerFactory(fakeClient, 0)
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: &warningsHandler{},
+		dryRunVerified:  true,
+		config: Config{
+			Levels: LevelsConfig{ConflictingLevelEvents: true},
+			Pods:   PodsConfig{SkipUserSCCCheck: true},
+		},
+		evaluationCache: newEvaluationCache(kubeInformers.Core().V1().Pods().Lister()),
+	}
+	kubeInformers.Start(ctx.Done())
+	kubeInformers.WaitForCacheSync(ctx.Done())
+
+	// Only the informer lists pods.
+	podLists := 0
+	fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		podLists++
+		return false, nil, nil
+	})
+
+	// waitForPod waits until the informer observed the pod.
+	waitForPod := func(t *testing.T, name, resourceVersion string) {
+		t.Helper()
+
+		err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, wait.ForeverTestTimeout, true, func(context.Context) (bool, error) {
+			observed, err := kubeInformers.Core().V1().Pods().Lister().Pods(pod.Namespace).Get(name)
+			return err == nil && observed.ResourceVersion == resourceVersion, nil
+		})
+		if err != nil {
+			t.Fatalf("the informer didn't observe pod %s: %v", name, err)
+		}
+	}
+
+	sync := func(t *testing.T, expectedDryRuns int) events.InMemoryRecorder {
+		t.Helper()
+
+		recorder := events.NewInMemoryRecorder("test", clock.RealClock{})
+		controller.recorder = recorder
+		syncCtx := factory.NewSyncContext("test", recorder)
+		if err := controller.sync(ctx, syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if dryRuns != expectedDryRuns {
+			t.Errorf("expected %d dry runs, got %d", expectedDryRuns, dryRuns)
+		}
+		if podLists != 0 {
+			t.Errorf("expected no pod lists, got %d", podLists)
+		}
+
+		return recorder
+	}
+
+	t.Run("miss on first sync", func(t *testing.T) {
+		recorder := sync(t, 1)
+		if len(recorder.Events()) != 1 || recorder.Events()[0].Reason != "PodSecurityConflictingLevels" {
+			t.Errorf("expected a single PodSecurityConflictingLevels event, got %v", recorder.Events())
+		}
+	})
+
+	t.Run("hit on unchanged namespace", func(t *testing.T) {
+		// The conflict was already reported.
+		recorder := sync(t, 1)
+		if len(recorder.Events()) != 0 {
+			t.Errorf("expected no events, got %v", recorder.Events())
+		}
+	})
+
+	t.Run("miss on changed namespace", func(t *testing.T) {
+		updated := namespace.DeepCopy()
+		updated.ResourceVersion = "2"
+		if _, err := fakeClient.CoreV1().Namespaces().Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		sync(t, 2)
+		sync(t, 2)
+	})
+
+	t.Run("miss on changed pod", func(t *testing.T) {
+		updated := pod.DeepCopy()
+		updated.ResourceVersion = "2"
+		if _, err := fakeClient.CoreV1().Pods(pod.Namespace).Update(context.TODO(), updated, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		waitForPod(t, updated.Name, updated.ResourceVersion)
+
+		sync(t, 3)
+		sync(t, 3)
+	})
+
+	t.Run("miss on new pod", func(t *testing.T) {
+		added := pod.DeepCopy()
+		added.Name = "another-pod"
+		added.UID = "another-pod-uid"
+		if _, err := fakeClient.CoreV1().Pods(pod.Namespace).Create(context.TODO(), added, metav1.CreateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+		waitForPod(t, added.Name, added.ResourceVersion)
+
+		sync(t, 4)
+	})
+
+	t.Run("drops deleted namespaces", func(t *testing.T) {
+		if err := fakeClient.CoreV1().Namespaces().Delete(context.TODO(), namespace.Name, metav1.DeleteOptions{}); err != nil {
+			t.Fatal(err)
+		}
+
+		sync(t, 4)
+		if len(controller.evaluationCache.entries) != 0 {
+			t.Errorf("expected no cache entries, got %v", controller.evaluationCache.entries)
+		}
+	})
+}
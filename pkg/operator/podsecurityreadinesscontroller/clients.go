@@ -0,0 +1,110 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applyconfiguration "k8s.io/client-go/applyconfigurations/core/v1"
+)
+
+// namespaceClient is the part of the namespace API the controller uses. It is
+// satisfied by the typed client, but narrow enough to be decorated, e.g. for
+// instrumentation or caching, without wrapping the whole clientset.
+type namespaceClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Namespace, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error)
+	Apply(ctx context.Context, namespace *applyconfiguration.NamespaceApplyConfiguration, opts metav1.ApplyOptions) (*corev1.Namespace, error)
+}
+
+// podClient lists the pods of a namespace. Unlike the typed client, it isn't
+// scoped to a namespace, so that a single decorator covers all of them.
+type podClient interface {
+	List(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error)
+}
+
+// podClientFunc adapts a function to a podClient.
+type podClientFunc func(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error)
+
+func (f podClientFunc) List(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	return f(ctx, namespace, opts)
+}
+
+// namespacesClient returns the client the namespaces are read and evaluated
+// with, which defaults to the typed client of kubeClient. Its Applies are
+// traced if tracing is enabled.
+func (c *PodSecurityReadinessController) namespacesClient() namespaceClient {
+	var client namespaceClient = c.namespaces
+	if client == nil {
+		client = c.kubeClient.CoreV1().Namespaces()
+	}
+	if c.config.Tracer != nil {
+		client = tracingNamespaceClient{namespaceClient: client, tracer: c.config.Tracer}
+	}
+
+	return client
+}
+
+// podsClient returns the client the pods are listed with, which defaults to
+// the typed client of kubeClient. Its lists are traced if tracing is enabled,
+// and shared within the evaluation of a namespace, see withSharedPodLists.
+func (c *PodSecurityReadinessController) podsClient() podClient {
+	var client podClient = c.pods
+	if client == nil {
+		client = podClientFunc(func(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+			return c.kubeClient.CoreV1().Pods(namespace).List(ctx, opts)
+		})
+	}
+	if c.config.Tracer != nil {
+		client = tracingPodClient{podClient: client, tracer: c.config.Tracer}
+	}
+
+	return sharingPodClient{podClient: client}
+}
+
+// sharedPodListsKey is the context key of the shared pod lists.
+type sharedPodListsKey struct{}
+
+// sharedPodLists holds the pod lists of the evaluation of a namespace by
+// namespace and list options.
+type sharedPodLists struct {
+	lock  sync.Mutex
+	lists map[string]*corev1.PodList
+}
+
+// withSharedPodLists shares the pod lists within the context, so that the
+// checks of a namespace evaluation that look at its pods list them once.
+// The lists must not be modified.
+func withSharedPodLists(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sharedPodListsKey{}, &sharedPodLists{lists: map[string]*corev1.PodList{}})
+}
+
+// sharingPodClient returns the pod lists shared in the context, if any,
+// instead of listing the pods again.
+type sharingPodClient struct {
+	podClient
+}
+
+func (s sharingPodClient) List(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	shared, ok := ctx.Value(sharedPodListsKey{}).(*sharedPodLists)
+	if !ok {
+		return s.podClient.List(ctx, namespace, opts)
+	}
+
+	key := fmt.Sprintf("%s|%s|%s|%d", namespace, opts.LabelSelector, opts.FieldSelector, opts.Limit)
+	shared.lock.Lock()
+	defer shared.lock.Unlock()
+	if pods, ok := shared.lists[key]; ok {
+		return pods, nil
+	}
+
+	pods, err := s.podClient.List(ctx, namespace, opts)
+	if err != nil {
+		return nil, err
+	}
+	shared.lists[key] = pods
+
+	return pods, nil
+}
@@ -0,0 +1,186 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	applyconfiguration "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+)
+
+// countingNamespaceClient decorates a namespaceClient and counts its calls.
+type countingNamespaceClient struct {
+	namespaceClient
+
+	gets, lists, applies int
+}
+
+func (c *countingNamespaceClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*corev1.Namespace, error) {
+	c.gets++
+	return c.namespaceClient.Get(ctx, name, opts)
+}
+
+func (c *countingNamespaceClient) List(ctx context.Context, opts metav1.ListOptions) (*corev1.NamespaceList, error) {
+	c.lists++
+	return c.namespaceClient.List(ctx, opts)
+}
+
+func (c *countingNamespaceClient) Apply(ctx context.Context, namespace *applyconfiguration.NamespaceApplyConfiguration, opts metav1.ApplyOptions) (*corev1.Namespace, error) {
+	c.applies++
+	return c.namespaceClient.Apply(ctx, namespace, opts)
+}
+
+func TestNamespaceClientDecorator(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	})
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, nil
+	})
+
+	namespaces := &countingNamespaceClient{namespaceClient: fakeClient.CoreV1().Namespaces()}
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: &warningsHandler{},
+		namespaces:      namespaces,
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The self check and the evaluation of the namespace are both applied,
+	// the listed namespace isn't read again without a conflict.
+	if namespaces.lists != 1 || namespaces.gets != 0 || namespaces.applies != 2 {
+		t.Errorf("expected 1 list, no get and 2 applies through the decorator, got %d, %d and %d", namespaces.lists, namespaces.gets, namespaces.applies)
+	}
+}
+
+func TestPodClient(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ns"},
+	}
+	userPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "user-pod",
+			Namespace: "test-ns",
+			Annotations: map[string]string{
+				securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:            "privileged",
+				SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+			}},
+		},
+	}
+
+	psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var listedNamespaces []string
+	controller := &PodSecurityReadinessController{
+		// The clientset doesn't know about the pod, only the pod client does.
+		kubeClient:   fake.NewSimpleClientset(),
+		psaEvaluator: psaEvaluator,
+		pods: podClientFunc(func(_ context.Context, namespace string, _ metav1.ListOptions) (*corev1.PodList, error) {
+			listedNamespaces = append(listedNamespaces, namespace)
+			return &corev1.PodList{Items: []corev1.Pod{userPod}}, nil
+		}),
+	}
+
+	violating, err := controller.isUserViolation(context.TODO(), namespace, string(psapi.LevelBaseline))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !violating {
+		t.Error("expected the pod listed by the pod client to be violating")
+	}
+	if len(listedNamespaces) != 1 || listedNamespaces[0] != "test-ns" {
+		t.Errorf("expected the pods of test-ns to be listed once, got %v", listedNamespaces)
+	}
+}
+
+func TestDefaultClients(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: "test-ns"}},
+	)
+	controller := &PodSecurityReadinessController{kubeClient: fakeClient}
+
+	nsList, err := controller.namespacesClient().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(nsList.Items) != 1 {
+		t.Errorf("expected the namespaces of the clientset, got %d", len(nsList.Items))
+	}
+
+	pods, err := controller.podsClient().List(context.TODO(), "test-ns", metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods.Items) != 1 {
+		t.Errorf("expected the pods of the clientset, got %d", len(pods.Items))
+	}
+}
+
+func TestSharedPodLists(t *testing.T) {
+	var lists int
+	controller := &PodSecurityReadinessController{
+		pods: podClientFunc(func(_ context.Context, namespace string, _ metav1.ListOptions) (*corev1.PodList, error) {
+			lists++
+			return &corev1.PodList{}, nil
+		}),
+	}
+
+	list := func(ctx context.Context, namespace string, opts metav1.ListOptions) {
+		if _, err := controller.podsClient().List(ctx, namespace, opts); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ctx := withSharedPodLists(context.TODO())
+	list(ctx, "test-ns", metav1.ListOptions{})
+	list(ctx, "test-ns", metav1.ListOptions{})
+	if lists != 1 {
+		t.Errorf("expected the pods to be listed once within the evaluation, got %d", lists)
+	}
+
+	list(ctx, "test-ns", metav1.ListOptions{Limit: 10})
+	list(ctx, "other-ns", metav1.ListOptions{})
+	if lists != 3 {
+		t.Errorf("expected other options and namespaces to be listed separately, got %d", lists)
+	}
+
+	list(withSharedPodLists(context.TODO()), "test-ns", metav1.ListOptions{})
+	list(context.TODO(), "test-ns", metav1.ListOptions{})
+	if lists != 5 {
+		t.Errorf("expected the lists not to be shared beyond the evaluation, got %d", lists)
+	}
+}
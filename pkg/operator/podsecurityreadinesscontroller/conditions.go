@@ -1,16 +1,23 @@
 package podsecurityreadinesscontroller
 
 import (
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	psapi "k8s.io/pod-security-admission/api"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
 )
 
 const (
@@ -18,20 +25,138 @@ const (
 	PodSecurityOpenshiftType      = "PodSecurityOpenshiftEvaluationConditionsDetected"
 	PodSecurityRunLevelZeroType   = "PodSecurityRunLevelZeroEvaluationConditionsDetected"
 	PodSecurityDisabledSyncerType = "PodSecurityDisabledSyncerEvaluationConditionsDetected"
+	PodSecurityAddOnType          = "PodSecurityAddOnEvaluationConditionsDetected"
 	PodSecurityInconclusiveType   = "PodSecurityInconclusiveEvaluationConditionsDetected"
+	PodSecurityUserSCCType        = "PodSecurityUserSCCEvaluationConditionsDetected"
+
+	PodSecurityUserSCCInconclusiveType = "PodSecurityUserSCCInconclusiveEvaluationConditionsDetected"
+	PodSecurityEnforcedRegressionType  = "PodSecurityEnforcedRegressionEvaluationConditionsDetected"
+	PodSecurityStaleAnnotationType     = "PodSecurityStaleAnnotationEvaluationConditionsDetected"
+	PodSecurityLabelFixableType        = "PodSecurityLabelFixableEvaluationConditionsDetected"
+	PodSecurityWorkloadBlockingType    = "PodSecurityWorkloadBlockingEvaluationConditionsDetected"
+	PodSecurityCleanType               = "PodSecurityCleanNamespacesEvaluated"
+	PodSecurityOptedOutType            = "PodSecurityOptedOutNamespacesDetected"
+	PodSecurityCountsType              = "PodSecurityNamespaceCountsEvaluated"
+	PodSecurityMisconfiguredType       = "PodSecurityMisconfiguredEvaluationConditionsDetected"
+	PodSecurityVolumeOnlyType          = "PodSecurityVolumeOnlyEvaluationConditionsDetected"
+	PodSecurityViolationsSummaryType   = "PodSecurityViolationsSummary"
+	PodSecurityFailedClosedType        = "PodSecurityFailedClosedEvaluationConditionsDetected"
+	PodSecuritySyncerPendingType       = "PodSecuritySyncerPendingConditionsDetected"
+	PodSecurityFullyEnforcedType       = "PodSecurityFullyEnforced"
+	PodSecurityBackedOffType           = "PodSecurityBackedOffEvaluationConditionsDetected"
+
+	PodSecurityRunLevelZeroUpgradeableType = "PodSecurityRunLevelZeroUpgradeable"
+	PodSecurityRunLevelZeroDegradedType    = "PodSecurityRunLevelZeroDegraded"
+	PodSecurityCustomerUpgradeableType     = "PodSecurityCustomerUpgradeable"
+	PodSecurityDryRunDegradedType          = "PodSecurityDryRunDegraded"
+	PodSecurityNamespaceListDegradedType   = "PodSecurityNamespaceListDegraded"
+	PodSecurityReadinessSyncHealthyType    = "PodSecurityReadinessSyncHealthy"
+	PodSecurityWarningsDegradedType        = "PodSecurityWarningsDegraded"
+	PodSecurityThresholdDegradedType       = "PodSecurityViolationThresholdDegraded"
+	PodSecurityReadinessInitializingType   = "PodSecurityReadinessInitializing"
+	PodSecurityEvaluationInconclusiveType  = "PodSecurityEvaluationInconclusive"
+
+	// legacyReadinessAvailableType was replaced by
+	// PodSecurityReadinessSyncHealthyType and is removed when found.
+	legacyReadinessAvailableType = "PodSecurityReadinessAvailable"
+
+	// conditionTypePrefix prefixes the types of all conditions of the
+	// controller.
+	conditionTypePrefix = "PodSecurity"
 
 	labelSyncControlLabel = "security.openshift.io/scc.podSecurityLabelSync"
+	// readinessOptOutAnnotation lets namespace owners exclude their namespace
+	// from the evaluation, e.g. for accepted risks.
+	readinessOptOutAnnotation = "security.openshift.io/readiness-opt-out"
+
+	violationReason     = "PSViolationsDetected"
+	inconclusiveReason  = "PSViolationDecisionInconclusive"
+	staleReason         = "PSStaleAnnotationDetected"
+	labelFixableReason  = "PSViolationsFixableByLabel"
+	blockingReason      = "PSViolationsRequireWorkloadChanges"
+	cleanReason         = "PSNoViolationsDetected"
+	optedOutReason      = "PSEvaluationOptedOut"
+	countsReason        = "PSNamespacesCounted"
+	misconfiguredReason = "PSInvalidEnforceLevel"
+	volumeOnlyReason    = "PSViolationsCausedByVolumes"
+	summaryReason       = "PSViolationsSummarized"
+	failedClosedReason  = "PSEvaluationFailedClosed"
+	syncerPendingReason = "PSSyncerPending"
+	backedOffReason     = "PSEvaluationBackedOff"
+	expectedReason      = "ExpectedReason"
+	dryRunFailedReason  = "DryRunForbidden"
+	listFailedReason    = "NamespaceListFailed"
+	syncFailedReason    = "SyncFailed"
+	warningsLostReason  = "WarningsNotCaptured"
+	thresholdReason     = "PSViolationThresholdExceeded"
+	initializingReason  = "PSEvaluationPending"
+	fullyEnforcedReason = "PSAllNamespacesEnforced"
 
-	violationReason    = "PSViolationsDetected"
-	inconclusiveReason = "PSViolationDecisionInconclusive"
+	// The category reasons replace violationReason, see ConditionsConfig.CategoryReasons.
+	customerViolationReason           = "PSCustomerViolations"
+	openShiftViolationReason          = "PSOpenShiftViolations"
+	runLevelZeroViolationReason       = "PSRunLevelZeroViolations"
+	disabledSyncerViolationReason     = "PSDisabledSyncerViolations"
+	addOnViolationReason              = "PSAddOnViolations"
+	userSCCViolationReason            = "PSUserSCCViolations"
+	enforcedRegressionViolationReason = "PSEnforcedRegressionViolations"
+
+	// maxReportedViolatingSince limits the number of namespaces for which
+	// the condition message states since when they are violating.
+	maxReportedViolatingSince = 3
+	// maxSummaryNamespaces limits the number of namespaces listed per
+	// category in the summary.
+	maxSummaryNamespaces = 3
+)
+
+// RunLevelZeroEscalation selects the operator condition that is additionally
+// raised when a run-level zero namespace is violating. Enforcing pod security
+// admission there could break the control plane.
+type RunLevelZeroEscalation string
+
+const (
+	RunLevelZeroEscalationNone        RunLevelZeroEscalation = ""
+	RunLevelZeroEscalationUpgradeable RunLevelZeroEscalation = "Upgradeable"
+	RunLevelZeroEscalationDegraded    RunLevelZeroEscalation = "Degraded"
 )
 
+// DegradedThresholds holds, per category, the number of violating namespaces
+// at which the operator reports Degraded. A zero threshold never degrades.
+type DegradedThresholds struct {
+	Customer     int
+	OpenShift    int
+	RunLevelZero int
+}
+
 var (
-	// run-level zero namespaces, shouldn't avoid openshift namespaces
+	// categoryViolationReasons maps the types of the conditions that are
+	// raised with violationReason to the reason of their category.
+	categoryViolationReasons = map[string]string{
+		PodSecurityCustomerType:                customerViolationReason,
+		PodSecurityCustomerUpgradeableType:     customerViolationReason,
+		PodSecurityOpenshiftType:               openShiftViolationReason,
+		PodSecurityRunLevelZeroType:            runLevelZeroViolationReason,
+		PodSecurityRunLevelZeroUpgradeableType: runLevelZeroViolationReason,
+		PodSecurityRunLevelZeroDegradedType:    runLevelZeroViolationReason,
+		PodSecurityDisabledSyncerType:          disabledSyncerViolationReason,
+		PodSecurityAddOnType:                   addOnViolationReason,
+		PodSecurityUserSCCType:                 userSCCViolationReason,
+		PodSecurityEnforcedRegressionType:      enforcedRegressionViolationReason,
+	}
+
+	// runLevelZeroNamespaces are the namespaces Kubernetes itself creates.
+	// They are reported together, as system namespaces, and never as
+	// customer namespaces. This list is explicit on purpose: namespaces that
+	// merely share the "kube-" prefix may belong to customers.
 	runLevelZeroNamespaces = sets.New[string](
+		// default holds the kubernetes service.
 		"default",
+		// kube-system holds the control plane components.
 		"kube-system",
+		// kube-public holds the cluster-info ConfigMap.
 		"kube-public",
+		// kube-node-lease holds the node heartbeat leases.
+		"kube-node-lease",
 	)
 )
 
@@ -40,34 +165,508 @@ type podSecurityOperatorConditions struct {
 	violatingRunLevelZeroNamespaces   []string
 	violatingCustomerNamespaces       []string
 	violatingDisabledSyncerNamespaces []string
+	violatingAddOnNamespaces          []string
 	inconclusiveNamespaces            []string
+	userSCCViolatingNamespaces        []string
+	userSCCInconclusiveNamespaces     []string
+	regressedEnforcingNamespaces      []string
+	staleAnnotationNamespaces         []string
+	optedOutNamespaces                []string
+	// labelFixableNamespaces and workloadBlockingNamespaces split the
+	// violating namespaces by remediation, if remediationClassified is set.
+	labelFixableNamespaces     []string
+	workloadBlockingNamespaces []string
+	remediationClassified      bool
+	// misconfiguredNamespaces holds the namespaces with an invalid enforce
+	// level, if misconfigurationChecked is set.
+	misconfiguredNamespaces []string
+	misconfigurationChecked bool
+	// userSCCSkipped is set if pods weren't evaluated for user SCC
+	// violations, in which case their conditions aren't reported.
+	userSCCSkipped bool
+	// volumeOnlyNamespaces holds the user SCC violating namespaces whose
+	// pods only fail volume checks.
+	volumeOnlyNamespaces []string
+	// checkFamilies maps user SCC violating namespaces to the family of the
+	// checks their pods fail most often.
+	checkFamilies map[string]string
+	// customerOverrides holds the namespaces that are classified as
+	// customer namespaces regardless of their name and labels.
+	customerOverrides sets.Set[string]
+	// addOnNamespaces holds the namespaces add-on operators are installed in.
+	addOnNamespaces sets.Set[string]
+	// inconclusiveCategories maps the inconclusive namespaces to their
+	// violation category.
+	inconclusiveCategories map[string]namespaceCategory
+	// collapseInconclusive reports all inconclusive namespaces in a single
+	// condition, grouped by their category.
+	collapseInconclusive bool
+	// failClosed reports the namespaces that couldn't be evaluated as
+	// violating, failedClosedNamespaces holds them.
+	failClosed             bool
+	failedClosedNamespaces []string
+	// syncerPendingNamespaces holds the namespaces that the syncer should
+	// have labeled, but that have neither alert labels nor the annotation.
+	syncerPendingNamespaces []string
+	// backedOffNamespaces holds the namespaces that weren't evaluated because
+	// their evaluation kept failing.
+	backedOffNamespaces []string
+	// fullyEnforced is set if no namespace was left to evaluate, because pod
+	// security admission enforces a level in all of them.
+	fullyEnforced bool
+	// enforcedNamespacesAudited is set if namespaces that already enforce pod
+	// security were checked for regressions, backOffEnabled is set if
+	// namespaces whose evaluation keeps failing are backed off. Their
+	// conditions are only reported if they are set.
+	enforcedNamespacesAudited bool
+	backOffEnabled            bool
+	// summaries reports the clean namespaces, the namespace counts and the
+	// violations summary, diagnostics reports the stale annotation, opted
+	// out, syncer pending and volume only namespaces. violationDetails adds
+	// the evaluated levels and the longest violating namespaces to the
+	// messages of the violation conditions.
+	summaries        bool
+	diagnostics      bool
+	violationDetails bool
+
+	runLevelZeroEscalation RunLevelZeroEscalation
+	degradedThresholds     DegradedThresholds
+	// terse removes the conditions of empty categories instead of reporting
+	// them with their healthy status.
+	terse bool
+	// blockUpgradeOnCustomerViolations sets Upgradeable=False while customer
+	// namespaces are violating.
+	blockUpgradeOnCustomerViolations bool
+	// informational removes the Degraded, Upgradeable and Available
+	// conditions, so that the evaluation never gates the operator.
+	informational bool
+	// categoryReasons raises the violation conditions with the reason of
+	// their category instead of violationReason.
+	categoryReasons bool
+	// evaluatedLevels maps violating namespaces to the enforce level they
+	// were evaluated against.
+	evaluatedLevels map[string]string
+	// achievableLevels maps violating namespaces to the strictest level
+	// they would satisfy, if probed.
+	achievableLevels map[string]string
+	// workloadKinds maps violating customer namespaces to the kinds of the
+	// workloads that own the violating pods.
+	workloadKinds map[string][]string
+	// cleanCounts holds the number of namespaces that aren't violating the
+	// level they were evaluated against, per level.
+	cleanCounts map[string]int
+	// violatingSince holds since when each namespace has been violating
+	// continuously, as tracked by the controller across syncs.
+	violatingSince map[string]time.Time
+	// dryRunFailure holds why namespaces can't be evaluated with a dry-run
+	// Apply, if they haven't been for longer than tolerated.
+	dryRunFailure string
+	// listFailure holds why the namespaces couldn't be listed, if they
+	// haven't been for longer than tolerated.
+	listFailure string
+	// syncFailure holds the error of the last sync, if the syncs have been
+	// failing for longer than tolerated since failingSince.
+	syncFailure  string
+	failingSince time.Time
+	// warningHeartbeat is set if it was verified whether warnings are
+	// captured, warningsDropped holds the outcome. warningHeartbeatFailed is
+	// set if the verification couldn't be performed.
+	warningHeartbeat       bool
+	warningsDropped        bool
+	warningHeartbeatFailed bool
 }
 
-func (c *podSecurityOperatorConditions) addViolation(ns *corev1.Namespace) {
+// deepCopy returns a copy of the conditions that doesn't share any slices with
+// the original.
+func (c *podSecurityOperatorConditions) deepCopy() podSecurityOperatorConditions {
+	return podSecurityOperatorConditions{
+		violatingOpenShiftNamespaces:      slices.Clone(c.violatingOpenShiftNamespaces),
+		violatingRunLevelZeroNamespaces:   slices.Clone(c.violatingRunLevelZeroNamespaces),
+		violatingCustomerNamespaces:       slices.Clone(c.violatingCustomerNamespaces),
+		violatingDisabledSyncerNamespaces: slices.Clone(c.violatingDisabledSyncerNamespaces),
+		violatingAddOnNamespaces:          slices.Clone(c.violatingAddOnNamespaces),
+		inconclusiveNamespaces:            slices.Clone(c.inconclusiveNamespaces),
+		userSCCViolatingNamespaces:        slices.Clone(c.userSCCViolatingNamespaces),
+		userSCCInconclusiveNamespaces:     slices.Clone(c.userSCCInconclusiveNamespaces),
+		regressedEnforcingNamespaces:      slices.Clone(c.regressedEnforcingNamespaces),
+		staleAnnotationNamespaces:         slices.Clone(c.staleAnnotationNamespaces),
+		optedOutNamespaces:                slices.Clone(c.optedOutNamespaces),
+		labelFixableNamespaces:            slices.Clone(c.labelFixableNamespaces),
+		workloadBlockingNamespaces:        slices.Clone(c.workloadBlockingNamespaces),
+		remediationClassified:             c.remediationClassified,
+		misconfiguredNamespaces:           slices.Clone(c.misconfiguredNamespaces),
+		misconfigurationChecked:           c.misconfigurationChecked,
+		userSCCSkipped:                    c.userSCCSkipped,
+		volumeOnlyNamespaces:              slices.Clone(c.volumeOnlyNamespaces),
+		checkFamilies:                     maps.Clone(c.checkFamilies),
+		customerOverrides:                 maps.Clone(c.customerOverrides),
+		addOnNamespaces:                   maps.Clone(c.addOnNamespaces),
+		inconclusiveCategories:            maps.Clone(c.inconclusiveCategories),
+		collapseInconclusive:              c.collapseInconclusive,
+		failClosed:                        c.failClosed,
+		failedClosedNamespaces:            slices.Clone(c.failedClosedNamespaces),
+		syncerPendingNamespaces:           slices.Clone(c.syncerPendingNamespaces),
+		backedOffNamespaces:               slices.Clone(c.backedOffNamespaces),
+		fullyEnforced:                     c.fullyEnforced,
+		enforcedNamespacesAudited:         c.enforcedNamespacesAudited,
+		backOffEnabled:                    c.backOffEnabled,
+		summaries:                         c.summaries,
+		diagnostics:                       c.diagnostics,
+		violationDetails:                  c.violationDetails,
+
+		runLevelZeroEscalation: c.runLevelZeroEscalation,
+		degradedThresholds:     c.degradedThresholds,
+		terse:                  c.terse,
+
+		blockUpgradeOnCustomerViolations: c.blockUpgradeOnCustomerViolations,
+		informational:                    c.informational,
+		categoryReasons:                  c.categoryReasons,
+		evaluatedLevels:                  maps.Clone(c.evaluatedLevels),
+		achievableLevels:                 maps.Clone(c.achievableLevels),
+		workloadKinds:                    maps.Clone(c.workloadKinds),
+		cleanCounts:                      maps.Clone(c.cleanCounts),
+		violatingSince:                   maps.Clone(c.violatingSince),
+		dryRunFailure:                    c.dryRunFailure,
+		listFailure:                      c.listFailure,
+		syncFailure:                      c.syncFailure,
+		failingSince:                     c.failingSince,
+		warningHeartbeat:                 c.warningHeartbeat,
+		warningsDropped:                  c.warningsDropped,
+		warningHeartbeatFailed:           c.warningHeartbeatFailed,
+	}
+}
+
+// violatingNamespaces returns the namespaces of all violation categories.
+func (c *podSecurityOperatorConditions) violatingNamespaces() []string {
+	return slices.Concat(
+		c.violatingOpenShiftNamespaces,
+		c.violatingRunLevelZeroNamespaces,
+		c.violatingCustomerNamespaces,
+		c.violatingDisabledSyncerNamespaces,
+		c.violatingAddOnNamespaces,
+	)
+}
+
+// Summary renders the number of namespaces of every category, with the first
+// namespaces in alphabetical order, one category per line.
+func (c *podSecurityOperatorConditions) Summary() string {
+	var summary strings.Builder
+	for _, category := range []struct {
+		name       string
+		namespaces []string
+	}{
+		{"customer", c.violatingCustomerNamespaces},
+		{"openshift", c.violatingOpenShiftNamespaces},
+		{"runLevelZero", c.violatingRunLevelZeroNamespaces},
+		{"disabledSyncer", c.violatingDisabledSyncerNamespaces},
+		{"addOn", c.violatingAddOnNamespaces},
+		{"inconclusive", c.inconclusiveNamespaces},
+		{"userSCC", c.userSCCViolatingNamespaces},
+		{"userSCCInconclusive", c.userSCCInconclusiveNamespaces},
+		{"enforcedRegression", c.regressedEnforcingNamespaces},
+		{"optedOut", c.optedOutNamespaces},
+		{"misconfigured", c.misconfiguredNamespaces},
+		{"volumeOnly", c.volumeOnlyNamespaces},
+		{"failedClosed", c.failedClosedNamespaces},
+		{"syncerPending", c.syncerPendingNamespaces},
+		{"backedOff", c.backedOffNamespaces},
+	} {
+		fmt.Fprintf(&summary, "%s: %d", category.name, len(category.namespaces))
+		if len(category.namespaces) > 0 {
+			namespaces := sortedClone(category.namespaces)
+			if len(namespaces) > maxSummaryNamespaces {
+				namespaces = append(namespaces[:maxSummaryNamespaces], fmt.Sprintf("and %d more", len(namespaces)-maxSummaryNamespaces))
+			}
+			fmt.Fprintf(&summary, " (%s)", strings.Join(namespaces, ", "))
+		}
+		summary.WriteString("\n")
+	}
+	fmt.Fprintf(&summary, "clean: %d", newCategoryCounts(c).Clean)
+
+	return summary.String()
+}
+
+// namespaceCategory is the violation category a namespace is reported in.
+type namespaceCategory int
+
+const (
+	categoryCustomer namespaceCategory = iota
+	categoryRunLevelZero
+	categoryOpenShift
+	categoryDisabledSyncer
+	categoryAddOn
+)
+
+// String returns the name of the category as used in the results.
+func (c namespaceCategory) String() string {
+	switch c {
+	case categoryRunLevelZero:
+		return "runLevelZero"
+	case categoryOpenShift:
+		return "openShift"
+	case categoryDisabledSyncer:
+		return "disabledSyncer"
+	case categoryAddOn:
+		return "addOn"
+	default:
+		return "customer"
+	}
+}
+
+// classifyNamespace returns the violation category of the namespace, given the
+// namespaces add-on operators are installed in.
+func classifyNamespace(ns *corev1.Namespace, addOnNamespaces sets.Set[string]) namespaceCategory {
 	if runLevelZeroNamespaces.Has(ns.Name) {
-		c.violatingRunLevelZeroNamespaces = append(c.violatingRunLevelZeroNamespaces, ns.Name)
-		return
+		return categoryRunLevelZero
 	}
 
-	isOpenShift := strings.HasPrefix(ns.Name, "openshift")
-	if isOpenShift {
-		c.violatingOpenShiftNamespaces = append(c.violatingOpenShiftNamespaces, ns.Name)
-		return
+	if strings.HasPrefix(ns.Name, "openshift") {
+		return categoryOpenShift
 	}
 
 	if ns.Labels[labelSyncControlLabel] == "false" {
 		// This is the only case in which the controller wouldn't enforce the pod security standards.
+		return categoryDisabledSyncer
+	}
+
+	if addOnNamespaces.Has(ns.Name) {
+		// The violations have to be fixed by the add-on operator, not by the
+		// cluster admin.
+		return categoryAddOn
+	}
+
+	return categoryCustomer
+}
+
+// classify returns the violation category of the namespace, honoring the
+// customer overrides.
+func (c *podSecurityOperatorConditions) classify(ns *corev1.Namespace) namespaceCategory {
+	if isOperatorNamespace(ns) {
+		// The pods of the operator itself must never count as customer
+		// workloads, whatever the overrides say.
+		return categoryOpenShift
+	}
+
+	if c.customerOverrides.Has(ns.Name) {
+		return categoryCustomer
+	}
+
+	return classifyNamespace(ns, c.addOnNamespaces)
+}
+
+// isSyncerEnabled checks whether the syncer is expected to label the namespace.
+// Like the syncer, it skips OpenShift and run-level zero namespaces unless they
+// opt in.
+func isSyncerEnabled(ns *corev1.Namespace) bool {
+	switch ns.Labels[labelSyncControlLabel] {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	return !runLevelZeroNamespaces.Has(ns.Name) && !strings.HasPrefix(ns.Name, "openshift")
+}
+
+// isOperatorNamespace checks whether the namespace is the one the operator
+// runs in, which is always a platform namespace.
+func isOperatorNamespace(ns *corev1.Namespace) bool {
+	return ns.Name == operatorclient.OperatorNamespace
+}
+
+// isOptedOut checks whether the namespace owner opted the namespace out of the
+// evaluation.
+func isOptedOut(ns *corev1.Namespace) bool {
+	return ns.Annotations[readinessOptOutAnnotation] == "true"
+}
+
+func (c *podSecurityOperatorConditions) addViolation(ns *corev1.Namespace) {
+	switch c.classify(ns) {
+	case categoryRunLevelZero:
+		c.violatingRunLevelZeroNamespaces = append(c.violatingRunLevelZeroNamespaces, ns.Name)
+	case categoryOpenShift:
+		c.violatingOpenShiftNamespaces = append(c.violatingOpenShiftNamespaces, ns.Name)
+	case categoryDisabledSyncer:
 		c.violatingDisabledSyncerNamespaces = append(c.violatingDisabledSyncerNamespaces, ns.Name)
+	case categoryAddOn:
+		c.violatingAddOnNamespaces = append(c.violatingAddOnNamespaces, ns.Name)
+	default:
+		c.violatingCustomerNamespaces = append(c.violatingCustomerNamespaces, ns.Name)
+	}
+}
+
+// addUserSCCViolation records a namespace with violating pods that were
+// admitted through a user-bound SCC.
+func (c *podSecurityOperatorConditions) addUserSCCViolation(ns *corev1.Namespace) {
+	c.userSCCViolatingNamespaces = append(c.userSCCViolatingNamespaces, ns.Name)
+}
+
+// addUserSCCInconclusive records a violating namespace for which it couldn't be
+// decided whether the violating pods were admitted through a user-bound SCC.
+func (c *podSecurityOperatorConditions) addUserSCCInconclusive(ns *corev1.Namespace) {
+	c.userSCCInconclusiveNamespaces = append(c.userSCCInconclusiveNamespaces, ns.Name)
+	c.addInconclusiveCategory(ns)
+}
+
+// addEnforcedRegression records a namespace that already enforces pod security
+// but contains pods that violate its enforce level.
+func (c *podSecurityOperatorConditions) addEnforcedRegression(ns *corev1.Namespace) {
+	c.regressedEnforcingNamespaces = append(c.regressedEnforcingNamespaces, ns.Name)
+}
+
+// addStaleAnnotation records a namespace whose minimally sufficient pod
+// security annotation disagrees with its alert labels.
+func (c *podSecurityOperatorConditions) addStaleAnnotation(ns *corev1.Namespace) {
+	c.staleAnnotationNamespaces = append(c.staleAnnotationNamespaces, ns.Name)
+}
+
+// addOptedOut records a namespace that was skipped because its owner opted it
+// out of the evaluation.
+func (c *podSecurityOperatorConditions) addOptedOut(ns *corev1.Namespace) {
+	c.optedOutNamespaces = append(c.optedOutNamespaces, ns.Name)
+}
+
+// addMisconfigured records a namespace whose enforce label isn't a valid
+// level.
+func (c *podSecurityOperatorConditions) addMisconfigured(ns *corev1.Namespace) {
+	c.misconfiguredNamespaces = append(c.misconfiguredNamespaces, ns.Name)
+}
+
+// addWorkloadKinds records the kinds of the workloads that own the violating
+// pods of a customer namespace.
+func (c *podSecurityOperatorConditions) addWorkloadKinds(ns *corev1.Namespace, kinds []string) {
+	if c.workloadKinds == nil {
+		c.workloadKinds = map[string][]string{}
+	}
+
+	c.workloadKinds[ns.Name] = kinds
+}
+
+// addResult records the evaluation result of the namespace in the categories
+// it belongs to.
+func (c *podSecurityOperatorConditions) addResult(ns *corev1.Namespace, result EvaluationResult) {
+	if result.Violating {
+		c.addViolation(ns)
+		c.addEvaluatedLevel(ns, result.Level)
+	} else {
+		c.addClean(result.Level)
+	}
+	if result.Inconclusive {
+		if c.failClosed {
+			c.addFailedClosed(ns)
+			c.addUserSCCViolation(ns)
+		} else {
+			c.addUserSCCInconclusive(ns)
+		}
+	}
+	if result.UserWorkload {
+		c.addUserSCCViolation(ns)
+		c.addCheckFamily(ns, result.CheckFamily)
+	}
+	if result.VolumeOnly {
+		c.addVolumeOnly(ns)
+	}
+}
+
+// addCheckFamily records the family of the checks the pods of a user SCC
+// violating namespace fail most often.
+func (c *podSecurityOperatorConditions) addCheckFamily(ns *corev1.Namespace, family string) {
+	if len(family) == 0 {
 		return
 	}
+	if c.checkFamilies == nil {
+		c.checkFamilies = map[string]string{}
+	}
+
+	c.checkFamilies[ns.Name] = family
+}
+
+func (c *podSecurityOperatorConditions) addVolumeOnly(ns *corev1.Namespace) {
+	c.volumeOnlyNamespaces = append(c.volumeOnlyNamespaces, ns.Name)
+}
+
+// addEvaluatedLevel records the enforce level a violating namespace was
+// evaluated against.
+func (c *podSecurityOperatorConditions) addEvaluatedLevel(ns *corev1.Namespace, level string) {
+	if c.evaluatedLevels == nil {
+		c.evaluatedLevels = map[string]string{}
+	}
 
-	c.violatingCustomerNamespaces = append(c.violatingCustomerNamespaces, ns.Name)
+	c.evaluatedLevels[ns.Name] = level
+}
+
+// addAchievableLevel records the strictest level a violating namespace would
+// satisfy.
+func (c *podSecurityOperatorConditions) addAchievableLevel(ns *corev1.Namespace, level string) {
+	if c.achievableLevels == nil {
+		c.achievableLevels = map[string]string{}
+	}
+
+	c.achievableLevels[ns.Name] = level
 }
 
 func (c *podSecurityOperatorConditions) addInconclusive(ns *corev1.Namespace) {
+	if c.failClosed {
+		c.addFailedClosed(ns)
+		c.addViolation(ns)
+		return
+	}
+
 	c.inconclusiveNamespaces = append(c.inconclusiveNamespaces, ns.Name)
+	c.addInconclusiveCategory(ns)
+}
+
+// addFailedClosed records a namespace that is reported as violating because it
+// couldn't be evaluated.
+func (c *podSecurityOperatorConditions) addFailedClosed(ns *corev1.Namespace) {
+	c.failedClosedNamespaces = append(c.failedClosedNamespaces, ns.Name)
 }
 
+// addSyncerPending records a namespace that the syncer should have labeled, but
+// that has neither alert labels nor the annotation yet.
+func (c *podSecurityOperatorConditions) addSyncerPending(ns *corev1.Namespace) {
+	c.syncerPendingNamespaces = append(c.syncerPendingNamespaces, ns.Name)
+}
+
+// addBackedOff records a namespace that isn't evaluated in this sync because
+// its evaluation kept failing.
+func (c *podSecurityOperatorConditions) addBackedOff(ns *corev1.Namespace) {
+	c.backedOffNamespaces = append(c.backedOffNamespaces, ns.Name)
+}
+
+// addInconclusiveCategory records the category of an inconclusive namespace,
+// for the collapsed inconclusive condition.
+func (c *podSecurityOperatorConditions) addInconclusiveCategory(ns *corev1.Namespace) {
+	if c.inconclusiveCategories == nil {
+		c.inconclusiveCategories = map[string]namespaceCategory{}
+	}
+	c.inconclusiveCategories[ns.Name] = c.classify(ns)
+}
+
+// compact ensures every namespace is only reported as either violating or
+// inconclusive, for whether it violates and for whether its user workloads
+// do. Violations are kept, as they were determined.
+func (c *podSecurityOperatorConditions) compact() {
+	c.inconclusiveNamespaces = withoutConflicts(c.inconclusiveNamespaces, c.violatingNamespaces())
+	c.userSCCInconclusiveNamespaces = withoutConflicts(c.userSCCInconclusiveNamespaces, c.userSCCViolatingNamespaces)
+}
+
+// withoutConflicts removes the violating namespaces from the inconclusive
+// ones.
+func withoutConflicts(inconclusive, violating []string) []string {
+	violatingSet := sets.New(violating...)
+	return slices.DeleteFunc(inconclusive, func(ns string) bool {
+		if !violatingSet.Has(ns) {
+			return false
+		}
+
+		klog.InfoS("Namespace reported as both violating and inconclusive, keeping it as violating", "namespace", ns)
+		return true
+	})
+}
+
+// makeCondition leaves LastTransitionTime unset, v1helpers.UpdateConditionFn
+// only sets it when the status of the condition changes.
 func makeCondition(conditionType, conditionReason string, namespaces []string) operatorv1.OperatorCondition {
 	var messageFormatter string
 
@@ -76,6 +675,24 @@ func makeCondition(conditionType, conditionReason string, namespaces []string) o
 		messageFormatter = "Violations detected in namespaces: %v"
 	case inconclusiveReason:
 		messageFormatter = "Could not evaluate violations for namespaces: %v"
+	case staleReason:
+		messageFormatter = "Pod security annotation disagrees with the alert labels in namespaces: %v"
+	case labelFixableReason:
+		messageFormatter = "Violations can be resolved by relaxing the enforce level in namespaces: %v"
+	case blockingReason:
+		messageFormatter = "Violating user workloads need to be changed before any level can be enforced in namespaces: %v"
+	case optedOutReason:
+		messageFormatter = "Evaluation was opted out of in namespaces: %v"
+	case misconfiguredReason:
+		messageFormatter = "Invalid pod security enforce level, enforced as restricted, in namespaces: %v"
+	case volumeOnlyReason:
+		messageFormatter = "Violating user workloads only fail volume checks in namespaces: %v"
+	case failedClosedReason:
+		messageFormatter = "Could not evaluate violations, reporting as violating the namespaces: %v"
+	case syncerPendingReason:
+		messageFormatter = "Pod security labels haven't been synced yet in namespaces: %v"
+	case backedOffReason:
+		messageFormatter = "Evaluation is backed off after repeated errors in namespaces: %v"
 	default:
 		messageFormatter = "Unexpected condition for namespace: %v"
 	}
@@ -83,10 +700,9 @@ func makeCondition(conditionType, conditionReason string, namespaces []string) o
 	if len(namespaces) > 0 {
 		sort.Strings(namespaces)
 		return operatorv1.OperatorCondition{
-			Type:               conditionType,
-			Status:             operatorv1.ConditionTrue,
-			LastTransitionTime: metav1.Now(),
-			Reason:             conditionReason,
+			Type:   conditionType,
+			Status: operatorv1.ConditionTrue,
+			Reason: conditionReason,
 			Message: fmt.Sprintf(
 				messageFormatter,
 				namespaces,
@@ -95,19 +711,725 @@ func makeCondition(conditionType, conditionReason string, namespaces []string) o
 	}
 
 	return operatorv1.OperatorCondition{
-		Type:               conditionType,
-		Status:             operatorv1.ConditionFalse,
-		LastTransitionTime: metav1.Now(),
-		Reason:             "ExpectedReason",
+		Type:   conditionType,
+		Status: operatorv1.ConditionFalse,
+		Reason: expectedReason,
+	}
+}
+
+// appendLevels groups the namespaces of a raised condition by their level,
+// strictest level first, and adds them to the message after the description.
+func appendLevels(condition operatorv1.OperatorCondition, description string, namespaces []string, levels map[string]string) operatorv1.OperatorCondition {
+	if condition.Status != operatorv1.ConditionTrue {
+		return condition
+	}
+
+	byLevel := map[string][]string{}
+	for _, ns := range namespaces {
+		if level, ok := levels[ns]; ok {
+			byLevel[level] = append(byLevel[level], ns)
+		}
+	}
+	if len(byLevel) == 0 {
+		return condition
+	}
+
+	evaluatedLevels := slices.Collect(maps.Keys(byLevel))
+	sort.Slice(evaluatedLevels, func(i, j int) bool {
+		return psapi.CompareLevels(psapi.Level(evaluatedLevels[i]), psapi.Level(evaluatedLevels[j])) > 0
+	})
+
+	grouped := make([]string, 0, len(evaluatedLevels))
+	for _, level := range evaluatedLevels {
+		sort.Strings(byLevel[level])
+		grouped = append(grouped, fmt.Sprintf("%s: %v", level, byLevel[level]))
+	}
+	condition.Message += fmt.Sprintf("; %s %s", description, strings.Join(grouped, ", "))
+
+	return condition
+}
+
+// appendWorkloadKinds adds the kinds of the workloads that own violating pods
+// in the given namespaces to the message of a raised condition, to point at
+// what has to be reconfigured.
+func appendWorkloadKinds(condition operatorv1.OperatorCondition, namespaces []string, workloadKinds map[string][]string) operatorv1.OperatorCondition {
+	if condition.Status != operatorv1.ConditionTrue {
+		return condition
+	}
+
+	kinds := sets.New[string]()
+	for _, ns := range namespaces {
+		kinds.Insert(workloadKinds[ns]...)
+	}
+	if kinds.Len() == 0 {
+		return condition
+	}
+
+	condition.Message += fmt.Sprintf("; violating workloads: %s", strings.Join(sets.List(kinds), ", "))
+
+	return condition
+}
+
+// appendCheckFamilies groups the namespaces of a raised condition by the family
+// of the checks their pods fail most often, and adds them to the message.
+func appendCheckFamilies(condition operatorv1.OperatorCondition, namespaces []string, checkFamilies map[string]string) operatorv1.OperatorCondition {
+	if condition.Status != operatorv1.ConditionTrue {
+		return condition
+	}
+
+	byFamily := map[string][]string{}
+	for _, ns := range namespaces {
+		if family, ok := checkFamilies[ns]; ok {
+			byFamily[family] = append(byFamily[family], ns)
+		}
+	}
+	if len(byFamily) == 0 {
+		return condition
+	}
+
+	grouped := make([]string, 0, len(byFamily))
+	for _, family := range slices.Sorted(maps.Keys(byFamily)) {
+		sort.Strings(byFamily[family])
+		grouped = append(grouped, fmt.Sprintf("%s: %v", family, byFamily[family]))
+	}
+	condition.Message += fmt.Sprintf("; most common violations %s", strings.Join(grouped, ", "))
+
+	return condition
+}
+
+// appendViolatingSince adds the namespaces that have been violating the
+// longest to the message of a raised condition, along with since when they are
+// violating. Only fixed points in time are reported, so that the message
+// doesn't change from one sync to the next while the violations persist.
+func appendViolatingSince(condition operatorv1.OperatorCondition, namespaces []string, since map[string]time.Time) operatorv1.OperatorCondition {
+	if condition.Status != operatorv1.ConditionTrue {
+		return condition
+	}
+
+	oldest := []string{}
+	for _, ns := range namespaces {
+		if _, ok := since[ns]; ok {
+			oldest = append(oldest, ns)
+		}
+	}
+	if len(oldest) == 0 {
+		return condition
+	}
+
+	sort.Slice(oldest, func(i, j int) bool {
+		if !since[oldest[i]].Equal(since[oldest[j]]) {
+			return since[oldest[i]].Before(since[oldest[j]])
+		}
+		return oldest[i] < oldest[j]
+	})
+	if len(oldest) > maxReportedViolatingSince {
+		oldest = oldest[:maxReportedViolatingSince]
+	}
+
+	reported := make([]string, 0, len(oldest))
+	for _, ns := range oldest {
+		reported = append(reported, fmt.Sprintf("%s (since %s)", ns, since[ns].UTC().Format(time.RFC3339)))
+	}
+	condition.Message += fmt.Sprintf("; violating the longest: %s", strings.Join(reported, ", "))
+
+	return condition
+}
+
+// makeRunLevelZeroEscalationCondition returns the condition selected by the
+// escalation for run-level zero violations, which is only raised while
+// run-level zero namespaces are violating. There is no condition without an
+// escalation.
+func makeRunLevelZeroEscalationCondition(escalation RunLevelZeroEscalation, namespaces []string) (operatorv1.OperatorCondition, bool) {
+	var condition operatorv1.OperatorCondition
+	switch escalation {
+	case RunLevelZeroEscalationUpgradeable:
+		condition = operatorv1.OperatorCondition{
+			Type:   PodSecurityRunLevelZeroUpgradeableType,
+			Status: operatorv1.ConditionTrue,
+			Reason: expectedReason,
+		}
+	case RunLevelZeroEscalationDegraded:
+		condition = operatorv1.OperatorCondition{
+			Type:   PodSecurityRunLevelZeroDegradedType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	default:
+		return operatorv1.OperatorCondition{}, false
+	}
+
+	if len(namespaces) > 0 {
+		sort.Strings(namespaces)
+		condition.Status = operatorv1.ConditionTrue
+		if escalation == RunLevelZeroEscalationUpgradeable {
+			condition.Status = operatorv1.ConditionFalse
+		}
+		condition.Reason = violationReason
+		condition.Message = fmt.Sprintf("Violations detected in run-level zero namespaces: %v", namespaces)
+	}
+
+	return condition, true
+}
+
+// makeCollapsedInconclusiveCondition reports the namespaces that couldn't be
+// evaluated completely, either whether they violate or whether their violating
+// workloads were admitted through a user-bound SCC, grouped by category.
+func (c *podSecurityOperatorConditions) makeCollapsedInconclusiveCondition() operatorv1.OperatorCondition {
+	byCategory := map[namespaceCategory]sets.Set[string]{}
+	for _, ns := range slices.Concat(c.inconclusiveNamespaces, c.userSCCInconclusiveNamespaces) {
+		category := c.inconclusiveCategories[ns]
+		if byCategory[category] == nil {
+			byCategory[category] = sets.New[string]()
+		}
+		byCategory[category].Insert(ns)
+	}
+
+	if len(byCategory) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityEvaluationInconclusiveType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	}
+
+	grouped := make([]string, 0, len(byCategory))
+	for _, category := range slices.Sorted(maps.Keys(byCategory)) {
+		grouped = append(grouped, fmt.Sprintf("%s: %v", category, sets.List(byCategory[category])))
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityEvaluationInconclusiveType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  inconclusiveReason,
+		Message: fmt.Sprintf("Could not evaluate violations for namespaces: %s", strings.Join(grouped, ", ")),
 	}
 }
 
+// makeViolationCondition makes the condition of a violation category, detailing
+// the achievable levels of its namespaces, if they were probed. With violation
+// details, it also details their evaluated levels and since when they are
+// violating.
+func (c *podSecurityOperatorConditions) makeViolationCondition(conditionType string, namespaces []string) operatorv1.OperatorCondition {
+	condition := makeCondition(conditionType, violationReason, namespaces)
+	if c.violationDetails {
+		condition = appendLevels(condition, "evaluated against", namespaces, c.evaluatedLevels)
+	}
+	condition = appendLevels(condition, "strictest achievable", namespaces, c.achievableLevels)
+	if c.violationDetails {
+		condition = appendViolatingSince(condition, namespaces, c.violatingSince)
+	}
+	return condition
+}
+
+// addClean counts a namespace that doesn't violate the level it was evaluated
+// against.
+func (c *podSecurityOperatorConditions) addClean(level string) {
+	if c.cleanCounts == nil {
+		c.cleanCounts = map[string]int{}
+	}
+	c.cleanCounts[level]++
+}
+
+// addLabelFixable records a violating namespace that would satisfy baseline,
+// or whose pods that violate baseline weren't admitted through a user-bound
+// SCC, so that it can be fixed by relabeling.
+func (c *podSecurityOperatorConditions) addLabelFixable(ns *corev1.Namespace) {
+	c.labelFixableNamespaces = append(c.labelFixableNamespaces, ns.Name)
+}
+
+// addWorkloadBlocking records a violating namespace in which pods admitted
+// through a user-bound SCC would be rejected at any enforceable level.
+func (c *podSecurityOperatorConditions) addWorkloadBlocking(ns *corev1.Namespace) {
+	c.workloadBlockingNamespaces = append(c.workloadBlockingNamespaces, ns.Name)
+}
+
 func (c *podSecurityOperatorConditions) toConditionFuncs() []v1helpers.UpdateStatusFunc {
-	return []v1helpers.UpdateStatusFunc{
-		v1helpers.UpdateConditionFn(makeCondition(PodSecurityCustomerType, violationReason, c.violatingCustomerNamespaces)),
-		v1helpers.UpdateConditionFn(makeCondition(PodSecurityOpenshiftType, violationReason, c.violatingOpenShiftNamespaces)),
-		v1helpers.UpdateConditionFn(makeCondition(PodSecurityRunLevelZeroType, violationReason, c.violatingRunLevelZeroNamespaces)),
-		v1helpers.UpdateConditionFn(makeCondition(PodSecurityDisabledSyncerType, violationReason, c.violatingDisabledSyncerNamespaces)),
-		v1helpers.UpdateConditionFn(makeCondition(PodSecurityInconclusiveType, inconclusiveReason, c.inconclusiveNamespaces)),
+	conditions := []operatorv1.OperatorCondition{
+		appendWorkloadKinds(c.makeViolationCondition(PodSecurityCustomerType, c.violatingCustomerNamespaces), c.violatingCustomerNamespaces, c.workloadKinds),
+		c.makeViolationCondition(PodSecurityOpenshiftType, c.violatingOpenShiftNamespaces),
+		c.makeViolationCondition(PodSecurityRunLevelZeroType, c.violatingRunLevelZeroNamespaces),
+		c.makeViolationCondition(PodSecurityDisabledSyncerType, c.violatingDisabledSyncerNamespaces),
+		c.makeViolationCondition(PodSecurityAddOnType, c.violatingAddOnNamespaces),
+		makeInitializingCondition(false),
+		makeFullyEnforcedCondition(c.fullyEnforced),
+	}
+	if c.enforcedNamespacesAudited {
+		conditions = append(conditions, makeCondition(PodSecurityEnforcedRegressionType, violationReason, c.regressedEnforcingNamespaces))
+	}
+	if c.backOffEnabled {
+		conditions = append(conditions, makeCondition(PodSecurityBackedOffType, backedOffReason, c.backedOffNamespaces))
+	}
+	if c.summaries {
+		conditions = append(conditions,
+			makeCleanCondition(c.cleanCounts),
+			makeCountsCondition(newCategoryCounts(c)),
+			makeViolationsSummaryCondition(newCategoryCounts(c)),
+		)
+	}
+	if c.diagnostics {
+		conditions = append(conditions,
+			makeCondition(PodSecurityStaleAnnotationType, staleReason, c.staleAnnotationNamespaces),
+			makeCondition(PodSecurityOptedOutType, optedOutReason, c.optedOutNamespaces),
+			makeCondition(PodSecuritySyncerPendingType, syncerPendingReason, c.syncerPendingNamespaces),
+		)
+	}
+	if condition, ok := makeRunLevelZeroEscalationCondition(c.runLevelZeroEscalation, c.violatingRunLevelZeroNamespaces); ok {
+		conditions = append(conditions, condition)
+	}
+	conditions = append(conditions, makeDryRunDegradedCondition(c.dryRunFailure), makeListDegradedCondition(c.listFailure))
+	if c.warningHeartbeat {
+		conditions = append(conditions, makeWarningsDegradedCondition(c.warningsDropped))
+	}
+	if c.degradedThresholds != (DegradedThresholds{}) {
+		conditions = append(conditions, c.makeThresholdDegradedCondition())
+	}
+	if c.remediationClassified {
+		conditions = append(conditions,
+			makeCondition(PodSecurityLabelFixableType, labelFixableReason, c.labelFixableNamespaces),
+			makeCondition(PodSecurityWorkloadBlockingType, blockingReason, c.workloadBlockingNamespaces),
+		)
+	}
+	if c.collapseInconclusive {
+		conditions = append(conditions, c.makeCollapsedInconclusiveCondition())
+	} else {
+		conditions = append(conditions, makeCondition(PodSecurityInconclusiveType, inconclusiveReason, c.inconclusiveNamespaces))
+	}
+	if c.failClosed {
+		conditions = append(conditions, makeCondition(PodSecurityFailedClosedType, failedClosedReason, c.failedClosedNamespaces))
+	}
+	if c.misconfigurationChecked {
+		conditions = append(conditions, makeCondition(PodSecurityMisconfiguredType, misconfiguredReason, c.misconfiguredNamespaces))
+	}
+	if !c.userSCCSkipped {
+		conditions = append(conditions,
+			appendCheckFamilies(c.makeViolationCondition(PodSecurityUserSCCType, c.userSCCViolatingNamespaces), c.userSCCViolatingNamespaces, c.checkFamilies),
+		)
+		if c.diagnostics {
+			conditions = append(conditions, makeCondition(PodSecurityVolumeOnlyType, volumeOnlyReason, c.volumeOnlyNamespaces))
+		}
+		if !c.collapseInconclusive {
+			conditions = append(conditions, makeCondition(PodSecurityUserSCCInconclusiveType, inconclusiveReason, c.userSCCInconclusiveNamespaces))
+		}
+	}
+
+	conditionFuncs := make([]v1helpers.UpdateStatusFunc, 0, len(conditions)+5)
+	for _, condition := range conditions {
+		if c.informational && isGatingConditionType(condition.Type) {
+			conditionFuncs = append(conditionFuncs, removeConditionFn(condition.Type))
+			continue
+		}
+		if c.terse && condition.Reason == expectedReason {
+			conditionFuncs = append(conditionFuncs, removeConditionFn(condition.Type))
+			continue
+		}
+
+		if condition.Status == operatorv1.ConditionTrue && condition.Reason == violationReason {
+			conditionFuncs = append(conditionFuncs, updateViolationConditionFn(c.withCategoryReason(condition)))
+			continue
+		}
+
+		conditionFuncs = append(conditionFuncs, v1helpers.UpdateConditionFn(c.withCategoryReason(condition)))
+	}
+
+	if !c.enforcedNamespacesAudited {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityEnforcedRegressionType))
+	}
+	if !c.backOffEnabled {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityBackedOffType))
+	}
+	if !c.summaries {
+		conditionFuncs = append(conditionFuncs,
+			removeConditionFn(PodSecurityCleanType),
+			removeConditionFn(PodSecurityCountsType),
+			removeConditionFn(PodSecurityViolationsSummaryType),
+		)
+	}
+	if !c.diagnostics {
+		conditionFuncs = append(conditionFuncs,
+			removeConditionFn(PodSecurityStaleAnnotationType),
+			removeConditionFn(PodSecurityOptedOutType),
+			removeConditionFn(PodSecuritySyncerPendingType),
+			removeConditionFn(PodSecurityVolumeOnlyType),
+		)
+	}
+	// The outcome of the last heartbeat is kept if it couldn't be performed.
+	if !c.warningHeartbeat && !c.warningHeartbeatFailed {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityWarningsDegradedType))
+	}
+	if !c.remediationClassified {
+		conditionFuncs = append(conditionFuncs,
+			removeConditionFn(PodSecurityLabelFixableType),
+			removeConditionFn(PodSecurityWorkloadBlockingType),
+		)
+	}
+	if !c.misconfigurationChecked {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityMisconfiguredType))
+	}
+	if !c.failClosed {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityFailedClosedType))
+	}
+	if c.collapseInconclusive {
+		conditionFuncs = append(conditionFuncs,
+			removeConditionFn(PodSecurityInconclusiveType),
+			removeConditionFn(PodSecurityUserSCCInconclusiveType),
+		)
+	} else {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityEvaluationInconclusiveType))
+	}
+	if c.userSCCSkipped {
+		conditionFuncs = append(conditionFuncs,
+			removeConditionFn(PodSecurityUserSCCType),
+			removeConditionFn(PodSecurityUserSCCInconclusiveType),
+		)
+		if c.diagnostics {
+			conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityVolumeOnlyType))
+		}
+	}
+	if c.degradedThresholds == (DegradedThresholds{}) {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityThresholdDegradedType))
+	}
+	if c.runLevelZeroEscalation != RunLevelZeroEscalationUpgradeable {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityRunLevelZeroUpgradeableType))
+	}
+	if c.runLevelZeroEscalation != RunLevelZeroEscalationDegraded {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityRunLevelZeroDegradedType))
+	}
+
+	if c.blockUpgradeOnCustomerViolations && !c.informational && len(c.violatingCustomerNamespaces) > 0 {
+		conditionFuncs = append(conditionFuncs, v1helpers.UpdateConditionFn(c.withCategoryReason(makeCustomerUpgradeableCondition(c.violatingCustomerNamespaces))))
+	} else {
+		conditionFuncs = append(conditionFuncs, removeConditionFn(PodSecurityCustomerUpgradeableType))
+	}
+
+	return conditionFuncs
+}
+
+// toDryRunConditionFuncs only reports whether namespaces can be evaluated,
+// leaving the violation conditions of the previous evaluation untouched.
+func (c *podSecurityOperatorConditions) toDryRunConditionFuncs() []v1helpers.UpdateStatusFunc {
+	return c.toSingleConditionFuncs(makeDryRunDegradedCondition(c.dryRunFailure))
+}
+
+// toListConditionFuncs only reports that the namespaces couldn't be listed,
+// leaving the conditions of the last complete evaluation in place.
+func (c *podSecurityOperatorConditions) toListConditionFuncs() []v1helpers.UpdateStatusFunc {
+	return c.toSingleConditionFuncs(makeListDegradedCondition(c.listFailure))
+}
+
+// toSyncHealthyConditionFuncs only reports whether the controller is able to
+// complete its syncs. The condition replaces an Available condition, which
+// the status controller merged into the Available status of the operator.
+func (c *podSecurityOperatorConditions) toSyncHealthyConditionFuncs() []v1helpers.UpdateStatusFunc {
+	return append(
+		c.toSingleConditionFuncs(makeSyncHealthyCondition(c.syncFailure, c.failingSince)),
+		removeConditionFn(legacyReadinessAvailableType),
+	)
+}
+
+// toInitializingConditionFuncs only reports that no evaluation has completed
+// yet, so that the absence of the other conditions isn't mistaken for no
+// violations.
+func (c *podSecurityOperatorConditions) toInitializingConditionFuncs() []v1helpers.UpdateStatusFunc {
+	return c.toSingleConditionFuncs(makeInitializingCondition(true))
+}
+
+func (c *podSecurityOperatorConditions) toSingleConditionFuncs(condition operatorv1.OperatorCondition) []v1helpers.UpdateStatusFunc {
+	if (c.terse && condition.Reason == expectedReason) || (c.informational && isGatingConditionType(condition.Type)) {
+		return []v1helpers.UpdateStatusFunc{removeConditionFn(condition.Type)}
+	}
+
+	return []v1helpers.UpdateStatusFunc{v1helpers.UpdateConditionFn(condition)}
+}
+
+// makeDryRunDegradedCondition degrades the operator if namespaces can't be
+// evaluated because the dry-run Apply is forbidden, which would otherwise be
+// indistinguishable from no violations. The failure is only set once it
+// persisted for longer than syncFailureTolerance.
+func makeDryRunDegradedCondition(failure string) operatorv1.OperatorCondition {
+	if len(failure) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityDryRunDegradedType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityDryRunDegradedType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  dryRunFailedReason,
+		Message: fmt.Sprintf("Unable to evaluate pod security violations with a dry-run Apply: %s", failure),
+	}
+}
+
+// makeListDegradedCondition degrades the operator if the namespaces can't be
+// listed. Without the list nothing is evaluated, which must not be mistaken
+// for no violations. The failure is only set once it persisted for longer
+// than syncFailureTolerance.
+func makeListDegradedCondition(failure string) operatorv1.OperatorCondition {
+	if len(failure) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityNamespaceListDegradedType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityNamespaceListDegradedType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  listFailedReason,
+		Message: fmt.Sprintf("Unable to list namespaces to evaluate pod security violations: %s", failure),
+	}
+}
+
+// makeSyncHealthyCondition reports the syncs of the controller as unhealthy if
+// they keep failing, in which case all other conditions are stale. It is
+// informational only, so that the operator stays available.
+func makeSyncHealthyCondition(failure string, since time.Time) operatorv1.OperatorCondition {
+	if len(failure) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityReadinessSyncHealthyType,
+			Status: operatorv1.ConditionTrue,
+			Reason: expectedReason,
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityReadinessSyncHealthyType,
+		Status:  operatorv1.ConditionFalse,
+		Reason:  syncFailedReason,
+		Message: fmt.Sprintf("Unable to evaluate pod security violations since %s: %s", since.UTC().Format(time.RFC3339), failure),
+	}
+}
+
+// makeInitializingCondition reports whether the controller is still waiting for
+// the first complete evaluation since it started.
+func makeInitializingCondition(initializing bool) operatorv1.OperatorCondition {
+	if !initializing {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityReadinessInitializingType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityReadinessInitializingType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  initializingReason,
+		Message: "The namespaces have not been evaluated for pod security violations yet",
+	}
+}
+
+// makeFullyEnforcedCondition confirms that the migration to pod security
+// admission is complete once an evaluation found no namespace without an
+// enforced level. Unlike the violation conditions, which are False as well
+// before the first evaluation, it is only raised by a completed evaluation.
+func makeFullyEnforcedCondition(fullyEnforced bool) operatorv1.OperatorCondition {
+	if !fullyEnforced {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityFullyEnforcedType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityFullyEnforcedType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  fullyEnforcedReason,
+		Message: "Pod security admission enforces a level in all namespaces, none is left to evaluate",
+	}
+}
+
+// makeWarningsDegradedCondition degrades the operator if the heartbeat warning
+// wasn't captured, in which case violations can't be detected.
+func makeWarningsDegradedCondition(dropped bool) operatorv1.OperatorCondition {
+	if !dropped {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityWarningsDegradedType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityWarningsDegradedType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  warningsLostReason,
+		Message: "Warnings returned by the apiserver are not captured, pod security violations can't be detected",
+	}
+}
+
+// makeThresholdDegradedCondition degrades the operator if the violating
+// namespaces of any category reach the threshold of that category.
+func (c *podSecurityOperatorConditions) makeThresholdDegradedCondition() operatorv1.OperatorCondition {
+	var exceeded []string
+	for _, category := range []struct {
+		name       string
+		namespaces []string
+		threshold  int
+	}{
+		{name: "customer", namespaces: c.violatingCustomerNamespaces, threshold: c.degradedThresholds.Customer},
+		{name: "openshift", namespaces: c.violatingOpenShiftNamespaces, threshold: c.degradedThresholds.OpenShift},
+		{name: "run-level zero", namespaces: c.violatingRunLevelZeroNamespaces, threshold: c.degradedThresholds.RunLevelZero},
+	} {
+		if category.threshold > 0 && len(category.namespaces) >= category.threshold {
+			exceeded = append(exceeded, fmt.Sprintf("%s (%d, threshold %d)", category.name, len(category.namespaces), category.threshold))
+		}
+	}
+
+	if len(exceeded) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityThresholdDegradedType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityThresholdDegradedType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  thresholdReason,
+		Message: fmt.Sprintf("Too many namespaces with pod security violations: %s", strings.Join(exceeded, ", ")),
+	}
+}
+
+// makeCleanCondition reports how many namespaces passed at each level,
+// strictest level first. Only the counts are reported, as the list of clean
+// namespaces can grow much longer than the condition message should.
+func makeCleanCondition(cleanCounts map[string]int) operatorv1.OperatorCondition {
+	if len(cleanCounts) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityCleanType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	}
+
+	levels := slices.Collect(maps.Keys(cleanCounts))
+	sort.Slice(levels, func(i, j int) bool {
+		return psapi.CompareLevels(psapi.Level(levels[i]), psapi.Level(levels[j])) > 0
+	})
+
+	counts := make([]string, 0, len(levels))
+	for _, level := range levels {
+		counts = append(counts, fmt.Sprintf("%s: %d", level, cleanCounts[level]))
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityCleanType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  cleanReason,
+		Message: fmt.Sprintf("Namespaces without violations per evaluated level: %s", strings.Join(counts, ", ")),
+	}
+}
+
+// makeCountsCondition reports the number of namespaces of every category as
+// JSON, to be parsed by telemetry without exposing any namespace names. It is
+// reported in terse mode as well.
+func makeCountsCondition(counts CategoryCounts) operatorv1.OperatorCondition {
+	// Marshalling a struct of integers can't fail.
+	message, _ := json.Marshal(counts)
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityCountsType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  countsReason,
+		Message: string(message),
+	}
+}
+
+// makeViolationsSummaryCondition rolls the populated categories up into a
+// single line, so that dashboards don't have to combine the conditions of all
+// categories. Clean and opted out namespaces aren't findings and are left out.
+func makeViolationsSummaryCondition(counts CategoryCounts) operatorv1.OperatorCondition {
+	keysAndValues := counts.keysAndValues()
+
+	populated := []string{}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		category, count := keysAndValues[i].(string), keysAndValues[i+1].(int)
+		if count == 0 || category == "clean" || category == "optedOut" {
+			continue
+		}
+		populated = append(populated, fmt.Sprintf("%s: %d", category, count))
+	}
+
+	if len(populated) == 0 {
+		return operatorv1.OperatorCondition{
+			Type:   PodSecurityViolationsSummaryType,
+			Status: operatorv1.ConditionFalse,
+			Reason: expectedReason,
+		}
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:    PodSecurityViolationsSummaryType,
+		Status:  operatorv1.ConditionTrue,
+		Reason:  summaryReason,
+		Message: fmt.Sprintf("Pod security findings by category: %s", strings.Join(populated, ", ")),
+	}
+}
+
+// makeCustomerUpgradeableCondition blocks upgrades, which could enable pod
+// security admission enforcement, while customer namespaces are violating.
+func makeCustomerUpgradeableCondition(namespaces []string) operatorv1.OperatorCondition {
+	sort.Strings(namespaces)
+	return operatorv1.OperatorCondition{
+		Type:   PodSecurityCustomerUpgradeableType,
+		Status: operatorv1.ConditionFalse,
+		Reason: violationReason,
+		Message: fmt.Sprintf(
+			"Upgrades are blocked until pod security violations are resolved in namespaces: %v",
+			namespaces,
+		),
+	}
+}
+
+// withCategoryReason replaces violationReason with the reason of the category
+// of the condition, if category reasons are enabled.
+func (c *podSecurityOperatorConditions) withCategoryReason(condition operatorv1.OperatorCondition) operatorv1.OperatorCondition {
+	if !c.categoryReasons || condition.Reason != violationReason {
+		return condition
+	}
+
+	if reason, ok := categoryViolationReasons[condition.Type]; ok {
+		condition.Reason = reason
+	}
+	return condition
+}
+
+// updateViolationConditionFn sets a raised violation condition and states in
+// its message since when the violations are detected, which is the transition
+// time of the condition. Newly detected violations are told apart from
+// persistent ones by that time, so the reason and message don't change while
+// the violations persist.
+func updateViolationConditionFn(condition operatorv1.OperatorCondition) v1helpers.UpdateStatusFunc {
+	return func(oldStatus *operatorv1.OperatorStatus) error {
+		v1helpers.SetOperatorCondition(&oldStatus.Conditions, condition)
+
+		updated := v1helpers.FindOperatorCondition(oldStatus.Conditions, condition.Type)
+		updated.Message += fmt.Sprintf("; first detected at %s", updated.LastTransitionTime.UTC().Format(time.RFC3339))
+		return nil
+	}
+}
+
+// isGatingConditionType checks whether the status controller aggregates the
+// condition into the Degraded, Upgradeable or Available condition of the
+// ClusterOperator, which it does by the suffix of the type.
+func isGatingConditionType(conditionType string) bool {
+	for _, suffix := range []string{"Degraded", "Upgradeable", "Available"} {
+		if strings.HasSuffix(conditionType, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func removeConditionFn(conditionType string) v1helpers.UpdateStatusFunc {
+	return func(oldStatus *operatorv1.OperatorStatus) error {
+		v1helpers.RemoveOperatorCondition(&oldStatus.Conditions, conditionType)
+		return nil
 	}
 }
@@ -1,11 +1,20 @@
 package podsecurityreadinesscontroller
 
 import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/utils/ptr"
 )
 
 func TestCondition(t *testing.T) {
@@ -65,6 +74,18 @@ func TestCondition(t *testing.T) {
 		}
 	})
 
+	t.Run("with stale annotations", func(t *testing.T) {
+		condition := makeCondition(PodSecurityStaleAnnotationType, staleReason, []string{"namespace2", "namespace1"})
+
+		expectedMessage := "Pod security annotation disagrees with the alert labels in namespaces: [namespace1 namespace2]"
+		if condition.Status != operatorv1.ConditionTrue {
+			t.Errorf("expected condition status %s, got %s", operatorv1.ConditionTrue, condition.Status)
+		}
+		if condition.Message != expectedMessage {
+			t.Errorf("expected condition message %s, got %s", expectedMessage, condition.Message)
+		}
+	})
+
 	t.Run("without namespaces", func(t *testing.T) {
 		namespaces := []string{}
 		expectedCondition := operatorv1.OperatorCondition{
@@ -313,3 +334,1120 @@ func TestOperatorStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestRunLevelZeroEscalation(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		escalation RunLevelZeroEscalation
+		namespace  string
+		// expected maps the escalation conditions to their status, or to
+		// an empty status if they mustn't be reported.
+		expected map[string]operatorv1.ConditionStatus
+	}{
+		{
+			name:       "upgradeable escalation with violating run-level 0 namespace",
+			escalation: RunLevelZeroEscalationUpgradeable,
+			namespace:  "kube-system",
+			expected: map[string]operatorv1.ConditionStatus{
+				PodSecurityRunLevelZeroUpgradeableType: operatorv1.ConditionFalse,
+				PodSecurityRunLevelZeroDegradedType:    "",
+			},
+		},
+		{
+			name:       "degraded escalation with violating run-level 0 namespace",
+			escalation: RunLevelZeroEscalationDegraded,
+			namespace:  "default",
+			expected: map[string]operatorv1.ConditionStatus{
+				PodSecurityRunLevelZeroUpgradeableType: "",
+				PodSecurityRunLevelZeroDegradedType:    operatorv1.ConditionTrue,
+			},
+		},
+		{
+			name:       "no escalation with violating run-level 0 namespace",
+			escalation: RunLevelZeroEscalationNone,
+			namespace:  "kube-public",
+			expected: map[string]operatorv1.ConditionStatus{
+				PodSecurityRunLevelZeroUpgradeableType: "",
+				PodSecurityRunLevelZeroDegradedType:    "",
+			},
+		},
+		{
+			name:       "upgradeable escalation with violating customer namespace",
+			escalation: RunLevelZeroEscalationUpgradeable,
+			namespace:  "customer",
+			expected: map[string]operatorv1.ConditionStatus{
+				PodSecurityRunLevelZeroUpgradeableType: operatorv1.ConditionTrue,
+				PodSecurityRunLevelZeroDegradedType:    "",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cond := podSecurityOperatorConditions{
+				runLevelZeroEscalation: tt.escalation,
+			}
+			cond.addViolation(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: tt.namespace},
+			})
+
+			// Conditions of a previous escalation are removed.
+			status := &operatorv1.OperatorStatus{
+				Conditions: []operatorv1.OperatorCondition{
+					{Type: PodSecurityRunLevelZeroUpgradeableType, Status: operatorv1.ConditionTrue, Reason: expectedReason},
+					{Type: PodSecurityRunLevelZeroDegradedType, Status: operatorv1.ConditionFalse, Reason: expectedReason},
+				},
+			}
+			for _, f := range cond.toConditionFuncs() {
+				if err := f(status); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			for expectedType, expectedStatus := range tt.expected {
+				condition := v1helpers.FindOperatorCondition(status.Conditions, expectedType)
+				if len(expectedStatus) == 0 {
+					if condition != nil {
+						t.Errorf("expected condition %s to be removed, have %v", expectedType, condition)
+					}
+					continue
+				}
+				if condition == nil {
+					t.Errorf("expected condition %s not found", expectedType)
+					continue
+				}
+				if condition.Status != expectedStatus {
+					t.Errorf("expected %s to be %v, have %v", expectedType, expectedStatus, condition.Status)
+				}
+			}
+		})
+	}
+}
+
+func TestTerseConditions(t *testing.T) {
+	status := &operatorv1.OperatorStatus{
+		Conditions: []operatorv1.OperatorCondition{
+			{Type: PodSecurityOpenshiftType, Status: operatorv1.ConditionTrue, Reason: violationReason},
+			{Type: "UnrelatedDegraded", Status: operatorv1.ConditionFalse},
+		},
+	}
+
+	cond := podSecurityOperatorConditions{
+		runLevelZeroEscalation: RunLevelZeroEscalationUpgradeable,
+		terse:                  true,
+		summaries:              true,
+	}
+	cond.addViolation(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "customer"}})
+
+	for _, f := range cond.toConditionFuncs() {
+		if err := f(status); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	actual := []string{}
+	for _, condition := range status.Conditions {
+		actual = append(actual, condition.Type)
+	}
+	sort.Strings(actual)
+
+	// The counts are reported even if they are empty, for telemetry.
+	expected := []string{PodSecurityCustomerType, PodSecurityCountsType, PodSecurityViolationsSummaryType, "UnrelatedDegraded"}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected conditions %v, got %v", expected, actual)
+	}
+}
+
+func TestInformationalConditions(t *testing.T) {
+	status := &operatorv1.OperatorStatus{
+		Conditions: []operatorv1.OperatorCondition{
+			{Type: PodSecurityRunLevelZeroDegradedType, Status: operatorv1.ConditionTrue, Reason: violationReason},
+			{Type: PodSecurityCustomerUpgradeableType, Status: operatorv1.ConditionFalse, Reason: violationReason},
+			{Type: "UnrelatedDegraded", Status: operatorv1.ConditionFalse},
+		},
+	}
+
+	cond := podSecurityOperatorConditions{
+		runLevelZeroEscalation:           RunLevelZeroEscalationDegraded,
+		degradedThresholds:               DegradedThresholds{Customer: 1, RunLevelZero: 1},
+		blockUpgradeOnCustomerViolations: true,
+		warningHeartbeat:                 true,
+		warningsDropped:                  true,
+		dryRunFailure:                    "dry run failed",
+		listFailure:                      "list failed",
+		syncFailure:                      "sync failed",
+		informational:                    true,
+	}
+	cond.addViolation(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "customer"}})
+	cond.addViolation(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}})
+
+	updateFuncs := slices.Concat(
+		cond.toConditionFuncs(),
+		cond.toDryRunConditionFuncs(),
+		cond.toListConditionFuncs(),
+		cond.toSyncHealthyConditionFuncs(),
+	)
+	for _, f := range updateFuncs {
+		if err := f(status); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for _, condition := range status.Conditions {
+		if condition.Type != "UnrelatedDegraded" && isGatingConditionType(condition.Type) {
+			t.Errorf("expected no gating conditions, got %v", condition)
+		}
+	}
+	for _, conditionType := range []string{PodSecurityCustomerType, PodSecurityRunLevelZeroType, "UnrelatedDegraded"} {
+		if v1helpers.FindOperatorCondition(status.Conditions, conditionType) == nil {
+			t.Errorf("expected condition %s to be kept", conditionType)
+		}
+	}
+}
+
+func TestFeatureConditions(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		enable func(c *podSecurityOperatorConditions)
+
+		conditionTypes []string
+	}{
+		{
+			name:           "enforced namespace audit",
+			enable:         func(c *podSecurityOperatorConditions) { c.enforcedNamespacesAudited = true },
+			conditionTypes: []string{PodSecurityEnforcedRegressionType},
+		},
+		{
+			name:           "error backoff",
+			enable:         func(c *podSecurityOperatorConditions) { c.backOffEnabled = true },
+			conditionTypes: []string{PodSecurityBackedOffType},
+		},
+		{
+			name:           "summaries",
+			enable:         func(c *podSecurityOperatorConditions) { c.summaries = true },
+			conditionTypes: []string{PodSecurityCleanType, PodSecurityCountsType, PodSecurityViolationsSummaryType},
+		},
+		{
+			name:           "diagnostics",
+			enable:         func(c *podSecurityOperatorConditions) { c.diagnostics = true },
+			conditionTypes: []string{PodSecurityStaleAnnotationType, PodSecurityOptedOutType, PodSecuritySyncerPendingType, PodSecurityVolumeOnlyType},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &operatorv1.OperatorStatus{}
+			for _, conditionType := range tt.conditionTypes {
+				status.Conditions = append(status.Conditions, operatorv1.OperatorCondition{Type: conditionType, Status: operatorv1.ConditionTrue})
+			}
+
+			for _, enabled := range []bool{true, false} {
+				conditions := podSecurityOperatorConditions{}
+				if enabled {
+					tt.enable(&conditions)
+				}
+				for _, fn := range conditions.toConditionFuncs() {
+					if err := fn(status); err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				}
+
+				for _, conditionType := range tt.conditionTypes {
+					if reported := v1helpers.FindOperatorCondition(status.Conditions, conditionType) != nil; reported != enabled {
+						t.Errorf("enabled %v: expected condition %s reported %v, got %v", enabled, conditionType, enabled, reported)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestViolationsSummaryCondition(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		conditions *podSecurityOperatorConditions
+
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name: "multiple populated categories",
+			conditions: &podSecurityOperatorConditions{
+				violatingCustomerNamespaces:     []string{"customer-a", "customer-b"},
+				violatingRunLevelZeroNamespaces: []string{"kube-system"},
+				inconclusiveNamespaces:          []string{"undetermined-ns"},
+				userSCCViolatingNamespaces:      []string{"customer-a"},
+				optedOutNamespaces:              []string{"accepted-risk-ns"},
+				cleanCounts:                     map[string]int{"restricted": 3},
+				summaries:                       true,
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "Pod security findings by category: customer: 2, runLevelZero: 1, inconclusive: 1, userSCC: 1",
+		},
+		{
+			name: "only clean and opted out namespaces",
+			conditions: &podSecurityOperatorConditions{
+				optedOutNamespaces: []string{"accepted-risk-ns"},
+				cleanCounts:        map[string]int{"restricted": 3},
+				summaries:          true,
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &operatorv1.OperatorStatus{}
+			for _, fn := range tt.conditions.toConditionFuncs() {
+				if err := fn(status); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityViolationsSummaryType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", PodSecurityViolationsSummaryType)
+			}
+			if condition.Status != tt.expectedStatus {
+				t.Errorf("expected condition status %s, got %s", tt.expectedStatus, condition.Status)
+			}
+			if condition.Message != tt.expectedMessage {
+				t.Errorf("expected condition message %q, got %q", tt.expectedMessage, condition.Message)
+			}
+
+			// The detailed conditions are reported as well.
+			if v1helpers.FindOperatorCondition(status.Conditions, PodSecurityCustomerType) == nil {
+				t.Errorf("expected condition %s to be set", PodSecurityCustomerType)
+			}
+		})
+	}
+}
+
+func TestCollapsedInconclusiveCondition(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		collapse bool
+		expected map[string]string
+	}{
+		{
+			name: "split",
+			expected: map[string]string{
+				PodSecurityInconclusiveType:        "Could not evaluate violations for namespaces: [customer openshift-console]",
+				PodSecurityUserSCCInconclusiveType: "Could not evaluate violations for namespaces: [customer-user kube-system]",
+			},
+		},
+		{
+			name:     "collapsed",
+			collapse: true,
+			expected: map[string]string{
+				PodSecurityEvaluationInconclusiveType: "Could not evaluate violations for namespaces: customer: [customer customer-user], runLevelZero: [kube-system], openShift: [openshift-console]",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &operatorv1.OperatorStatus{
+				Conditions: []operatorv1.OperatorCondition{
+					{Type: PodSecurityInconclusiveType, Status: operatorv1.ConditionTrue, Reason: inconclusiveReason},
+					{Type: PodSecurityUserSCCInconclusiveType, Status: operatorv1.ConditionTrue, Reason: inconclusiveReason},
+					{Type: PodSecurityEvaluationInconclusiveType, Status: operatorv1.ConditionTrue, Reason: inconclusiveReason},
+				},
+			}
+
+			cond := podSecurityOperatorConditions{
+				runLevelZeroEscalation: RunLevelZeroEscalationUpgradeable,
+				collapseInconclusive:   tt.collapse,
+			}
+			cond.addInconclusive(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-console"}})
+			cond.addInconclusive(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "customer"}})
+			cond.addUserSCCInconclusive(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}})
+			cond.addUserSCCInconclusive(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "customer-user"}})
+
+			for _, f := range cond.toConditionFuncs() {
+				if err := f(status); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			for _, conditionType := range []string{PodSecurityInconclusiveType, PodSecurityUserSCCInconclusiveType, PodSecurityEvaluationInconclusiveType} {
+				condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+				message, ok := tt.expected[conditionType]
+				if !ok {
+					if condition != nil {
+						t.Errorf("expected condition %s to be removed, got %v", conditionType, condition)
+					}
+					continue
+				}
+				if condition == nil {
+					t.Fatalf("expected condition %s to be reported", conditionType)
+				}
+				if condition.Status != operatorv1.ConditionTrue || condition.Reason != inconclusiveReason {
+					t.Errorf("expected condition %s to be raised with reason %s, got %v", conditionType, inconclusiveReason, condition)
+				}
+				if condition.Message != message {
+					t.Errorf("expected message %q, got %q", message, condition.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestAppendViolatingSince(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	since := map[string]time.Time{
+		"ns-a": start.Add(72 * time.Hour),
+		"ns-b": start,
+		"ns-c": start.Add(71 * time.Hour),
+		"ns-d": start.Add(70 * time.Hour),
+	}
+
+	t.Run("reports the oldest namespaces", func(t *testing.T) {
+		namespaces := []string{"ns-a", "ns-b", "ns-c", "ns-d"}
+		condition := appendViolatingSince(makeCondition(PodSecurityCustomerType, violationReason, namespaces), namespaces, since)
+
+		expected := "Violations detected in namespaces: [ns-a ns-b ns-c ns-d]; violating the longest: ns-b (since 2024-01-01T00:00:00Z), ns-d (since 2024-01-03T22:00:00Z), ns-c (since 2024-01-03T23:00:00Z)"
+		if condition.Message != expected {
+			t.Errorf("expected condition message %q, got %q", expected, condition.Message)
+		}
+	})
+
+	t.Run("ignores namespaces without start", func(t *testing.T) {
+		namespaces := []string{"ns-a", "ns-unknown"}
+		condition := appendViolatingSince(makeCondition(PodSecurityCustomerType, violationReason, namespaces), namespaces, since)
+
+		expected := "Violations detected in namespaces: [ns-a ns-unknown]; violating the longest: ns-a (since 2024-01-04T00:00:00Z)"
+		if condition.Message != expected {
+			t.Errorf("expected condition message %q, got %q", expected, condition.Message)
+		}
+	})
+
+	t.Run("orders namespaces with the same start by name", func(t *testing.T) {
+		namespaces := []string{"ns-z", "ns-y"}
+		same := map[string]time.Time{"ns-y": start, "ns-z": start}
+		condition := appendViolatingSince(makeCondition(PodSecurityCustomerType, violationReason, namespaces), namespaces, same)
+
+		expected := "Violations detected in namespaces: [ns-y ns-z]; violating the longest: ns-y (since 2024-01-01T00:00:00Z), ns-z (since 2024-01-01T00:00:00Z)"
+		if condition.Message != expected {
+			t.Errorf("expected condition message %q, got %q", expected, condition.Message)
+		}
+	})
+
+	t.Run("leaves healthy conditions untouched", func(t *testing.T) {
+		condition := appendViolatingSince(makeCondition(PodSecurityCustomerType, violationReason, nil), nil, since)
+		if condition.Message != "" {
+			t.Errorf("expected empty condition message, got %q", condition.Message)
+		}
+	})
+}
+
+func TestCustomerViolationUpgradeBlock(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		blockUpgrade      bool
+		namespace         string
+		expectedCondition *operatorv1.ConditionStatus
+	}{
+		{
+			name:              "blocks upgrade with violating customer namespace",
+			blockUpgrade:      true,
+			namespace:         "customer",
+			expectedCondition: ptr.To(operatorv1.ConditionFalse),
+		},
+		{
+			name:         "clears condition without violating customer namespace",
+			blockUpgrade: true,
+			namespace:    "openshift-violating",
+		},
+		{
+			name:         "clears condition when not enabled",
+			blockUpgrade: false,
+			namespace:    "customer",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &operatorv1.OperatorStatus{
+				Conditions: []operatorv1.OperatorCondition{
+					{Type: PodSecurityCustomerUpgradeableType, Status: operatorv1.ConditionFalse, Reason: violationReason},
+				},
+			}
+
+			cond := podSecurityOperatorConditions{
+				blockUpgradeOnCustomerViolations: tt.blockUpgrade,
+			}
+			cond.addViolation(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tt.namespace}})
+
+			for _, f := range cond.toConditionFuncs() {
+				if err := f(status); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityCustomerUpgradeableType)
+			switch {
+			case tt.expectedCondition == nil && condition != nil:
+				t.Errorf("expected condition %s to be removed, got %v", PodSecurityCustomerUpgradeableType, condition)
+			case tt.expectedCondition != nil && condition == nil:
+				t.Errorf("expected condition %s not found", PodSecurityCustomerUpgradeableType)
+			case tt.expectedCondition != nil && condition.Status != *tt.expectedCondition:
+				t.Errorf("expected %s to be %v, have %v", PodSecurityCustomerUpgradeableType, *tt.expectedCondition, condition.Status)
+			}
+		})
+	}
+}
+
+func TestAppendLevels(t *testing.T) {
+	levels := map[string]string{
+		"ns-a": "baseline",
+		"ns-b": "restricted",
+		"ns-c": "restricted",
+	}
+
+	t.Run("groups namespaces by level", func(t *testing.T) {
+		namespaces := []string{"ns-c", "ns-a", "ns-b", "ns-unknown"}
+		condition := appendLevels(makeCondition(PodSecurityCustomerType, violationReason, namespaces), "evaluated against", namespaces, levels)
+
+		expected := "Violations detected in namespaces: [ns-a ns-b ns-c ns-unknown]; evaluated against restricted: [ns-b ns-c], baseline: [ns-a]"
+		if condition.Message != expected {
+			t.Errorf("expected condition message %q, got %q", expected, condition.Message)
+		}
+	})
+
+	t.Run("leaves healthy conditions untouched", func(t *testing.T) {
+		condition := appendLevels(makeCondition(PodSecurityCustomerType, violationReason, nil), "evaluated against", nil, levels)
+		if condition.Message != "" {
+			t.Errorf("expected empty condition message, got %q", condition.Message)
+		}
+	})
+}
+
+func TestViolationDetails(t *testing.T) {
+	for _, tt := range []struct {
+		name             string
+		violationDetails bool
+
+		expectedMessage string
+	}{
+		{
+			name:            "without details",
+			expectedMessage: "Violations detected in namespaces: [ns-a]; strictest achievable baseline: [ns-a]",
+		},
+		{
+			name:             "with details",
+			violationDetails: true,
+			expectedMessage:  "Violations detected in namespaces: [ns-a]; evaluated against restricted: [ns-a]; strictest achievable baseline: [ns-a]; violating the longest: ns-a (since 2024-01-01T00:00:00Z)",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			conditions := podSecurityOperatorConditions{
+				violationDetails: tt.violationDetails,
+				evaluatedLevels:  map[string]string{"ns-a": "restricted"},
+				achievableLevels: map[string]string{"ns-a": "baseline"},
+				violatingSince:   map[string]time.Time{"ns-a": time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			}
+
+			condition := conditions.makeViolationCondition(PodSecurityCustomerType, []string{"ns-a"})
+			if condition.Message != tt.expectedMessage {
+				t.Errorf("expected condition message %q, got %q", tt.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
+
+func TestAppendWorkloadKinds(t *testing.T) {
+	workloadKinds := map[string][]string{
+		"ns-a": {"Deployment", "StatefulSet"},
+		"ns-b": {"Deployment", "Pod"},
+	}
+
+	t.Run("reports the kinds of all namespaces", func(t *testing.T) {
+		namespaces := []string{"ns-a", "ns-b", "ns-c"}
+		condition := appendWorkloadKinds(makeCondition(PodSecurityCustomerType, violationReason, namespaces), namespaces, workloadKinds)
+
+		expected := "Violations detected in namespaces: [ns-a ns-b ns-c]; violating workloads: Deployment, Pod, StatefulSet"
+		if condition.Message != expected {
+			t.Errorf("expected condition message %q, got %q", expected, condition.Message)
+		}
+	})
+
+	t.Run("leaves conditions without kinds untouched", func(t *testing.T) {
+		namespaces := []string{"ns-c"}
+		condition := appendWorkloadKinds(makeCondition(PodSecurityCustomerType, violationReason, namespaces), namespaces, workloadKinds)
+
+		expected := "Violations detected in namespaces: [ns-c]"
+		if condition.Message != expected {
+			t.Errorf("expected condition message %q, got %q", expected, condition.Message)
+		}
+	})
+}
+
+func TestAppendCheckFamilies(t *testing.T) {
+	checkFamilies := map[string]string{
+		"ns-a": checkFamilyVolumes,
+		"ns-b": checkFamilyCapabilities,
+		"ns-c": checkFamilyVolumes,
+	}
+
+	t.Run("groups namespaces by check family", func(t *testing.T) {
+		namespaces := []string{"ns-c", "ns-a", "ns-b", "ns-unknown"}
+		condition := appendCheckFamilies(makeCondition(PodSecurityUserSCCType, violationReason, namespaces), namespaces, checkFamilies)
+
+		expected := "Violations detected in namespaces: [ns-a ns-b ns-c ns-unknown]; most common violations capabilities: [ns-b], volumes: [ns-a ns-c]"
+		if condition.Message != expected {
+			t.Errorf("expected condition message %q, got %q", expected, condition.Message)
+		}
+	})
+
+	t.Run("leaves healthy conditions untouched", func(t *testing.T) {
+		condition := appendCheckFamilies(makeCondition(PodSecurityUserSCCType, violationReason, nil), nil, checkFamilies)
+		if condition.Message != "" {
+			t.Errorf("expected empty condition message, got %q", condition.Message)
+		}
+	})
+}
+
+func TestVolumeOnlyCondition(t *testing.T) {
+	conditions := podSecurityOperatorConditions{diagnostics: true}
+	conditions.addResult(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "volumes"}}, EvaluationResult{
+		Violating:    true,
+		UserWorkload: true,
+		Level:        "restricted",
+		CheckFamily:  checkFamilyVolumes,
+		VolumeOnly:   true,
+	})
+	conditions.addResult(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "mixed"}}, EvaluationResult{
+		Violating:    true,
+		UserWorkload: true,
+		Level:        "restricted",
+		CheckFamily:  checkFamilyVolumes,
+	})
+
+	status := &operatorv1.OperatorStatus{}
+	for _, fn := range conditions.toConditionFuncs() {
+		if err := fn(status); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityVolumeOnlyType)
+	if condition == nil {
+		t.Fatalf("expected condition %s to be set", PodSecurityVolumeOnlyType)
+	}
+	if condition.Status != operatorv1.ConditionTrue || condition.Reason != volumeOnlyReason {
+		t.Errorf("expected condition %s with reason %s, got %s with reason %s", operatorv1.ConditionTrue, volumeOnlyReason, condition.Status, condition.Reason)
+	}
+	if expected := "Violating user workloads only fail volume checks in namespaces: [volumes]"; condition.Message != expected {
+		t.Errorf("expected condition message %q, got %q", expected, condition.Message)
+	}
+
+	condition = v1helpers.FindOperatorCondition(status.Conditions, PodSecurityUserSCCType)
+	if condition == nil {
+		t.Fatalf("expected condition %s to be set", PodSecurityUserSCCType)
+	}
+	if expected := "most common violations volumes: [mixed volumes]"; !strings.Contains(condition.Message, expected) {
+		t.Errorf("expected condition message to contain %q, got %q", expected, condition.Message)
+	}
+}
+
+func TestStableLastTransitionTime(t *testing.T) {
+	lastTransition := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	newStatus := func() *operatorv1.OperatorStatus {
+		return &operatorv1.OperatorStatus{
+			Conditions: []operatorv1.OperatorCondition{
+				{
+					Type:               PodSecurityCustomerType,
+					Status:             operatorv1.ConditionTrue,
+					LastTransitionTime: lastTransition,
+					Reason:             violationReason,
+					Message:            "Violations detected in namespaces: [ns-a]",
+				},
+			},
+		}
+	}
+	apply := func(t *testing.T, status *operatorv1.OperatorStatus, conditions podSecurityOperatorConditions) *operatorv1.OperatorCondition {
+		for _, fn := range conditions.toConditionFuncs() {
+			if err := fn(status); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return v1helpers.FindOperatorCondition(status.Conditions, PodSecurityCustomerType)
+	}
+
+	t.Run("unchanged status keeps the transition time", func(t *testing.T) {
+		condition := apply(t, newStatus(), podSecurityOperatorConditions{
+			violatingCustomerNamespaces: []string{"ns-a", "ns-b"},
+		})
+		if !condition.LastTransitionTime.Equal(&lastTransition) {
+			t.Errorf("expected transition time %v, got %v", lastTransition, condition.LastTransitionTime)
+		}
+		if condition.Message != "Violations detected in namespaces: [ns-a ns-b]; first detected at 2024-01-01T00:00:00Z" {
+			t.Errorf("expected the message to be updated, got %q", condition.Message)
+		}
+	})
+
+	t.Run("changed status moves the transition time", func(t *testing.T) {
+		condition := apply(t, newStatus(), podSecurityOperatorConditions{})
+		if condition.Status != operatorv1.ConditionFalse {
+			t.Errorf("expected condition status %s, got %s", operatorv1.ConditionFalse, condition.Status)
+		}
+		if !lastTransition.Before(&condition.LastTransitionTime) {
+			t.Errorf("expected transition time after %v, got %v", lastTransition, condition.LastTransitionTime)
+		}
+	})
+}
+
+func TestViolationReasonTransitions(t *testing.T) {
+	status := &operatorv1.OperatorStatus{}
+	sync := func(conditions podSecurityOperatorConditions) operatorv1.OperatorCondition {
+		for _, fn := range conditions.toConditionFuncs() {
+			if err := fn(status); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+		return *v1helpers.FindOperatorCondition(status.Conditions, PodSecurityCustomerType)
+	}
+	violating := podSecurityOperatorConditions{violatingCustomerNamespaces: []string{"ns-a"}}
+
+	condition := sync(podSecurityOperatorConditions{})
+	if condition.Reason != expectedReason {
+		t.Errorf("expected reason %s without violations, got %s", expectedReason, condition.Reason)
+	}
+
+	raised := sync(violating)
+	if raised.Reason != violationReason {
+		t.Errorf("expected reason %s on first detection, got %s", violationReason, raised.Reason)
+	}
+	expectedMessage := fmt.Sprintf("Violations detected in namespaces: [ns-a]; first detected at %s", raised.LastTransitionTime.UTC().Format(time.RFC3339))
+	if raised.Message != expectedMessage {
+		t.Errorf("expected condition message %q on first detection, got %q", expectedMessage, raised.Message)
+	}
+
+	// The condition doesn't change while the violations persist.
+	if condition := sync(violating); !reflect.DeepEqual(condition, raised) {
+		t.Errorf("expected persistent violations to keep the condition %v, got %v", raised, condition)
+	}
+
+	condition = sync(podSecurityOperatorConditions{})
+	if condition.Reason != expectedReason {
+		t.Errorf("expected reason %s after resolution, got %s", expectedReason, condition.Reason)
+	}
+
+	// Renewed violations state when they were detected again.
+	condition = sync(violating)
+	expectedMessage = fmt.Sprintf("Violations detected in namespaces: [ns-a]; first detected at %s", condition.LastTransitionTime.UTC().Format(time.RFC3339))
+	if condition.Reason != violationReason || condition.Message != expectedMessage {
+		t.Errorf("expected reason %s and message %q on renewed detection, got %s and %q", violationReason, expectedMessage, condition.Reason, condition.Message)
+	}
+}
+
+func TestDegradedThresholds(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		thresholds DegradedThresholds
+		conditions podSecurityOperatorConditions
+
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:       "single run-level zero violation",
+			thresholds: DegradedThresholds{Customer: 3, RunLevelZero: 1},
+			conditions: podSecurityOperatorConditions{
+				violatingRunLevelZeroNamespaces: []string{"default"},
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "Too many namespaces with pod security violations: run-level zero (1, threshold 1)",
+		},
+		{
+			name:       "customer violations below threshold",
+			thresholds: DegradedThresholds{Customer: 3, RunLevelZero: 1},
+			conditions: podSecurityOperatorConditions{
+				violatingCustomerNamespaces: []string{"ns-a", "ns-b"},
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:       "customer and openshift violations at threshold",
+			thresholds: DegradedThresholds{Customer: 2, OpenShift: 1},
+			conditions: podSecurityOperatorConditions{
+				violatingCustomerNamespaces:  []string{"ns-a", "ns-b"},
+				violatingOpenShiftNamespaces: []string{"openshift-a"},
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "Too many namespaces with pod security violations: customer (2, threshold 2), openshift (1, threshold 1)",
+		},
+		{
+			name:       "category without threshold",
+			thresholds: DegradedThresholds{Customer: 2},
+			conditions: podSecurityOperatorConditions{
+				violatingOpenShiftNamespaces: []string{"openshift-a", "openshift-b"},
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &operatorv1.OperatorStatus{}
+			tt.conditions.degradedThresholds = tt.thresholds
+			for _, fn := range tt.conditions.toConditionFuncs() {
+				if err := fn(status); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityThresholdDegradedType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", PodSecurityThresholdDegradedType)
+			}
+			if condition.Status != tt.expectedStatus {
+				t.Errorf("expected condition status %s, got %s", tt.expectedStatus, condition.Status)
+			}
+			if condition.Message != tt.expectedMessage {
+				t.Errorf("expected condition message %q, got %q", tt.expectedMessage, condition.Message)
+			}
+		})
+	}
+
+	t.Run("removed without thresholds", func(t *testing.T) {
+		status := &operatorv1.OperatorStatus{
+			Conditions: []operatorv1.OperatorCondition{
+				{Type: PodSecurityThresholdDegradedType, Status: operatorv1.ConditionTrue},
+			},
+		}
+		for _, fn := range (&podSecurityOperatorConditions{}).toConditionFuncs() {
+			if err := fn(status); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityThresholdDegradedType); condition != nil {
+			t.Errorf("expected condition %s to be removed, got %v", PodSecurityThresholdDegradedType, condition)
+		}
+	})
+}
+
+func TestClassifyNamespace(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		namespace *corev1.Namespace
+		expected  namespaceCategory
+	}{
+		{
+			name:      "default",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}},
+			expected:  categoryRunLevelZero,
+		},
+		{
+			name:      "kube-system",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}},
+			expected:  categoryRunLevelZero,
+		},
+		{
+			name:      "kube-public",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-public"}},
+			expected:  categoryRunLevelZero,
+		},
+		{
+			name:      "kube-node-lease",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-node-lease"}},
+			expected:  categoryRunLevelZero,
+		},
+		{
+			name:      "customer namespace with kube prefix",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-monitoring"}},
+			expected:  categoryCustomer,
+		},
+		{
+			name: "run-level zero with disabled syncer",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "kube-system",
+				Labels: map[string]string{labelSyncControlLabel: "false"},
+			}},
+			expected: categoryRunLevelZero,
+		},
+		{
+			name:      "openshift prefix",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-console"}},
+			expected:  categoryOpenShift,
+		},
+		{
+			name: "openshift with disabled syncer",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "openshift-console",
+				Labels: map[string]string{labelSyncControlLabel: "false"},
+			}},
+			expected: categoryOpenShift,
+		},
+		{
+			name: "disabled syncer",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "customer",
+				Labels: map[string]string{labelSyncControlLabel: "false"},
+			}},
+			expected: categoryDisabledSyncer,
+		},
+		{
+			name: "enabled syncer",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "customer",
+				Labels: map[string]string{labelSyncControlLabel: "true"},
+			}},
+			expected: categoryCustomer,
+		},
+		{
+			name:      "customer",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "customer"}},
+			expected:  categoryCustomer,
+		},
+		{
+			name:      "openshift infix",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "my-openshift"}},
+			expected:  categoryCustomer,
+		},
+		{
+			name:      "add-on install namespace",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "addon"}},
+			expected:  categoryAddOn,
+		},
+		{
+			name: "operator group target",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "customer",
+				Labels: map[string]string{"olm.operatorgroup.uid/0a1b2c3d": ""},
+			}},
+			expected: categoryCustomer,
+		},
+		{
+			name:      "openshift add-on install namespace",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-operators"}},
+			expected:  categoryOpenShift,
+		},
+		{
+			name: "add-on install namespace with disabled syncer",
+			namespace: &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+				Name:   "addon",
+				Labels: map[string]string{labelSyncControlLabel: "false"},
+			}},
+			expected: categoryDisabledSyncer,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if category := classifyNamespace(tt.namespace, sets.New("addon", "openshift-operators")); category != tt.expected {
+				t.Errorf("expected category %v, got %v", tt.expected, category)
+			}
+		})
+	}
+}
+
+func TestAddOnCondition(t *testing.T) {
+	conditions := podSecurityOperatorConditions{addOnNamespaces: sets.New("addon")}
+	conditions.addViolation(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "addon"}})
+	if len(conditions.violatingCustomerNamespaces) != 0 {
+		t.Errorf("expected no customer namespaces, got %v", conditions.violatingCustomerNamespaces)
+	}
+
+	status := &operatorv1.OperatorStatus{}
+	for _, fn := range conditions.toConditionFuncs() {
+		if err := fn(status); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityAddOnType)
+	if condition == nil {
+		t.Fatalf("expected condition %s to be set", PodSecurityAddOnType)
+	}
+	if condition.Status != operatorv1.ConditionTrue {
+		t.Errorf("expected condition status %s, got %s", operatorv1.ConditionTrue, condition.Status)
+	}
+	if expected := "Violations detected in namespaces: [addon]"; !strings.HasPrefix(condition.Message, expected) {
+		t.Errorf("expected condition message to start with %q, got %q", expected, condition.Message)
+	}
+}
+
+func TestCleanCondition(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		cleanCounts map[string]int
+
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name:           "no clean namespaces",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:            "clean namespaces at several levels",
+			cleanCounts:     map[string]int{"baseline": 2, "privileged": 1, "restricted": 5},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "Namespaces without violations per evaluated level: restricted: 5, baseline: 2, privileged: 1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &operatorv1.OperatorStatus{}
+			conditions := podSecurityOperatorConditions{cleanCounts: tt.cleanCounts, summaries: true}
+			for _, fn := range conditions.toConditionFuncs() {
+				if err := fn(status); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityCleanType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", PodSecurityCleanType)
+			}
+			if condition.Status != tt.expectedStatus {
+				t.Errorf("expected condition status %s, got %s", tt.expectedStatus, condition.Status)
+			}
+			if condition.Message != tt.expectedMessage {
+				t.Errorf("expected condition message %q, got %q", tt.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
+
+func TestCompact(t *testing.T) {
+	conditions := podSecurityOperatorConditions{
+		violatingCustomerNamespaces:   []string{"customer"},
+		violatingOpenShiftNamespaces:  []string{"openshift-violating"},
+		inconclusiveNamespaces:        []string{"customer", "inconclusive", "openshift-violating"},
+		userSCCViolatingNamespaces:    []string{"customer"},
+		userSCCInconclusiveNamespaces: []string{"customer", "openshift-violating"},
+	}
+
+	conditions.compact()
+
+	if expected := []string{"inconclusive"}; !reflect.DeepEqual(conditions.inconclusiveNamespaces, expected) {
+		t.Errorf("expected inconclusive namespaces %v, got %v", expected, conditions.inconclusiveNamespaces)
+	}
+	if expected := []string{"openshift-violating"}; !reflect.DeepEqual(conditions.userSCCInconclusiveNamespaces, expected) {
+		t.Errorf("expected user SCC inconclusive namespaces %v, got %v", expected, conditions.userSCCInconclusiveNamespaces)
+	}
+	if expected := []string{"customer"}; !reflect.DeepEqual(conditions.violatingCustomerNamespaces, expected) {
+		t.Errorf("expected violating customer namespaces to be kept, got %v", conditions.violatingCustomerNamespaces)
+	}
+	if expected := []string{"customer"}; !reflect.DeepEqual(conditions.userSCCViolatingNamespaces, expected) {
+		t.Errorf("expected user SCC violating namespaces to be kept, got %v", conditions.userSCCViolatingNamespaces)
+	}
+}
+
+func TestSummary(t *testing.T) {
+	conditions := podSecurityOperatorConditions{
+		violatingCustomerNamespaces:  []string{"customer-d", "customer-b", "customer-a", "customer-c", "customer-e"},
+		violatingOpenShiftNamespaces: []string{"openshift-b", "openshift-a"},
+		inconclusiveNamespaces:       []string{"undetermined"},
+		cleanCounts:                  map[string]int{"restricted": 3, "baseline": 1},
+	}
+
+	expected := `customer: 5 (customer-a, customer-b, customer-c, and 2 more)
+openshift: 2 (openshift-a, openshift-b)
+runLevelZero: 0
+disabledSyncer: 0
+addOn: 0
+inconclusive: 1 (undetermined)
+userSCC: 0
+userSCCInconclusive: 0
+enforcedRegression: 0
+optedOut: 0
+misconfigured: 0
+volumeOnly: 0
+failedClosed: 0
+syncerPending: 0
+backedOff: 0
+clean: 4`
+	if summary := conditions.Summary(); summary != expected {
+		t.Errorf("expected summary\n%s\ngot\n%s", expected, summary)
+	}
+
+	if expected := []string{"customer-d", "customer-b", "customer-a", "customer-c", "customer-e"}; !reflect.DeepEqual(conditions.violatingCustomerNamespaces, expected) {
+		t.Errorf("expected the namespaces to be left unsorted, got %v", conditions.violatingCustomerNamespaces)
+	}
+}
+
+func TestCategoryReasons(t *testing.T) {
+	newConditions := func(categoryReasons bool, escalation RunLevelZeroEscalation) podSecurityOperatorConditions {
+		return podSecurityOperatorConditions{
+			violatingCustomerNamespaces:       []string{"customer"},
+			violatingOpenShiftNamespaces:      []string{"openshift-ns"},
+			violatingRunLevelZeroNamespaces:   []string{"kube-system"},
+			violatingDisabledSyncerNamespaces: []string{"disabled-syncer"},
+			violatingAddOnNamespaces:          []string{"add-on"},
+			userSCCViolatingNamespaces:        []string{"customer"},
+			regressedEnforcingNamespaces:      []string{"regressed"},
+			inconclusiveNamespaces:            []string{"undetermined"},
+
+			runLevelZeroEscalation:           escalation,
+			blockUpgradeOnCustomerViolations: true,
+			categoryReasons:                  categoryReasons,
+			enforcedNamespacesAudited:        true,
+		}
+	}
+
+	for _, tt := range []struct {
+		name            string
+		categoryReasons bool
+		escalation      RunLevelZeroEscalation
+
+		// expectedReasons are the reasons in the sync that raises the
+		// conditions, which don't change in the following syncs.
+		expectedReasons map[string]string
+	}{
+		{
+			name:       "violation reason",
+			escalation: RunLevelZeroEscalationUpgradeable,
+			expectedReasons: map[string]string{
+				PodSecurityCustomerType:                violationReason,
+				PodSecurityOpenshiftType:               violationReason,
+				PodSecurityRunLevelZeroType:            violationReason,
+				PodSecurityDisabledSyncerType:          violationReason,
+				PodSecurityAddOnType:                   violationReason,
+				PodSecurityUserSCCType:                 violationReason,
+				PodSecurityEnforcedRegressionType:      violationReason,
+				PodSecurityRunLevelZeroUpgradeableType: violationReason,
+				PodSecurityCustomerUpgradeableType:     violationReason,
+				PodSecurityInconclusiveType:            inconclusiveReason,
+			},
+		},
+		{
+			name:            "category reasons with upgradeable escalation",
+			categoryReasons: true,
+			escalation:      RunLevelZeroEscalationUpgradeable,
+			expectedReasons: map[string]string{
+				PodSecurityCustomerType:                "PSCustomerViolations",
+				PodSecurityOpenshiftType:               "PSOpenShiftViolations",
+				PodSecurityRunLevelZeroType:            "PSRunLevelZeroViolations",
+				PodSecurityDisabledSyncerType:          "PSDisabledSyncerViolations",
+				PodSecurityAddOnType:                   "PSAddOnViolations",
+				PodSecurityUserSCCType:                 "PSUserSCCViolations",
+				PodSecurityEnforcedRegressionType:      "PSEnforcedRegressionViolations",
+				PodSecurityRunLevelZeroUpgradeableType: "PSRunLevelZeroViolations",
+				PodSecurityCustomerUpgradeableType:     "PSCustomerViolations",
+				PodSecurityInconclusiveType:            inconclusiveReason,
+			},
+		},
+		{
+			name:            "category reasons with degraded escalation",
+			categoryReasons: true,
+			escalation:      RunLevelZeroEscalationDegraded,
+			expectedReasons: map[string]string{
+				PodSecurityRunLevelZeroType:         "PSRunLevelZeroViolations",
+				PodSecurityRunLevelZeroDegradedType: "PSRunLevelZeroViolations",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &operatorv1.OperatorStatus{}
+			for sync := range 2 {
+				cond := newConditions(tt.categoryReasons, tt.escalation)
+				for _, f := range cond.toConditionFuncs() {
+					if err := f(status); err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+				}
+
+				for conditionType, expected := range tt.expectedReasons {
+					condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType)
+					if condition == nil {
+						t.Errorf("sync %d: expected condition %s", sync, conditionType)
+						continue
+					}
+					if condition.Reason != expected {
+						t.Errorf("sync %d: expected reason %q for %s, got %q", sync, expected, conditionType, condition.Reason)
+					}
+				}
+			}
+		})
+	}
+}
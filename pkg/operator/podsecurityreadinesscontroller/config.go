@@ -0,0 +1,348 @@
+package podsecurityreadinesscontroller
+
+import (
+	"cmp"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/informers"
+	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+)
+
+// Config configures the PodSecurityReadinessController. The zero value
+// evaluates every namespace that doesn't enforce pod security yet and only
+// reports the conditions of the violation categories, along with those about
+// the health of the evaluation. Every other condition is only reported if the
+// setting it belongs to is enabled, and removed otherwise.
+type Config struct {
+	Namespaces NamespacesConfig
+	Levels     LevelsConfig
+	Pods       PodsConfig
+	Evaluation EvaluationConfig
+	Conditions ConditionsConfig
+	Results    ResultsConfig
+	Client     ClientConfig
+
+	// HealthChecker is started once the controller runs and is told about
+	// every successful sync. It turns unhealthy once no sync succeeded within
+	// its staleness window.
+	HealthChecker *SyncHealthChecker
+	// ResyncTrigger can force a full re-evaluation of all namespaces. A
+	// trigger can only be used with a single controller.
+	ResyncTrigger *ResyncTrigger
+	// Tracer traces every sync, with a child span per evaluated namespace,
+	// which in turn has the spans of the dry-run Applies and pod lists of the
+	// namespace. This shows which namespaces dominate the duration of a sync.
+	Tracer trace.Tracer
+}
+
+// NamespacesConfig selects the namespaces that are evaluated and the order
+// they are evaluated in.
+type NamespacesConfig struct {
+	// ExclusionLabel excludes the namespaces whose label has one of the
+	// ExclusionValues, or all namespaces with the label if there are no
+	// values, e.g. psa-migration=deferred. This lets admins manage
+	// exclusions with labels instead of the operator configuration.
+	ExclusionLabel  string
+	ExclusionValues []string
+	// CustomerOverrides are reported as customer namespaces, including their
+	// workload kinds, even if they would be classified as OpenShift,
+	// run-level zero, disabled syncer or add-on namespaces. This lets
+	// platform developers validate the readiness of platform namespaces. They
+	// are still only evaluated if they don't enforce pod security. The
+	// namespace of the operator can't be overridden.
+	CustomerOverrides []string
+	// NewNamespaceGracePeriod keeps namespaces that are younger than it from
+	// being reported as inconclusive, as the syncer might not have labeled
+	// them yet.
+	NewNamespaceGracePeriod time.Duration
+	// PriorityOrdering evaluates run-level zero namespaces first, then
+	// OpenShift namespaces and all other namespaces last, so that the
+	// critical categories are evaluated even if the sync is cut short.
+	// Namespaces are evaluated in the order they are listed otherwise.
+	PriorityOrdering bool
+}
+
+// LevelsConfig determines the enforce level each namespace is evaluated
+// against.
+type LevelsConfig struct {
+	// SyncerControllerName is the field manager of the pod security label
+	// syncer, whose labels and annotations are used to determine the enforce
+	// level. Defaults to the name of the OpenShift syncer.
+	SyncerControllerName string
+	// TrustedFieldManagers own pod security labels and annotations that are
+	// considered in addition to those of the syncer, e.g. if another syncer
+	// labels some of the namespaces.
+	TrustedFieldManagers []string
+	// AlertLabelPreference selects which of the warn and audit labels
+	// determine the enforce level. Defaults to the strictest of both.
+	AlertLabelPreference AlertLabelPreference
+	// Minimum and Maximum bound the level namespaces are evaluated against,
+	// regardless of their annotation, their alert labels or the cluster
+	// default, e.g. to only report baseline violations early in a migration.
+	Minimum psapi.Level
+	Maximum psapi.Level
+	// ClusterDefault evaluates namespaces without any pod security labels or
+	// annotations against the cluster-wide PodSecurity admission default
+	// instead of reporting them as inconclusive.
+	ClusterDefault bool
+	// ConflictingLevelEvents emits a warning event, in addition to the log
+	// message, when a namespace has warn and audit levels on opposite ends of
+	// the level range.
+	ConflictingLevelEvents bool
+}
+
+// PodsConfig configures the user SCC check, which evaluates the pods of
+// violating namespaces to find out whether the violations are caused by user
+// SCCs.
+type PodsConfig struct {
+	// SkipUserSCCCheck only evaluates namespaces with the dry run, without
+	// listing and evaluating their pods. This saves requests and the
+	// permission to list pods, but violating namespaces are never attributed
+	// to user SCCs, the user SCC conditions aren't reported, the violating
+	// workload kinds aren't hinted at and namespaces that only satisfy
+	// privileged aren't classified by remediation.
+	SkipUserSCCCheck bool
+	// PolicyChecks build the evaluator of the pods. Defaults to
+	// policy.DefaultChecks(), which follows the vendored pod-security-admission
+	// and therefore the checks enforced by the kube-apiserver.
+	PolicyChecks []policy.Check
+	// Phases are the phases of the pods that are evaluated. Defaults to
+	// Running and Pending, so that pods that terminated or whose node is
+	// unreachable are skipped. Pods without a phase count as Pending.
+	Phases []corev1.PodPhase
+	// IncludeTerminated evaluates pods in the Succeeded or Failed phase in
+	// addition to the evaluated phases.
+	IncludeTerminated bool
+	// SkipCompletedJobs ignores pods controlled by a finished Job.
+	SkipCompletedJobs bool
+	// SubjectTypes are the values of the validated SCC subject type
+	// annotation that make a pod count as a user workload. Defaults to "user".
+	// They are compared case-insensitively.
+	SubjectTypes []string
+	// IncludeUnannotated treats pods without the validated SCC subject type
+	// annotation, e.g. pods that were admitted before the annotation was
+	// introduced, as potential user workloads.
+	IncludeUnannotated bool
+	// MaxEvaluated caps the number of pods that are evaluated per namespace.
+	// Only the user workloads in the evaluated pod phases count, pods that
+	// are skipped anyway don't. Namespaces with more user workload pods are
+	// reported as inconclusive for the user SCC check. Unlimited if zero.
+	MaxEvaluated int64
+	// Templates also evaluates the pod templates of Deployments,
+	// StatefulSets and DaemonSets in namespaces without violating pods, so
+	// that workloads scaled to zero are caught before they are scaled up.
+	// Such namespaces are reported as violating, but inconclusive. Changes to
+	// the workloads don't invalidate cached evaluations.
+	Templates bool
+	// WorkloadKindHints hints at the kinds of the workloads that own the
+	// violating pods of customer namespaces in their condition, e.g.
+	// Deployment. This costs a request per violating ReplicaSet to find its
+	// Deployment.
+	WorkloadKindHints bool
+}
+
+// EvaluationConfig configures how namespaces are evaluated and what is
+// checked besides whether they are violating.
+type EvaluationConfig struct {
+	// Detector replaces the dry-run Apply of the enforce level that decides
+	// whether a namespace is violating, e.g. with a local evaluation of its
+	// pods. The user SCC check still evaluates the pods of violating
+	// namespaces.
+	Detector ViolationDetector
+	// ViolationWarningPattern is the regular expression that identifies the
+	// warnings of pod security admission about violating pods, e.g. if the
+	// apiserver words them differently. Warnings of the dry-run Apply that
+	// don't match are still treated as violations and reported with a
+	// PodSecurityUnrecognizedWarnings event. Defaults to
+	// defaultViolationWarningPattern.
+	ViolationWarningPattern string
+	// NamespaceTimeout reports namespaces as inconclusive if their
+	// evaluation takes longer, so that a single namespace can't block the
+	// sync. Defaults to one minute, a negative timeout disables it.
+	NamespaceTimeout time.Duration
+	// ErrorBackoffLimit evaluates namespaces whose evaluation keeps failing,
+	// e.g. because of a broken admission webhook scoped to them, less
+	// frequently. After the second consecutive failure, a namespace is
+	// skipped for 1, 3, 7 and so on syncs, at most ErrorBackoffLimit, and
+	// reported as inconclusive and backed off meanwhile. A successful
+	// evaluation puts it back on the normal cadence. Throttling, timeouts and
+	// cancellations don't count as failures of the namespace. Disabled if
+	// zero.
+	ErrorBackoffLimit int
+	// CacheInformers reuse the evaluation result of a namespace across syncs
+	// as long as neither the namespace nor its pods changed, according to
+	// their pod informer. The caller is responsible for starting them.
+	CacheInformers informers.SharedInformerFactory
+	// IncrementalInformers only let namespaces be evaluated again if they or
+	// their pods changed since their last evaluation, according to their
+	// namespace and pod informers, and carry the results of all others
+	// forward. The caller is responsible for starting them.
+	IncrementalInformers informers.SharedInformerFactory
+	// AuditEnforcedNamespaces additionally checks namespaces that already
+	// enforce pod security for pods that violate their enforce level.
+	AuditEnforcedNamespaces bool
+	// DetectInvalidEnforceLabels additionally reports namespaces whose
+	// enforce label isn't a valid level. They aren't evaluated, as they
+	// already have an enforce label, but pod security admission enforces
+	// restricted on them.
+	DetectInvalidEnforceLabels bool
+	// WarningHeartbeat verifies on every sync that warnings returned by the
+	// apiserver are captured, by creating a pod with a deprecated node
+	// selector with a dry run, which the apiserver always warns about.
+	// Degraded is set if the warning is lost.
+	WarningHeartbeat bool
+	// ProbeAchievableLevels determines the strictest level each violating
+	// namespace would satisfy today. This costs up to two more dry-run
+	// Applies per violating namespace.
+	ProbeAchievableLevels bool
+	// ClassifyRemediation splits the violating namespaces into those that can
+	// be fixed by relaxing their enforce level and those whose user workloads
+	// would be rejected at any enforceable level. This costs up to one more
+	// dry-run Apply and a pod list per violating namespace.
+	ClassifyRemediation bool
+	// FailClosed reports namespaces that couldn't be evaluated as violating
+	// instead of inconclusive, so that evaluation errors don't hide potential
+	// violations. They are also listed in a separate condition.
+	FailClosed bool
+	// TrackingResetGap forgets which namespaces have been violating, and for
+	// how long, once no sync succeeded for the gap, e.g. because another
+	// instance held the leader lease in the meantime. Without it, namespaces
+	// that were only observed violating before the gap would be reported as
+	// violating continuously across it.
+	TrackingResetGap time.Duration
+}
+
+// ConditionsConfig configures the conditions reported in the operator status.
+type ConditionsConfig struct {
+	// Terse only reports the conditions of categories that contain
+	// namespaces and removes the others.
+	Terse bool
+	// Informational removes all Degraded, Upgradeable and Available
+	// conditions of the controller, so that the evaluation never gates the
+	// operator, whatever other settings would set them.
+	Informational bool
+	// CategoryReasons raises the violation conditions with a reason that is
+	// specific to their category, e.g. PSCustomerViolations, instead of
+	// PSViolationsDetected, so that automation can tell the categories apart
+	// without parsing the type or the message.
+	CategoryReasons bool
+	// CollapseInconclusive reports all namespaces that couldn't be evaluated
+	// completely in the single PodSecurityEvaluationInconclusive condition,
+	// noting the category of each namespace, instead of the separate
+	// inconclusive and user SCC inconclusive conditions.
+	CollapseInconclusive bool
+	// Summaries reports the number of namespaces per category, the clean
+	// namespaces per level and a summary of the violations.
+	Summaries bool
+	// Diagnostics reports the namespaces with a stale annotation, those that
+	// opted out, those the syncer hasn't labeled yet and those whose user SCC
+	// violations are limited to volumes.
+	Diagnostics bool
+	// ViolationDetails adds the level each violating namespace was evaluated
+	// against and the namespaces that have been violating the longest to the
+	// messages of the violation conditions. Without it, the messages only
+	// change with the violating namespaces.
+	ViolationDetails bool
+	// BlockUpgradeOnCustomerViolations sets Upgradeable=False while customer
+	// namespaces are violating.
+	BlockUpgradeOnCustomerViolations bool
+	// RunLevelZeroEscalation selects the condition that is additionally
+	// raised when a run-level zero namespace is violating.
+	RunLevelZeroEscalation RunLevelZeroEscalation
+	// DegradedThresholds reports Degraded once the number of violating
+	// namespaces of a category reaches its threshold.
+	DegradedThresholds DegradedThresholds
+	// DryRun computes all conditions but only logs how they would change the
+	// operator status instead of writing it, to preview the controller on a
+	// cluster without affecting status consumers.
+	DryRun bool
+	// FieldManager applies the conditions to the operator status, instead of
+	// updating the status. It must differ from the one of the namespaces.
+	// Conditions written before aren't owned by it and might not be removed.
+	FieldManager string
+}
+
+// ResultsConfig configures where the results are published besides the
+// operator status.
+type ResultsConfig struct {
+	// ConfigMapNamespace and ConfigMapName name the ConfigMap the namespaces
+	// of every category are written to as JSON whenever they change, for
+	// consumers that can't read the operator status.
+	ConfigMapNamespace string
+	ConfigMapName      string
+	// ArtifactPath is the file the namespaces of every category are written
+	// to as JSON on every sync, for support tooling like must-gather.
+	ArtifactPath string
+	// RemediationAnnotations annotates violating customer namespaces with the
+	// enforce label their pods would satisfy and the first few workloads with
+	// violating pods, so that namespace owners find the guidance on their
+	// namespace. The annotation is owned by a dedicated field manager and
+	// removed once the namespace isn't violating anymore.
+	RemediationAnnotations bool
+	// ViolationHandler is invoked for every violating namespace on every
+	// sync. It doesn't block the sync and is cancelled after
+	// ViolationHandlerTimeout, or after 30 seconds if that isn't positive.
+	// Errors are logged. At most maxConcurrentViolationHandlers handlers run
+	// at the same time, violations beyond that are skipped until a handler
+	// returns.
+	ViolationHandler        ViolationHandler
+	ViolationHandlerTimeout time.Duration
+}
+
+// ClientConfig configures the requests to the apiserver.
+type ClientConfig struct {
+	// QPS and Burst set the rate at which requests are sent. Default to 2
+	// requests per second with a burst of 2. The rate is reduced whenever the
+	// apiserver is overloaded, and restored on the next sync.
+	QPS   float32
+	Burst int
+	// FieldManager owns the fields of the dry-run Applies of the namespaces.
+	// Defaults to "pod-security-readiness-controller".
+	FieldManager string
+}
+
+// syncerControllerName returns the field manager of the syncer, the one of the
+// OpenShift syncer unless configured otherwise.
+func (config LevelsConfig) syncerControllerName() string {
+	return cmp.Or(config.SyncerControllerName, defaultSyncerControllerName)
+}
+
+func (config PodsConfig) policyChecks() []policy.Check {
+	if len(config.PolicyChecks) == 0 {
+		// An evaluator without checks would allow every pod.
+		return policy.DefaultChecks()
+	}
+	return config.PolicyChecks
+}
+
+func (config EvaluationConfig) violationWarningPattern() string {
+	return cmp.Or(config.ViolationWarningPattern, defaultViolationWarningPattern)
+}
+
+// namespaceTimeout bounds the evaluation of every namespace, unless it isn't
+// positive.
+func (config EvaluationConfig) namespaceTimeout() time.Duration {
+	return cmp.Or(config.NamespaceTimeout, defaultNamespaceEvaluationTimeout)
+}
+
+func (config ResultsConfig) violationHandlerTimeout() time.Duration {
+	if config.ViolationHandlerTimeout <= 0 {
+		return defaultViolationHandlerTimeout
+	}
+	return config.ViolationHandlerTimeout
+}
+
+func (config ClientConfig) qps() float32 {
+	return cmp.Or(config.QPS, defaultClientQPS)
+}
+
+func (config ClientConfig) burst() int {
+	return cmp.Or(config.Burst, defaultClientBurst)
+}
+
+func (config ClientConfig) fieldManager() string {
+	return cmp.Or(config.FieldManager, readinessFieldManager)
+}
@@ -0,0 +1,108 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+)
+
+func TestConfigDefaults(t *testing.T) {
+	newController := func(t *testing.T, config Config) *PodSecurityReadinessController {
+		t.Helper()
+
+		controller, err := newPodSecurityReadinessController(
+			v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			events.NewInMemoryRecorder("test", clock.RealClock{}),
+			NewWarningsHandler(),
+			config,
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return controller
+	}
+
+	t.Run("zero value", func(t *testing.T) {
+		controller := newController(t, Config{})
+
+		if controller.config.Levels.syncerControllerName() != defaultSyncerControllerName {
+			t.Errorf("expected the syncer controller name %q, got %q", defaultSyncerControllerName, controller.config.Levels.syncerControllerName())
+		}
+		if controller.config.Client.fieldManager() != readinessFieldManager {
+			t.Errorf("expected the field manager %q, got %q", readinessFieldManager, controller.config.Client.fieldManager())
+		}
+		if controller.config.Evaluation.violationWarningPattern() != defaultViolationWarningPattern {
+			t.Errorf("expected the violation warning pattern %q, got %q", defaultViolationWarningPattern, controller.config.Evaluation.violationWarningPattern())
+		}
+		if controller.config.Evaluation.namespaceTimeout() != defaultNamespaceEvaluationTimeout {
+			t.Errorf("expected the namespace evaluation timeout %v, got %v", defaultNamespaceEvaluationTimeout, controller.config.Evaluation.namespaceTimeout())
+		}
+		if controller.config.Client.qps() != defaultClientQPS || controller.config.Client.burst() != defaultClientBurst {
+			t.Errorf("expected the client rate limit %v with a burst of %d, got %v with a burst of %d", float32(defaultClientQPS), defaultClientBurst, controller.config.Client.qps(), controller.config.Client.burst())
+		}
+		if len(controller.config.Pods.policyChecks()) == 0 {
+			t.Error("expected the default policy checks")
+		}
+		if controller.violationHandlerSlots != nil {
+			t.Error("expected no violation handler slots without a violation handler")
+		}
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		controller := newController(t, Config{
+			Levels:     LevelsConfig{SyncerControllerName: "custom-syncer"},
+			Pods:       PodsConfig{SubjectTypes: []string{" ServiceAccount "}},
+			Evaluation: EvaluationConfig{NamespaceTimeout: -1},
+			Client:     ClientConfig{QPS: 10, Burst: 20, FieldManager: "custom-manager"},
+		})
+
+		if controller.config.Levels.syncerControllerName() != "custom-syncer" {
+			t.Errorf("expected the syncer controller name %q, got %q", "custom-syncer", controller.config.Levels.syncerControllerName())
+		}
+		if controller.config.Client.fieldManager() != "custom-manager" {
+			t.Errorf("expected the field manager %q, got %q", "custom-manager", controller.config.Client.fieldManager())
+		}
+		serviceAccountPod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{securityv1.ValidatedSCCSubjectTypeAnnotation: "serviceaccount"},
+		}}
+		if !controller.isUserWorkload(serviceAccountPod) {
+			t.Errorf("expected the subject types to be compared normalized, got %v", controller.config.Pods.SubjectTypes)
+		}
+		if controller.config.Evaluation.namespaceTimeout() > 0 {
+			t.Errorf("expected the namespace evaluation timeout to be disabled, got %v", controller.config.Evaluation.namespaceTimeout())
+		}
+		if controller.config.Client.qps() != 10 || controller.config.Client.burst() != 20 {
+			t.Errorf("expected the client rate limit 10 with a burst of 20, got %v with a burst of %d", controller.config.Client.qps(), controller.config.Client.burst())
+		}
+	})
+
+	t.Run("violation handler timeout", func(t *testing.T) {
+		controller := newController(t, Config{Results: ResultsConfig{ViolationHandler: func(context.Context, *corev1.Namespace, EvaluationResult) error { return nil }}})
+
+		if controller.config.Results.violationHandlerTimeout() != defaultViolationHandlerTimeout {
+			t.Errorf("expected the violation handler timeout %v, got %v", defaultViolationHandlerTimeout, controller.config.Results.violationHandlerTimeout())
+		}
+		if cap(controller.violationHandlerSlots) != maxConcurrentViolationHandlers {
+			t.Errorf("expected %d violation handler slots, got %d", maxConcurrentViolationHandlers, cap(controller.violationHandlerSlots))
+		}
+	})
+
+	t.Run("negative client rate limit rejected", func(t *testing.T) {
+		_, err := newPodSecurityReadinessController(
+			v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			events.NewInMemoryRecorder("test", clock.RealClock{}),
+			NewWarningsHandler(),
+			Config{Client: ClientConfig{QPS: -1}},
+		)
+		if err == nil {
+			t.Error("expected a negative client rate limit to be rejected")
+		}
+	})
+}
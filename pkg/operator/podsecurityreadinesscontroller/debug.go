@@ -0,0 +1,79 @@
+package podsecurityreadinesscontroller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"k8s.io/klog/v2"
+)
+
+// DebugPath is the path prefix the debug handler of the controller is served
+// under.
+const DebugPath = "/debug/pod-security-readiness"
+
+// errNamespaceNotReported is returned for namespaces that aren't part of the
+// last report.
+var errNamespaceNotReported = errors.New("namespace wasn't evaluated by the last sync")
+
+// DebugHandler serves the following endpoints below DebugPath for tooling:
+//
+//   - /snapshot returns the namespaces of every category as of the last sync,
+//     see Snapshot.
+//   - /report returns the outcome of the last complete evaluation, see
+//     GenerateReport.
+//   - /namespaces/<name> returns the outcome of a single namespace in the last
+//     complete evaluation.
+//
+// The endpoints only serve the results of the sync, so requests never
+// evaluate namespaces or wait for a running sync.
+func (c *PodSecurityReadinessController) DebugHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET "+DebugPath+"/snapshot", func(w http.ResponseWriter, r *http.Request) {
+		writeDebugResponse(w, c.Snapshot(), nil)
+	})
+	mux.HandleFunc("GET "+DebugPath+"/report", func(w http.ResponseWriter, r *http.Request) {
+		report, err := c.GenerateReport(r.Context())
+		writeDebugResponse(w, report, err)
+	})
+	mux.HandleFunc("GET "+DebugPath+"/namespaces/{name}", func(w http.ResponseWriter, r *http.Request) {
+		report, err := c.GenerateReport(r.Context())
+		if err != nil {
+			writeDebugResponse(w, nil, err)
+			return
+		}
+
+		name := r.PathValue("name")
+		for _, nsReport := range report.Namespaces {
+			if nsReport.Name == name {
+				writeDebugResponse(w, nsReport, nil)
+				return
+			}
+		}
+		writeDebugResponse(w, nil, fmt.Errorf("%w: %s", errNamespaceNotReported, name))
+	})
+
+	return mux
+}
+
+// writeDebugResponse writes the object as JSON, or the error with a status
+// matching it.
+func writeDebugResponse(w http.ResponseWriter, obj any, err error) {
+	switch {
+	case errors.Is(err, errNamespaceNotReported):
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	case errors.Is(err, ErrNoReport):
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		klog.ErrorS(err, "Failed to write the debug response")
+	}
+}
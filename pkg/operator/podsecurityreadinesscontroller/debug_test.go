@@ -0,0 +1,121 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestDebugHandler(t *testing.T) {
+	violationWarning := "existing pods in namespace \"violating\" violate the new PodSecurity enforce level \"restricted:latest\""
+
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "violating",
+			Annotations:   map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			ManagedFields: managedFields,
+		},
+	})
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		handleWarnings(handler, []string{violationWarning})
+		return true, nil, nil
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clocktesting.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)),
+		warningsHandler: handler,
+		dryRunVerified:  true,
+		config:          Config{Pods: PodsConfig{SkipUserSCCCheck: true}},
+	}
+	debugHandler := controller.DebugHandler()
+
+	get := func(t *testing.T, path string, expectedCode int, obj any) {
+		t.Helper()
+
+		recorder := httptest.NewRecorder()
+		debugHandler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, DebugPath+path, nil))
+		if recorder.Code != expectedCode {
+			t.Fatalf("expected status %d, got %d: %s", expectedCode, recorder.Code, recorder.Body.String())
+		}
+		if obj == nil {
+			return
+		}
+		if err := json.Unmarshal(recorder.Body.Bytes(), obj); err != nil {
+			t.Fatalf("failed to decode the response: %v", err)
+		}
+	}
+
+	t.Run("no report before the first sync", func(t *testing.T) {
+		get(t, "/report", http.StatusServiceUnavailable, nil)
+		get(t, "/namespaces/violating", http.StatusServiceUnavailable, nil)
+	})
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Requests only serve the results of the sync.
+	actions := len(fakeClient.Actions())
+	defer func() {
+		if len(fakeClient.Actions()) != actions {
+			t.Errorf("expected no requests by the debug handler, got %v", fakeClient.Actions()[actions:])
+		}
+	}()
+
+	t.Run("snapshot", func(t *testing.T) {
+		conditions := podSecurityOperatorConditions{}
+		conditions.addViolation(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "synced"}})
+		controller.setLastConditions(conditions)
+
+		var snapshot CategorizedNamespaces
+		get(t, "/snapshot", http.StatusOK, &snapshot)
+		if !reflect.DeepEqual(snapshot, CategorizedNamespaces{Customer: []string{"synced"}}) {
+			t.Errorf("expected the namespaces of the last sync, got %+v", snapshot)
+		}
+	})
+
+	t.Run("report", func(t *testing.T) {
+		var report Report
+		get(t, "/report", http.StatusOK, &report)
+		if !reflect.DeepEqual(report.Categories.Customer, []string{"violating"}) {
+			t.Errorf("expected the violating namespace to be reported, got %+v", report.Categories)
+		}
+	})
+
+	t.Run("namespace", func(t *testing.T) {
+		var result EvaluationResult
+		get(t, "/namespaces/violating", http.StatusOK, &result)
+		expected := EvaluationResult{Violating: true, Level: "restricted", Reason: violationWarning}
+		if result != expected {
+			t.Errorf("expected %+v, got %+v", expected, result)
+		}
+	})
+
+	t.Run("nonexistent namespace", func(t *testing.T) {
+		get(t, "/namespaces/nonexistent", http.StatusNotFound, nil)
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		get(t, "/unknown", http.StatusNotFound, nil)
+	})
+}
@@ -0,0 +1,69 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// ViolationDetector decides whether the pods of a namespace would violate an
+// enforce level. The level is resolved by the controller, see
+// resolveEnforceLevel, and may be invalid. The warnings explain the
+// violations and may be empty even if the namespace is violating.
+type ViolationDetector interface {
+	Detect(ctx context.Context, ns *corev1.Namespace, level string) (violating bool, warnings []string, err error)
+}
+
+// dryRunDetector is the default ViolationDetector. It applies the enforce
+// level with a dry run and relies on the warnings of pod security admission
+// about existing pods, as identified by the violation warning pattern.
+type dryRunDetector struct {
+	controller *PodSecurityReadinessController
+}
+
+func (d dryRunDetector) Detect(ctx context.Context, ns *corev1.Namespace, level string) (bool, []string, error) {
+	warnings, err := d.controller.dryRunEnforceLevel(ctx, ns.Name, level)
+	if err != nil {
+		return false, nil, err
+	}
+
+	pattern := d.controller.violationWarning
+	if pattern == nil {
+		pattern = defaultViolationWarning
+	}
+	violations := violationWarnings(pattern, warnings)
+	if len(violations) == 0 && len(warnings) > 0 {
+		// The apiserver may have reworded its warnings, reporting the
+		// namespace as clean would hide its violations.
+		d.controller.reportUnrecognizedWarnings(ns, pattern, warnings)
+		return true, warnings, nil
+	}
+
+	return len(violations) > 0, violations, nil
+}
+
+// reportUnrecognizedWarnings surfaces warnings of a dry-run Apply that don't
+// match the violation warning pattern, which likely needs to be updated.
+func (c *PodSecurityReadinessController) reportUnrecognizedWarnings(ns *corev1.Namespace, pattern *regexp.Regexp, warnings []string) {
+	klog.InfoS("Treating unrecognized warnings as violations", "namespace", ns.Name, "pattern", pattern.String(), "warnings", warnings)
+	if c.recorder == nil {
+		return
+	}
+
+	c.recorder.Warningf("PodSecurityUnrecognizedWarnings",
+		"Namespace %s is treated as violating because the dry run of its enforce level returned warnings that don't match the violation warning pattern %q: %s",
+		ns.Name, pattern.String(), strings.Join(warnings, "; "))
+}
+
+// violationDetector returns the detector namespaces are evaluated with, which
+// defaults to a dry-run Apply of the enforce level.
+func (c *PodSecurityReadinessController) violationDetector() ViolationDetector {
+	if c.config.Evaluation.Detector != nil {
+		return c.config.Evaluation.Detector
+	}
+
+	return dryRunDetector{controller: c}
+}
@@ -0,0 +1,135 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/clock"
+)
+
+// fakeDetector reports the namespaces in violating as violating with the given
+// warnings, and records the levels it was asked about.
+type fakeDetector struct {
+	violating map[string][]string
+	err       error
+
+	levels []string
+}
+
+func (d *fakeDetector) Detect(_ context.Context, ns *corev1.Namespace, level string) (bool, []string, error) {
+	d.levels = append(d.levels, level)
+	if d.err != nil {
+		return false, nil, d.err
+	}
+
+	warnings, ok := d.violating[ns.Name]
+	return ok, warnings, nil
+}
+
+func TestViolationDetector(t *testing.T) {
+	namespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "baseline",
+				},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+	errDetection := errors.New("detection failed")
+
+	for _, tt := range []struct {
+		name     string
+		detector *fakeDetector
+
+		expectViolating bool
+		expectReason    string
+		expectError     error
+	}{
+		{
+			name:     "clean",
+			detector: &fakeDetector{},
+		},
+		{
+			name:            "violating with warnings",
+			detector:        &fakeDetector{violating: map[string][]string{"test-ns": {"first", "second"}}},
+			expectViolating: true,
+			expectReason:    "first",
+		},
+		{
+			name:            "violating without warnings",
+			detector:        &fakeDetector{violating: map[string][]string{"test-ns": nil}},
+			expectViolating: true,
+			expectReason:    "namespace would violate the PodSecurity enforce level \"baseline\"",
+		},
+		{
+			name:        "detection error",
+			detector:    &fakeDetector{err: errDetection},
+			expectError: errDetection,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fake.NewSimpleClientset(),
+				warningsHandler: &warningsHandler{},
+				config:          Config{Evaluation: EvaluationConfig{Detector: tt.detector}},
+			}
+
+			result, err := controller.evaluateNamespaceViolation(context.TODO(), namespace("test-ns"))
+			if !errors.Is(err, tt.expectError) {
+				t.Fatalf("expected error %v, got %v", tt.expectError, err)
+			}
+			if result.Violating != tt.expectViolating {
+				t.Errorf("expected violating %v, got %v", tt.expectViolating, result.Violating)
+			}
+			if result.UserWorkload {
+				t.Error("expected no user workloads without pods")
+			}
+			if len(tt.detector.levels) != 1 || tt.detector.levels[0] != "baseline" {
+				t.Errorf("expected the detector to be asked about the resolved level, got %v", tt.detector.levels)
+			}
+			if result.Reason != tt.expectReason {
+				t.Errorf("expected reason %q, got %q", tt.expectReason, result.Reason)
+			}
+		})
+	}
+
+	t.Run("sync without dry-run Apply", func(t *testing.T) {
+		fakeClient := fake.NewSimpleClientset(namespace("violating"), namespace("clean"))
+		detector := &fakeDetector{violating: map[string][]string{"violating": {"violation"}}}
+		controller := &PodSecurityReadinessController{
+			kubeClient:      fakeClient,
+			operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			clock:           clock.RealClock{},
+			warningsHandler: &warningsHandler{},
+			config: Config{
+				Pods:       PodsConfig{SkipUserSCCCheck: true},
+				Evaluation: EvaluationConfig{Detector: detector},
+			},
+		}
+
+		if err := controller.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		for _, action := range fakeClient.Actions() {
+			if action.GetVerb() == "patch" {
+				t.Errorf("expected no dry-run Apply with a custom detector, got %v", action)
+			}
+		}
+		if violating := controller.snapshot().violatingCustomerNamespaces; len(violating) != 1 || violating[0] != "violating" {
+			t.Errorf("expected the violating namespace to be reported, got %v", violating)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/utils/clock"
+)
+
+// DefaultSyncStaleness spans a few resync intervals of the controller, so that
+// a single failed sync doesn't turn it unhealthy.
+const DefaultSyncStaleness = 3 * checkInterval
+
+// SyncHealthChecker reports the controller as unhealthy if it didn't complete
+// a sync within the staleness window, e.g. because it is stuck or keeps
+// failing. It is passed to the controller in Config.HealthChecker and
+// registered with the health checks of the controller command, which are
+// installed before the controllers start.
+//
+// The checker is healthy until the controller starts, so that replicas that
+// don't hold the leader lease and never run the controller stay healthy.
+type SyncHealthChecker struct {
+	clock     clock.PassiveClock
+	staleness time.Duration
+
+	lock     sync.Mutex
+	started  bool
+	lastSync time.Time
+}
+
+var _ healthz.HealthChecker = &SyncHealthChecker{}
+
+// NewSyncHealthChecker returns a checker with the given staleness window,
+// which must exceed the resync interval of the controller. The controller is
+// healthy for the staleness window after it started, to give it time for the
+// first sync.
+func NewSyncHealthChecker(staleness time.Duration) *SyncHealthChecker {
+	return newSyncHealthChecker(staleness, clock.RealClock{})
+}
+
+func newSyncHealthChecker(staleness time.Duration, clock clock.PassiveClock) *SyncHealthChecker {
+	return &SyncHealthChecker{
+		clock:     clock,
+		staleness: staleness,
+	}
+}
+
+func (h *SyncHealthChecker) Name() string {
+	return "pod-security-readiness-sync"
+}
+
+func (h *SyncHealthChecker) Check(_ *http.Request) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if !h.started {
+		return nil
+	}
+	if since := h.clock.Since(h.lastSync); since > h.staleness {
+		return fmt.Errorf("the last pod security readiness sync completed %v ago, more than %v", since.Round(time.Second), h.staleness)
+	}
+
+	return nil
+}
+
+// start starts the staleness window, unless it is already running.
+func (h *SyncHealthChecker) start() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.started {
+		return
+	}
+	h.started = true
+	h.lastSync = h.clock.Now()
+}
+
+// recordSync marks the completion of a successful sync.
+func (h *SyncHealthChecker) recordSync() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	h.lastSync = h.clock.Now()
+}
+
+// startHealthChecker is a post start hook of the controller, which only runs
+// on the replica holding the leader lease.
+func (c *PodSecurityReadinessController) startHealthChecker(_ context.Context, _ factory.SyncContext) error {
+	c.config.HealthChecker.start()
+	return nil
+}
@@ -0,0 +1,160 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apiserver/pkg/server/healthz"
+	"k8s.io/apiserver/pkg/server/mux"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestSyncHealthChecker(t *testing.T) {
+	fakeClock := clocktesting.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	checker := newSyncHealthChecker(time.Hour, fakeClock)
+
+	// The controller doesn't run on replicas without the leader lease.
+	fakeClock.Step(2 * time.Hour)
+	if err := checker.Check(nil); err != nil {
+		t.Errorf("expected the checker to be healthy before the controller started, got %v", err)
+	}
+
+	checker.start()
+	if err := checker.Check(nil); err != nil {
+		t.Errorf("expected the checker to be healthy before the first sync, got %v", err)
+	}
+
+	fakeClock.Step(time.Hour + time.Second)
+	if err := checker.Check(nil); err == nil {
+		t.Error("expected the checker to be unhealthy without a sync within the staleness window")
+	}
+
+	// Starting again doesn't restart the staleness window.
+	checker.start()
+	if err := checker.Check(nil); err == nil {
+		t.Error("expected the checker to stay unhealthy when started again")
+	}
+
+	checker.recordSync()
+	if err := checker.Check(nil); err != nil {
+		t.Errorf("expected the checker to be healthy after a sync, got %v", err)
+	}
+
+	fakeClock.Step(time.Hour)
+	if err := checker.Check(nil); err != nil {
+		t.Errorf("expected the checker to be healthy at the end of the staleness window, got %v", err)
+	}
+}
+
+func TestSyncHealthCheckerHealthz(t *testing.T) {
+	fakeClock := clocktesting.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	checker := newSyncHealthChecker(time.Hour, fakeClock)
+	controller := &PodSecurityReadinessController{config: Config{HealthChecker: checker}}
+
+	// The controller command installs its health checks like this.
+	pathMux := mux.NewPathRecorderMux("test")
+	healthz.InstallHandler(pathMux, checker)
+
+	get := func(t *testing.T, expectedCode int) {
+		t.Helper()
+
+		recorder := httptest.NewRecorder()
+		pathMux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz/"+checker.Name(), nil))
+		if recorder.Code != expectedCode {
+			t.Errorf("expected status %d, got %d: %s", expectedCode, recorder.Code, recorder.Body.String())
+		}
+	}
+
+	if err := controller.startHealthChecker(context.TODO(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	get(t, http.StatusOK)
+
+	fakeClock.Step(2 * time.Hour)
+	get(t, http.StatusInternalServerError)
+}
+
+func TestSyncHealthCheckerSync(t *testing.T) {
+	fakeClock := clocktesting.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	checker := newSyncHealthChecker(time.Hour, fakeClock)
+
+	failing := true
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("list", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if !failing {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewServiceUnavailable("apiserver unavailable")
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           fakeClock,
+		warningsHandler: &warningsHandler{},
+		dryRunVerified:  true,
+		config:          Config{HealthChecker: checker},
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	checker.start()
+
+	fakeClock.Step(2 * time.Hour)
+	if err := controller.sync(context.TODO(), syncCtx); err == nil {
+		t.Fatal("expected the sync to fail")
+	}
+	if err := checker.Check(nil); err == nil {
+		t.Error("expected a failed sync not to make the checker healthy")
+	}
+
+	failing = false
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := checker.Check(nil); err != nil {
+		t.Errorf("expected a successful sync to make the checker healthy, got %v", err)
+	}
+}
+
+func TestSyncHealthCheckerValidation(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		staleness   time.Duration
+		expectError bool
+	}{
+		{
+			name:      "staleness beyond the resync interval",
+			staleness: 2 * checkInterval,
+		},
+		{
+			name:        "staleness within the resync interval",
+			staleness:   checkInterval,
+			expectError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewPodSecurityReadinessController(
+				&rest.Config{Host: "https://localhost:6443"},
+				v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				events.NewInMemoryRecorder("test", clock.RealClock{}),
+				NewWarningsHandler(),
+				Config{HealthChecker: NewSyncHealthChecker(tt.staleness)},
+			)
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
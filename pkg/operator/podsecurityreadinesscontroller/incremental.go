@@ -0,0 +1,140 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+)
+
+// changeTracker records, from informer events, which namespaces changed since
+// they were last evaluated, and carries the results of all others forward.
+type changeTracker struct {
+	lock sync.Mutex
+
+	changed             sets.Set[string]
+	results             map[string]EvaluationResult
+	clusterDefaultLevel string
+}
+
+func newChangeTracker() *changeTracker {
+	return &changeTracker{
+		changed: sets.New[string](),
+		results: map[string]EvaluationResult{},
+	}
+}
+
+// eventHandler marks the namespace returned by namespaceOf for the object of
+// every event as changed.
+func (t *changeTracker) eventHandler(namespaceOf func(metav1.Object) string) cache.ResourceEventHandler {
+	markChanged := func(obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+
+		object, err := meta.Accessor(obj)
+		if err != nil {
+			return
+		}
+		t.markChanged(namespaceOf(object))
+	}
+
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    markChanged,
+		UpdateFunc: func(_, newObj interface{}) { markChanged(newObj) },
+		DeleteFunc: markChanged,
+	}
+}
+
+func (t *changeTracker) markChanged(namespace string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.changed.Insert(namespace)
+}
+
+// carriedResult returns the previous result of the namespace unless it changed
+// since. A changed namespace is expected to be evaluated again, so it is no
+// longer marked as changed.
+func (t *changeTracker) carriedResult(namespace string) (EvaluationResult, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.changed.Has(namespace) {
+		t.changed.Delete(namespace)
+		delete(t.results, namespace)
+		return EvaluationResult{}, false
+	}
+
+	result, ok := t.results[namespace]
+	return result, ok
+}
+
+func (t *changeTracker) setResult(namespace string, result EvaluationResult) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.results[namespace] = result
+}
+
+// setClusterDefaultLevel drops all results once the cluster default enforce
+// level changed, as it applies to every namespace without labels.
+func (t *changeTracker) setClusterDefaultLevel(level string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if level != t.clusterDefaultLevel {
+		t.results = map[string]EvaluationResult{}
+		t.clusterDefaultLevel = level
+	}
+}
+
+// reset drops all results, so that every namespace is evaluated again.
+func (t *changeTracker) reset() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.changed = sets.New[string]()
+	t.results = map[string]EvaluationResult{}
+}
+
+// retain drops the results of all namespaces that aren't listed anymore.
+func (t *changeTracker) retain(namespaces sets.Set[string]) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for namespace := range t.results {
+		if !namespaces.Has(namespace) {
+			delete(t.results, namespace)
+		}
+	}
+}
+
+// evaluateNamespace evaluates the namespace, unless incremental evaluation is
+// enabled and the namespace didn't change since its last evaluation.
+func (c *PodSecurityReadinessController) evaluateNamespace(ctx context.Context, ns *corev1.Namespace) (EvaluationResult, error) {
+	if c.changeTracker == nil {
+		return c.evaluateNamespaceCached(ctx, ns)
+	}
+
+	if result, ok := c.changeTracker.carriedResult(ns.Name); ok {
+		return result, nil
+	}
+
+	result, err := c.evaluateNamespaceCached(ctx, ns)
+	if err != nil {
+		return result, err
+	}
+
+	// Inconclusive results may change without any event, e.g. when
+	// permissions are granted.
+	if !result.Inconclusive {
+		c.changeTracker.setResult(ns.Name, result)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,143 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+)
+
+func TestIncrementalEvaluation(t *testing.T) {
+	newNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+	namespaceA, namespaceB := newNamespace("namespace-a"), newNamespace("namespace-b")
+
+	handler := &warningsHandler{}
+	violating := sets.New(namespaceA.Name)
+	var evaluated []string
+	fakeClient := fake.NewSimpleClientset(namespaceA, namespaceB)
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(clienttesting.PatchAction).GetName()
+		evaluated = append(evaluated, name)
+		if violating.Has(name) {
+			handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"restricted:latest\"", name))
+		}
+		return true, nil, nil
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: handler,
+		dryRunVerified:  true,
+		changeTracker:   newChangeTracker(),
+	}
+	namespaceHandler := controller.changeTracker.eventHandler(func(obj metav1.Object) string { return obj.GetName() })
+	podHandler := controller.changeTracker.eventHandler(func(obj metav1.Object) string { return obj.GetNamespace() })
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	for _, step := range []struct {
+		name   string
+		events func()
+
+		expectedEvaluated []string
+		expectedViolating []string
+	}{
+		{
+			name:              "initial sync",
+			events:            func() {},
+			expectedEvaluated: []string{namespaceA.Name, namespaceB.Name},
+			expectedViolating: []string{namespaceA.Name},
+		},
+		{
+			name:              "nothing changed",
+			events:            func() {},
+			expectedViolating: []string{namespaceA.Name},
+		},
+		{
+			name: "pod added",
+			events: func() {
+				violating.Insert(namespaceB.Name)
+				podHandler.OnAdd(&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespaceB.Name}}, false)
+			},
+			expectedEvaluated: []string{namespaceB.Name},
+			expectedViolating: []string{namespaceA.Name, namespaceB.Name},
+		},
+		{
+			name: "namespace updated",
+			events: func() {
+				violating.Delete(namespaceA.Name)
+				namespaceHandler.OnUpdate(namespaceA, namespaceA)
+			},
+			expectedEvaluated: []string{namespaceA.Name},
+			expectedViolating: []string{namespaceB.Name},
+		},
+		{
+			name: "pod deleted with a tombstone",
+			events: func() {
+				violating.Delete(namespaceB.Name)
+				podHandler.OnDelete(cache.DeletedFinalStateUnknown{
+					Key: namespaceB.Name + "/pod",
+					Obj: &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespaceB.Name}},
+				})
+			},
+			expectedEvaluated: []string{namespaceB.Name},
+		},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			evaluated = nil
+			step.events()
+
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(evaluated, step.expectedEvaluated) {
+				t.Errorf("expected evaluated namespaces %v, got %v", step.expectedEvaluated, evaluated)
+			}
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, step.expectedViolating) {
+				t.Errorf("expected violating namespaces %v, got %v", step.expectedViolating, conditions.violatingCustomerNamespaces)
+			}
+		})
+	}
+}
+
+func TestChangeTrackerClusterDefaultLevel(t *testing.T) {
+	tracker := newChangeTracker()
+	tracker.setResult("test-ns", EvaluationResult{Violating: true})
+
+	tracker.setClusterDefaultLevel("")
+	if _, ok := tracker.carriedResult("test-ns"); !ok {
+		t.Fatal("expected the result to be carried forward")
+	}
+
+	tracker.setClusterDefaultLevel("restricted")
+	if _, ok := tracker.carriedResult("test-ns"); ok {
+		t.Error("expected the result to be dropped after the cluster default level changed")
+	}
+}
@@ -0,0 +1,38 @@
+package podsecurityreadinesscontroller
+
+import (
+	"sync"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	registerMetrics sync.Once
+
+	// violatingSyncsGauge holds for how many consecutive syncs each violating
+	// namespace has been violating. It changes on every sync, which is why it
+	// isn't part of the condition messages.
+	violatingSyncsGauge = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Name:           "pod_security_readiness_violating_syncs",
+		Help:           "Number of consecutive syncs a namespace has been violating the pod security level it was evaluated against.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"namespace"})
+)
+
+// RegisterMetrics registers the metrics of the controller in the global
+// registry.
+func RegisterMetrics() {
+	registerMetrics.Do(func() {
+		legacyregistry.MustRegister(violatingSyncsGauge)
+	})
+}
+
+// recordViolatingSyncs replaces the violating syncs of all namespaces, so that
+// namespaces that are no longer violating are dropped.
+func recordViolatingSyncs(syncs map[string]int) {
+	violatingSyncsGauge.Reset()
+	for ns, count := range syncs {
+		violatingSyncsGauge.WithLabelValues(ns).Set(float64(count))
+	}
+}
@@ -0,0 +1,35 @@
+package podsecurityreadinesscontroller
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestRecordViolatingSyncs(t *testing.T) {
+	RegisterMetrics()
+
+	recordViolatingSyncs(map[string]int{"ns-a": 3, "ns-b": 1})
+	expected := `
+# HELP pod_security_readiness_violating_syncs [ALPHA] Number of consecutive syncs a namespace has been violating the pod security level it was evaluated against.
+# TYPE pod_security_readiness_violating_syncs gauge
+pod_security_readiness_violating_syncs{namespace="ns-a"} 3
+pod_security_readiness_violating_syncs{namespace="ns-b"} 1
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "pod_security_readiness_violating_syncs"); err != nil {
+		t.Error(err)
+	}
+
+	// ns-a stopped violating, so it is no longer reported.
+	recordViolatingSyncs(map[string]int{"ns-b": 2})
+	expected = `
+# HELP pod_security_readiness_violating_syncs [ALPHA] Number of consecutive syncs a namespace has been violating the pod security level it was evaluated against.
+# TYPE pod_security_readiness_violating_syncs gauge
+pod_security_readiness_violating_syncs{namespace="ns-b"} 2
+`
+	if err := testutil.GatherAndCompare(legacyregistry.DefaultGatherer, strings.NewReader(expected), "pod_security_readiness_violating_syncs"); err != nil {
+		t.Error(err)
+	}
+}
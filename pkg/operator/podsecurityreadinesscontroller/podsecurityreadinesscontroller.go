@@ -2,46 +2,249 @@ package podsecurityreadinesscontroller
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/selection"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+	"k8s.io/utils/clock"
 
+	applyoperatorv1 "github.com/openshift/client-go/operator/applyconfigurations/operator/v1"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
 )
 
 const (
 	checkInterval = 240 * time.Minute // Adjust the interval as needed.
+
+	defaultViolationHandlerTimeout    = 30 * time.Second
+	defaultNamespaceEvaluationTimeout = time.Minute
+
+	// maxConcurrentViolationHandlers bounds the violation handlers that run
+	// at the same time, so that slow handlers don't pile up across syncs.
+	maxConcurrentViolationHandlers = 10
+
+	// syncFailureTolerance is how long the syncs have to keep failing before
+	// the controller reports its syncs as unhealthy. Transient failures, e.g.
+	// during an apiserver rollout, are retried without being reported.
+	syncFailureTolerance = 15 * time.Minute
+
+	// violationResolvedReason is the reason of the events emitted for
+	// namespaces that stopped violating since the previous sync.
+	violationResolvedReason = "PodSecurityViolationResolved"
 )
 
+var (
+	// defaultEnforceLevelPath points to the enforce level the PodSecurity
+	// admission plugin applies to namespaces without an enforce label.
+	defaultEnforceLevelPath = []string{"admission", "pluginConfig", "PodSecurity", "configuration", "defaults", "enforce"}
+)
+
+// ViolationHandler is invoked for every violating namespace, e.g. to notify an
+// external system. The context is cancelled once the handler timed out.
+type ViolationHandler func(ctx context.Context, ns *corev1.Namespace, result EvaluationResult) error
+
 // PodSecurityReadinessController checks if namespaces are ready for Pod Security Admission enforcement.
 type PodSecurityReadinessController struct {
 	kubeClient     kubernetes.Interface
 	operatorClient v1helpers.OperatorClient
+	recorder       events.Recorder
+	clock          clock.PassiveClock
+
+	// namespaces and pods replace the corresponding parts of kubeClient if
+	// set, see namespacesClient and podsClient.
+	namespaces namespaceClient
+	pods       podClient
+
+	// config holds the configuration the controller was created with. Its
+	// defaults are applied where it is read, see config.go.
+	config Config
+
+	warningsHandler WarningsHandler
+	// namespaceSelector selects the namespaces that don't enforce pod
+	// security yet, without the excluded ones, see NamespacesConfig.
+	namespaceSelector          string
+	enforcingNamespaceSelector string
+
+	// psaEvaluator is built from the policy checks, violationWarning is
+	// compiled from the violation warning pattern of the config.
+	psaEvaluator     policy.Evaluator
+	violationWarning *regexp.Regexp
+
+	// addOnClient discovers the add-on operators, addOnNamespaces holds the
+	// namespaces they were last found in, see refreshAddOnNamespaces.
+	addOnClient     dynamic.Interface
+	addOnNamespaces sets.Set[string]
+
+	// reportedConflicts maps the namespaces with conflicting alert levels to
+	// the conflict that was last reported for them, see
+	// reportConflictingLevels.
+	reportedConflicts map[string]string
+
+	// clusterDefaultEnforceLevel is refreshed on every sync if
+	// LevelsConfig.ClusterDefault is set.
+	clusterDefaultEnforceLevel string
+	// versionLimit is the version of the cluster if the policy checks are
+	// newer than it, and zero otherwise. It is refreshed on every sync, see
+	// clampVersion.
+	versionLimit psapi.Version
+
+	// evaluationLock serializes the evaluations of the sync and of reports,
+	// as the warnings can't be attributed to concurrent requests.
+	evaluationLock sync.Mutex
+
+	// lastConditionsLock guards lastConditions and lastReport, which hold
+	// the outcome of the last sync and of the last complete evaluation.
+	lastConditionsLock sync.RWMutex
+	lastConditions     podSecurityOperatorConditions
+	lastReport         *Report
+
+	// evaluationCache is only set if the evaluation results should be
+	// cached across syncs.
+	evaluationCache *evaluationCache
+	// levelCache holds the resolved enforce levels of namespaces across
+	// syncs, unless it is nil.
+	levelCache *levelCache
+
+	// changeTracker is only set if only namespaces that changed since the
+	// last sync should be evaluated. It is fed by the events of the
+	// incremental informers.
+	changeTracker *changeTracker
+
+	// lastWrittenResults holds the content last written to the results
+	// ConfigMap.
+	lastWrittenResults string
+
+	// dryRunVerified is set once a dry-run Apply on namespaces succeeded.
+	dryRunVerified bool
+
+	// initialized is set once the conditions of a complete evaluation were
+	// reported.
+	initialized bool
 
-	warningsHandler   *warningsHandler
-	namespaceSelector string
+	// failingSince is when the syncs started failing, it is zero while they
+	// succeed. dryRunFailingSince and listFailingSince do the same for the
+	// dry-run self-check and the namespace list.
+	failingSince       time.Time
+	dryRunFailingSince time.Time
+	listFailingSince   time.Time
+
+	// violationHandlerSlots holds a token per running violation handler. It
+	// is only set if there is a handler.
+	violationHandlerSlots chan struct{}
+
+	// violatingSince tracks when each namespace started violating
+	// continuously.
+	violatingSince map[string]time.Time
+	// violatingSyncs counts the consecutive syncs each namespace has been
+	// violating in.
+	violatingSyncs map[string]int
+	// lastSuccessfulSync is when the last sync succeeded. violatingSince and
+	// violatingSyncs are reset after the tracking reset gap without one.
+	lastSuccessfulSync time.Time
+
+	// namespaceErrors tracks the failures per namespace, see
+	// EvaluationConfig.ErrorBackoffLimit.
+	namespaceErrors map[string]namespaceErrorBackoff
+
+	// rateLimiter reduces the request rate of kubeClient for the rest of a
+	// sync once the apiserver is overloaded. It is only set by the
+	// constructor.
+	rateLimiter *backpressureLimiter
 }
 
+// NewPodSecurityReadinessController returns the controller to run along with
+// the PodSecurityReadinessController it syncs, whose methods, e.g.
+// GenerateReport, can be called independently of the sync.
 func NewPodSecurityReadinessController(
 	kubeConfig *rest.Config,
 	operatorClient v1helpers.OperatorClient,
 	recorder events.Recorder,
-) (factory.Controller, error) {
-	warningsHandler := &warningsHandler{}
+	warningsHandler WarningsHandler,
+	config Config,
+) (factory.Controller, *PodSecurityReadinessController, error) {
+	c, err := newPodSecurityReadinessController(operatorClient, recorder, warningsHandler, config)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	kubeClient, err := newWarningAwareKubeClient(warningsHandler, kubeConfig)
+	RegisterMetrics()
+
+	c.rateLimiter = newBackpressureLimiter(c.config.Client.qps(), c.config.Client.burst())
+	c.kubeClient, err = newWarningAwareKubeClient(warningsHandler, kubeConfig, c.rateLimiter)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	c.addOnClient, err = newAddOnClient(kubeConfig, c.rateLimiter)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if c.config.Evaluation.IncrementalInformers != nil {
+		c.changeTracker = newChangeTracker()
+
+		namespaceOf := func(obj metav1.Object) string { return obj.GetName() }
+		if _, err := c.config.Evaluation.IncrementalInformers.Core().V1().Namespaces().Informer().AddEventHandler(c.changeTracker.eventHandler(namespaceOf)); err != nil {
+			return nil, nil, err
+		}
+
+		namespaceOf = func(obj metav1.Object) string { return obj.GetNamespace() }
+		if _, err := c.config.Evaluation.IncrementalInformers.Core().V1().Pods().Informer().AddEventHandler(c.changeTracker.eventHandler(namespaceOf)); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	controllerFactory := factory.New().
+		WithSync(c.sync).
+		ResyncEvery(checkInterval)
+	if c.config.ResyncTrigger != nil {
+		syncCtx := factory.NewSyncContext("PodSecurityReadinessController", recorder)
+		if err := c.config.ResyncTrigger.bind(syncCtx.Queue()); err != nil {
+			return nil, nil, err
+		}
+		controllerFactory = controllerFactory.WithSyncContext(syncCtx)
+	}
+	if c.config.HealthChecker != nil {
+		controllerFactory = controllerFactory.WithPostStartHooks(c.startHealthChecker)
+	}
+
+	return controllerFactory.ToController("PodSecurityReadinessController", recorder), c, nil
+}
+
+// newPodSecurityReadinessController applies the config to the defaults and
+// validates it. The kube client is left to the caller.
+func newPodSecurityReadinessController(
+	operatorClient v1helpers.OperatorClient,
+	recorder events.Recorder,
+	warningsHandler WarningsHandler,
+	config Config,
+) (*PodSecurityReadinessController, error) {
+	if warningsHandler == nil {
+		return nil, fmt.Errorf("the warnings handler must not be nil")
 	}
 
 	selector, err := nonEnforcingSelector()
@@ -49,53 +252,816 @@ func NewPodSecurityReadinessController(
 		return nil, err
 	}
 
+	enforcingSelector, err := enforcingSelector()
+	if err != nil {
+		return nil, err
+	}
+
 	c := &PodSecurityReadinessController{
-		operatorClient:    operatorClient,
-		kubeClient:        kubeClient,
-		warningsHandler:   warningsHandler,
-		namespaceSelector: selector,
+		operatorClient:             operatorClient,
+		recorder:                   recorder,
+		clock:                      clock.RealClock{},
+		config:                     config,
+		warningsHandler:            warningsHandler,
+		namespaceSelector:          selector,
+		enforcingNamespaceSelector: enforcingSelector,
+		levelCache:                 newLevelCache(),
+	}
+	if config.Evaluation.CacheInformers != nil {
+		c.evaluationCache = newEvaluationCache(config.Evaluation.CacheInformers.Core().V1().Pods().Lister())
+	}
+	if config.Results.ViolationHandler != nil {
+		c.violationHandlerSlots = make(chan struct{}, maxConcurrentViolationHandlers)
 	}
 
-	return factory.New().
-		WithSync(c.sync).
-		ResyncEvery(checkInterval).
-		ToController("PodSecurityReadinessController", recorder), nil
+	for _, manager := range config.Levels.TrustedFieldManagers {
+		if len(manager) == 0 {
+			return nil, fmt.Errorf("the trusted field managers must not be empty")
+		}
+		if manager == config.Client.fieldManager() {
+			// The dry-run Applies of the controller would be trusted.
+			return nil, fmt.Errorf("the trusted field managers must not include the field manager %q", config.Client.fieldManager())
+		}
+	}
+	c.violationWarning, err = regexp.Compile(config.Evaluation.violationWarningPattern())
+	if err != nil {
+		return nil, fmt.Errorf("invalid violation warning pattern: %w", err)
+	}
+	if config.Conditions.FieldManager == config.Client.fieldManager() {
+		return nil, fmt.Errorf("the status field manager must differ from the field manager %q", config.Client.fieldManager())
+	}
+	if slices.Contains(config.Namespaces.CustomerOverrides, operatorclient.OperatorNamespace) {
+		return nil, fmt.Errorf("the operator namespace %q can't be reported as a customer namespace", operatorclient.OperatorNamespace)
+	}
+	if unknown := sets.New(config.Pods.Phases...).Difference(knownPodPhases); unknown.Len() > 0 {
+		return nil, fmt.Errorf("unknown pod phases: %v", sets.List(unknown))
+	}
+	if len(config.Levels.Minimum) > 0 {
+		if _, err := psapi.ParseLevel(string(config.Levels.Minimum)); err != nil {
+			return nil, fmt.Errorf("invalid minimum level: %w", err)
+		}
+	}
+	if len(config.Levels.Maximum) > 0 {
+		if _, err := psapi.ParseLevel(string(config.Levels.Maximum)); err != nil {
+			return nil, fmt.Errorf("invalid maximum level: %w", err)
+		}
+		if len(config.Levels.Minimum) > 0 && psapi.CompareLevels(config.Levels.Minimum, config.Levels.Maximum) > 0 {
+			return nil, fmt.Errorf("the minimum level %q is stricter than the maximum level %q", config.Levels.Minimum, config.Levels.Maximum)
+		}
+	}
+	if len(config.Namespaces.ExclusionLabel) > 0 {
+		c.namespaceSelector, err = excludingSelector(c.namespaceSelector, config.Namespaces.ExclusionLabel, config.Namespaces.ExclusionValues)
+		if err != nil {
+			return nil, fmt.Errorf("invalid namespace exclusion: %w", err)
+		}
+	}
+	if config.HealthChecker != nil && config.HealthChecker.staleness <= checkInterval {
+		return nil, fmt.Errorf("the staleness window of the health checker must exceed the resync interval %v, got %v", checkInterval, config.HealthChecker.staleness)
+	}
+	if config.Client.qps() <= 0 || config.Client.burst() <= 0 {
+		return nil, fmt.Errorf("the client rate limit must be positive, got %v QPS with a burst of %d", config.Client.qps(), config.Client.burst())
+	}
+
+	psaEvaluator, err := policy.NewEvaluator(config.Pods.policyChecks())
+	if err != nil {
+		return nil, err
+	}
+	c.psaEvaluator = psaEvaluator
+
+	return c, nil
 }
 
 func (c *PodSecurityReadinessController) sync(ctx context.Context, syncCtx factory.SyncContext) error {
-	nsList, err := c.kubeClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{LabelSelector: c.namespaceSelector})
+	if c.isTrackingStale() {
+		c.resetTracking()
+	}
+	if c.config.ResyncTrigger != nil && c.config.ResyncTrigger.consume() {
+		c.discardEvaluationResults()
+	}
+
+	ctx, span := c.startSpan(ctx, syncSpanName)
+	err := c.syncConditions(ctx, syncCtx)
+	endSpan(span, err)
+	if err != nil {
+		if c.failingSince.IsZero() {
+			c.failingSince = c.clock.Now()
+		}
+	} else {
+		c.failingSince = time.Time{}
+		c.lastSuccessfulSync = c.clock.Now()
+		if c.config.HealthChecker != nil {
+			c.config.HealthChecker.recordSync()
+		}
+	}
+
+	conditions := podSecurityOperatorConditions{terse: c.config.Conditions.Terse, informational: c.config.Conditions.Informational}
+	if !c.failingSince.IsZero() && c.clock.Since(c.failingSince) >= syncFailureTolerance {
+		conditions.syncFailure = err.Error()
+		conditions.failingSince = c.failingSince
+	}
+	if updateErr := c.updateStatus(ctx, conditions.toSyncHealthyConditionFuncs()...); updateErr != nil {
+		klog.ErrorS(updateErr, "Failed to report the sync health of the controller")
+	}
+
+	return err
+}
+
+// failingBeyondTolerance records in since when a check started failing and
+// returns whether it has kept failing for longer than syncFailureTolerance.
+// since must be reset once the check succeeds.
+func (c *PodSecurityReadinessController) failingBeyondTolerance(since *time.Time) bool {
+	if since.IsZero() {
+		*since = c.clock.Now()
+	}
+
+	return c.clock.Since(*since) >= syncFailureTolerance
+}
+
+// syncConditions evaluates the namespaces and updates the conditions that
+// report the results.
+func (c *PodSecurityReadinessController) syncConditions(ctx context.Context, syncCtx factory.SyncContext) error {
+	c.evaluationLock.Lock()
+	defer c.evaluationLock.Unlock()
+
+	start := c.clock.Now()
+	if c.rateLimiter != nil {
+		// Every sync starts at the configured rate, the apiserver may have
+		// recovered in the meantime.
+		c.rateLimiter.reset()
+	}
+
+	if !c.initialized {
+		// The conditions may be empty or left over from a previous run, which
+		// consumers must not act on until the first evaluation completes.
+		initializing := podSecurityOperatorConditions{terse: c.config.Conditions.Terse, informational: c.config.Conditions.Informational}
+		if err := c.updateStatus(ctx, initializing.toInitializingConditionFuncs()...); err != nil {
+			return err
+		}
+	}
+
+	if !c.dryRunVerified && c.config.Evaluation.Detector == nil {
+		conditions := podSecurityOperatorConditions{terse: c.config.Conditions.Terse, informational: c.config.Conditions.Informational}
+		if err := c.verifyDryRunApply(ctx, &conditions); err != nil {
+			return err
+		}
+		if len(conditions.dryRunFailure) > 0 {
+			// Every namespace would look like it isn't violating, so there
+			// is nothing trustworthy to report besides the failure itself.
+			c.setLastConditions(conditions)
+			return c.updateStatus(ctx, conditions.toDryRunConditionFuncs()...)
+		}
+	}
+
+	nsList, err := c.namespacesClient().List(ctx, metav1.ListOptions{LabelSelector: c.namespaceSelector})
 	if err != nil {
+		if !c.failingBeyondTolerance(&c.listFailingSince) {
+			return err
+		}
+
+		conditions := podSecurityOperatorConditions{
+			terse:         c.config.Conditions.Terse,
+			informational: c.config.Conditions.Informational,
+			listFailure:   err.Error(),
+		}
+		c.setLastConditions(conditions)
+		if updateErr := c.updateStatus(ctx, conditions.toListConditionFuncs()...); updateErr != nil {
+			klog.ErrorS(updateErr, "Failed to report the namespace list failure")
+		}
 		return err
 	}
+	c.listFailingSince = time.Time{}
 
-	conditions := podSecurityOperatorConditions{}
-	for _, ns := range nsList.Items {
+	if c.config.Levels.ClusterDefault {
+		c.clusterDefaultEnforceLevel, err = clusterDefaultEnforceLevel(c.operatorClient)
+		if err != nil {
+			klog.V(2).ErrorS(err, "Failed to determine the cluster default enforce level")
+		}
+	}
+	if c.changeTracker != nil {
+		c.changeTracker.setClusterDefaultLevel(c.clusterDefaultEnforceLevel)
+	}
+	if c.kubeClient != nil {
+		c.refreshVersionLimit()
+	}
+
+	conditions := podSecurityOperatorConditions{
+		runLevelZeroEscalation: c.config.Conditions.RunLevelZeroEscalation,
+		degradedThresholds:     c.config.Conditions.DegradedThresholds,
+		terse:                  c.config.Conditions.Terse,
+		informational:          c.config.Conditions.Informational,
+		categoryReasons:        c.config.Conditions.CategoryReasons,
+
+		blockUpgradeOnCustomerViolations: c.config.Conditions.BlockUpgradeOnCustomerViolations,
+		remediationClassified:            c.config.Evaluation.ClassifyRemediation,
+		misconfigurationChecked:          c.config.Evaluation.DetectInvalidEnforceLabels,
+		userSCCSkipped:                   c.config.Pods.SkipUserSCCCheck,
+		customerOverrides:                sets.New(c.config.Namespaces.CustomerOverrides...),
+		addOnNamespaces:                  c.refreshAddOnNamespaces(ctx),
+		collapseInconclusive:             c.config.Conditions.CollapseInconclusive,
+		failClosed:                       c.config.Evaluation.FailClosed,
+		enforcedNamespacesAudited:        c.config.Evaluation.AuditEnforcedNamespaces,
+		backOffEnabled:                   c.config.Evaluation.ErrorBackoffLimit > 0,
+		summaries:                        c.config.Conditions.Summaries,
+		diagnostics:                      c.config.Conditions.Diagnostics,
+		violationDetails:                 c.config.Conditions.ViolationDetails,
+	}
+	if c.config.Evaluation.WarningHeartbeat {
+		c.verifyWarningsCaptured(ctx, &conditions)
+	}
+
+	selector, err := labels.Parse(c.namespaceSelector)
+	if err != nil {
+		return err
+	}
+
+	// The namespace selector only lists the namespaces that don't enforce a
+	// level yet, so none are left once all of them do.
+	conditions.fullyEnforced = len(nsList.Items) == 0
+
+	// resolved maps the namespaces that violated in the previous sync, but are
+	// clean now, to the level they were evaluated against.
+	resolved := map[string]string{}
+	// nsReports holds the outcome of every namespace for GenerateReport.
+	nsReports := make([]NamespaceReport, 0, len(nsList.Items))
+	namespaces := nsList.Items
+	if c.config.Namespaces.PriorityOrdering {
+		namespaces = prioritizedNamespaces(namespaces, conditions.classify)
+	}
+	for _, ns := range namespaces {
+		if c.isBackedOff(ns.Name) {
+			conditions.addBackedOff(&ns)
+			conditions.addInconclusive(&ns)
+			nsReport := newNamespaceReport(&conditions, &ns)
+			nsReport.Error = "the evaluation is backed off after repeated failures"
+			nsReports = append(nsReports, nsReport)
+			continue
+		}
+
+		nsCtx, cancel := c.namespaceEvaluationContext(ctx)
+		nsCtx = withSharedPodLists(nsCtx)
+		nsCtx, span := c.startSpan(nsCtx, evaluateNamespaceSpanName, attribute.String(namespaceAttribute, ns.Name))
+		// evaluated and result are only set if the last attempt evaluated
+		// the namespace, optedOut if it found the namespace opted out.
+		var evaluated *corev1.Namespace
+		var result EvaluationResult
+		var optedOut bool
+		// The listed namespace is evaluated. The syncer may have labeled it
+		// since, e.g. set its enforce level, in which case the dry-run Apply
+		// conflicts and the namespace is re-read before it is evaluated again.
+		current := &ns
+		reread := false
 		err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
-			isViolating, err := c.isNamespaceViolating(ctx, &ns)
+			evaluated = nil
+			optedOut = false
+			if reread {
+				fresh, err := c.namespacesClient().Get(nsCtx, ns.Name, metav1.GetOptions{})
+				if apierrors.IsNotFound(err) {
+					return nil
+				}
+				if err != nil {
+					return err
+				}
+				current = fresh
+			}
+			reread = true
+
+			if !selector.Matches(labels.Set(current.Labels)) {
+				klog.V(4).InfoS("Namespace no longer matches the selector", "namespace", current.Name)
+				return nil
+			}
+			if isOptedOut(current) {
+				conditions.addOptedOut(current)
+				optedOut = true
+				return nil
+			}
+
+			var err error
+			result, err = c.evaluateNamespace(nsCtx, current)
 			if apierrors.IsNotFound(err) {
 				return nil
 			}
 			if err != nil {
 				return err
 			}
-			if isViolating {
-				conditions.addViolation(&ns)
+			if err := nsCtx.Err(); err != nil {
+				// Not every request is interrupted by the timeout, a result
+				// that took longer isn't reported either.
+				return fmt.Errorf("evaluation exceeded %v: %w", c.config.Evaluation.namespaceTimeout(), err)
+			}
+			if isAnnotationStale(current) {
+				conditions.addStaleAnnotation(current)
+			}
+			conditions.addResult(current, result)
+			span.SetAttributes(attribute.Bool("violating", result.Violating), attribute.Bool("inconclusive", result.Inconclusive))
+			if _, ok := c.violatingSince[current.Name]; ok && !result.Violating {
+				resolved[current.Name] = result.Level
+			}
+			if result.Violating {
+				c.recordAchievableLevel(nsCtx, &conditions, current)
+				c.recordWorkloadKinds(nsCtx, &conditions, current)
+				c.recordRemediation(nsCtx, &conditions, current)
+			}
+			if result.Inconclusive {
+				klog.V(2).InfoS("Unable to determine user SCC violations", "namespace", current.Name, "reason", result.Reason)
 			}
 
+			evaluated = current
 			return nil
 		})
+		if err == nil && evaluated != nil {
+			// The annotation is written once the evaluation succeeded,
+			// retrying it wouldn't resolve any conflict.
+			c.annotateRemediation(nsCtx, &conditions, evaluated, result)
+		}
+		endSpan(span, err)
+		cancel()
+		switch {
+		case err != nil:
+			nsReport := newNamespaceReport(&conditions, &ns)
+			nsReport.EvaluatedAt = c.clock.Now()
+			nsReport.Error = err.Error()
+			nsReports = append(nsReports, nsReport)
+		case evaluated != nil:
+			nsReport := newNamespaceReport(&conditions, evaluated)
+			nsReport.EvaluatedAt = c.clock.Now()
+			nsReport.EvaluationResult = result
+			nsReports = append(nsReports, nsReport)
+		case optedOut:
+			nsReport := newNamespaceReport(&conditions, &ns)
+			nsReport.OptedOut = true
+			nsReports = append(nsReports, nsReport)
+		}
 		if err != nil {
 			klog.V(2).ErrorS(err, "namespace:", ns.Name)
+			c.recordEvaluationError(ns.Name, err)
 
+			if c.isWithinGracePeriod(&ns) {
+				continue
+			}
+			if errors.Is(err, ErrNoLabels) && isSyncerEnabled(&ns) {
+				// The syncer lags behind or is broken, unlike for namespaces
+				// that it skips.
+				conditions.addSyncerPending(&ns)
+			}
 			conditions.addInconclusive(&ns)
+		} else {
+			c.recordEvaluationSuccess(ns.Name)
+			if evaluated != nil && result.Violating {
+				// The handler runs past the evaluation of the namespace, and
+				// only once it succeeded.
+				c.notifyViolation(ctx, evaluated, result)
+			}
+		}
+	}
+
+	listed := sets.New[string]()
+	for _, ns := range nsList.Items {
+		listed.Insert(ns.Name)
+	}
+	if c.evaluationCache != nil {
+		c.evaluationCache.retain(listed)
+	}
+	if c.levelCache != nil {
+		c.levelCache.retain(listed)
+	}
+	if c.changeTracker != nil {
+		c.changeTracker.retain(listed)
+	}
+	c.retainNamespaceErrors(listed)
+	for ns := range c.reportedConflicts {
+		if !listed.Has(ns) {
+			delete(c.reportedConflicts, ns)
+		}
+	}
+
+	conditions.compact()
+	conditions.violatingSince = c.trackViolatingSince(conditions.violatingNamespaces())
+	recordViolatingSyncs(c.trackViolatingSyncs(conditions.violatingNamespaces()))
+	for _, ns := range sets.List(sets.KeySet(resolved)) {
+		syncCtx.Recorder().Eventf(violationResolvedReason, "Namespace %s no longer violates the PodSecurity enforce level %q", ns, resolved[ns])
+	}
+
+	if c.config.Evaluation.AuditEnforcedNamespaces || c.config.Evaluation.DetectInvalidEnforceLabels {
+		if err := c.auditEnforcedNamespaces(ctx, &conditions); err != nil {
+			return err
+		}
+	}
+
+	c.setLastConditions(conditions)
+	c.setLastReport(newReport(c.clock.Now(), &conditions, nsReports))
+	keysAndValues := append([]interface{}{
+		"duration", c.clock.Since(start),
+		"policyVersion", psapi.LatestVersion().String(),
+	}, newCategoryCounts(&conditions).keysAndValues()...)
+	klog.V(2).InfoS("Evaluated namespaces for pod security readiness", keysAndValues...)
+	klog.V(4).InfoS("Pod security readiness summary", "summary", conditions.Summary())
+
+	if len(c.config.Results.ArtifactPath) > 0 {
+		// The artifact is only used for debugging and must not fail the sync.
+		if err := c.writeArtifact(&conditions); err != nil {
+			klog.ErrorS(err, "Failed to write the pod security readiness artifact", "path", c.config.Results.ArtifactPath)
 		}
 	}
 
 	// We expect the Cluster's status conditions to be picked up by the status
 	// controller and push it into the ClusterOperator's status, where it will
 	// be evaluated by the ClusterFleetMechanic.
-	_, _, err = v1helpers.UpdateStatus(ctx, c.operatorClient, conditions.toConditionFuncs()...)
-	return err
+	if err := c.updateStatus(ctx, conditions.toConditionFuncs()...); err != nil {
+		return err
+	}
+	c.initialized = true
+
+	if len(c.config.Results.ConfigMapName) > 0 {
+		return c.writeResults(ctx, &conditions)
+	}
+
+	return nil
+}
+
+// prioritizedNamespaces returns a copy of the namespaces ordered by the
+// priority of their category: run-level zero, OpenShift, then all others. The
+// order within a category is kept.
+func prioritizedNamespaces(namespaces []corev1.Namespace, classify func(*corev1.Namespace) namespaceCategory) []corev1.Namespace {
+	priority := func(ns *corev1.Namespace) int {
+		switch classify(ns) {
+		case categoryRunLevelZero:
+			return 0
+		case categoryOpenShift:
+			return 1
+		default:
+			return 2
+		}
+	}
+
+	prioritized := make([]corev1.Namespace, len(namespaces))
+	copy(prioritized, namespaces)
+	sort.SliceStable(prioritized, func(i, j int) bool {
+		return priority(&prioritized[i]) < priority(&prioritized[j])
+	})
+
+	return prioritized
+}
+
+// auditEnforcedNamespaces records namespaces that already enforce pod security
+// but contain pods that violate their enforce level, if
+// EvaluationConfig.AuditEnforcedNamespaces is set, and namespaces with an
+// invalid enforce level, if EvaluationConfig.DetectInvalidEnforceLabels is
+// set.
+func (c *PodSecurityReadinessController) auditEnforcedNamespaces(ctx context.Context, conditions *podSecurityOperatorConditions) error {
+	nsList, err := c.namespacesClient().List(ctx, metav1.ListOptions{LabelSelector: c.enforcingNamespaceSelector})
+	if err != nil {
+		return err
+	}
+
+	for _, ns := range nsList.Items {
+		if _, err := psapi.ParseLevel(ns.Labels[psapi.EnforceLevelLabel]); err != nil && c.config.Evaluation.DetectInvalidEnforceLabels {
+			conditions.addMisconfigured(&ns)
+			continue
+		}
+		if !c.config.Evaluation.AuditEnforcedNamespaces {
+			continue
+		}
+
+		isRegressed, err := c.isEnforcedNamespaceRegressed(ctx, &ns)
+		if err != nil {
+			klog.V(2).ErrorS(err, "namespace:", ns.Name)
+
+			conditions.addInconclusive(&ns)
+			continue
+		}
+		if isRegressed {
+			conditions.addEnforcedRegression(&ns)
+		}
+	}
+
+	return nil
+}
+
+// namespaceEvaluationContext bounds the evaluation of a single namespace by the
+// namespace evaluation timeout, if any.
+func (c *PodSecurityReadinessController) namespaceEvaluationContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.config.Evaluation.namespaceTimeout() <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.config.Evaluation.namespaceTimeout())
+}
+
+// isWithinGracePeriod checks whether the namespace was created so recently
+// that it shouldn't be reported as inconclusive yet.
+func (c *PodSecurityReadinessController) isWithinGracePeriod(ns *corev1.Namespace) bool {
+	if c.config.Namespaces.NewNamespaceGracePeriod <= 0 {
+		return false
+	}
+
+	return c.clock.Since(ns.CreationTimestamp.Time) < c.config.Namespaces.NewNamespaceGracePeriod
+}
+
+// recordAchievableLevel records the strictest level the violating namespace
+// would satisfy, if probing is enabled.
+func (c *PodSecurityReadinessController) recordAchievableLevel(ctx context.Context, conditions *podSecurityOperatorConditions, ns *corev1.Namespace) {
+	if !c.config.Evaluation.ProbeAchievableLevels {
+		return
+	}
+
+	level, ok := conditions.evaluatedLevels[ns.Name]
+	if !ok {
+		return
+	}
+
+	achievable, err := c.achievableLevel(ctx, ns, level)
+	if err != nil {
+		klog.V(2).ErrorS(err, "Failed to determine the achievable level", "namespace", ns.Name)
+		return
+	}
+
+	conditions.addAchievableLevel(ns, achievable)
+}
+
+// recordRemediation records whether the violating namespace can be fixed by
+// relaxing its enforce level, if classification is enabled. Pods that were
+// admitted through a user-bound SCC and violate baseline, the least strict
+// level that restricts anything, can't be fixed by any label.
+func (c *PodSecurityReadinessController) recordRemediation(ctx context.Context, conditions *podSecurityOperatorConditions, ns *corev1.Namespace) {
+	if !c.config.Evaluation.ClassifyRemediation {
+		return
+	}
+
+	level, ok := conditions.evaluatedLevels[ns.Name]
+	if !ok {
+		return
+	}
+
+	achievable, ok := conditions.achievableLevels[ns.Name]
+	if !ok {
+		var err error
+		achievable, err = c.achievableLevel(ctx, ns, level)
+		if err != nil {
+			klog.V(2).ErrorS(err, "Failed to determine the achievable level", "namespace", ns.Name)
+			return
+		}
+	}
+	if achievable != string(psapi.LevelPrivileged) {
+		conditions.addLabelFixable(ns)
+		return
+	}
+	if c.config.Pods.SkipUserSCCCheck || isOperatorNamespace(ns) {
+		// Without the user SCC check, the namespace can't be classified.
+		return
+	}
+
+	userViolation, err := c.isUserViolation(ctx, ns, string(psapi.LevelBaseline))
+	if err != nil {
+		klog.V(2).ErrorS(err, "Failed to determine whether user workloads violate baseline", "namespace", ns.Name)
+		return
+	}
+	if userViolation {
+		conditions.addWorkloadBlocking(ns)
+	} else {
+		conditions.addLabelFixable(ns)
+	}
+}
+
+// recordWorkloadKinds records the kinds of the workloads that own violating
+// pods, as a remediation hint for customer namespaces, if enabled.
+func (c *PodSecurityReadinessController) recordWorkloadKinds(ctx context.Context, conditions *podSecurityOperatorConditions, ns *corev1.Namespace) {
+	if !c.config.Pods.WorkloadKindHints || c.config.Pods.SkipUserSCCCheck || conditions.classify(ns) != categoryCustomer {
+		return
+	}
+
+	level, ok := conditions.evaluatedLevels[ns.Name]
+	if !ok {
+		return
+	}
+
+	kinds, err := c.violatingWorkloadKinds(ctx, ns, level)
+	if err != nil {
+		klog.V(2).ErrorS(err, "Failed to determine the violating workloads", "namespace", ns.Name)
+		return
+	}
+
+	conditions.addWorkloadKinds(ns, kinds)
+}
+
+// notifyViolation passes the violating namespace to the violation handler, if
+// there is one, without waiting for it.
+func (c *PodSecurityReadinessController) notifyViolation(ctx context.Context, ns *corev1.Namespace, result EvaluationResult) {
+	if c.config.Results.ViolationHandler == nil {
+		return
+	}
+
+	select {
+	case c.violationHandlerSlots <- struct{}{}:
+	default:
+		klog.InfoS("Skipping the violation handler, too many handlers are still running", "namespace", ns.Name, "limit", cap(c.violationHandlerSlots))
+		return
+	}
+
+	ns = ns.DeepCopy()
+	go func() {
+		defer func() { <-c.violationHandlerSlots }()
+		handlerCtx, cancel := context.WithTimeout(ctx, c.config.Results.violationHandlerTimeout())
+		defer cancel()
+
+		if err := c.config.Results.ViolationHandler(handlerCtx, ns, result); err != nil {
+			klog.ErrorS(err, "Violation handler failed", "namespace", ns.Name)
+		}
+	}()
+}
+
+// trackViolatingSince updates when each of the given namespaces started
+// violating, forgets the namespaces that are no longer violating and returns
+// since when each namespace has been violating.
+func (c *PodSecurityReadinessController) trackViolatingSince(namespaces []string) map[string]time.Time {
+	now := c.clock.Now()
+
+	violatingSince := make(map[string]time.Time, len(namespaces))
+	for _, ns := range namespaces {
+		since, ok := c.violatingSince[ns]
+		if !ok {
+			since = now
+		}
+
+		violatingSince[ns] = since
+	}
+	c.violatingSince = violatingSince
+
+	return maps.Clone(violatingSince)
+}
+
+// isTrackingStale checks whether no sync succeeded within the tracking reset
+// gap. The first sync has nothing to reset.
+func (c *PodSecurityReadinessController) isTrackingStale() bool {
+	if c.config.Evaluation.TrackingResetGap <= 0 || c.lastSuccessfulSync.IsZero() {
+		return false
+	}
+
+	return c.clock.Since(c.lastSuccessfulSync) > c.config.Evaluation.TrackingResetGap
+}
+
+// resetTracking forgets which namespaces have been violating, so that the
+// next sync starts tracking from scratch.
+func (c *PodSecurityReadinessController) resetTracking() {
+	c.evaluationLock.Lock()
+	defer c.evaluationLock.Unlock()
+
+	klog.V(2).InfoS("Resetting the tracked violations after a gap between syncs", "lastSuccessfulSync", c.lastSuccessfulSync, "gap", c.config.Evaluation.TrackingResetGap)
+	c.violatingSince = nil
+	c.violatingSyncs = nil
+}
+
+// trackViolatingSyncs counts another sync for each of the given namespaces and
+// resets the count of the namespaces that are no longer violating. It returns
+// for how many consecutive syncs each namespace has been violating.
+func (c *PodSecurityReadinessController) trackViolatingSyncs(namespaces []string) map[string]int {
+	violatingSyncs := make(map[string]int, len(namespaces))
+	for _, ns := range namespaces {
+		violatingSyncs[ns] = c.violatingSyncs[ns] + 1
+	}
+	c.violatingSyncs = violatingSyncs
+
+	return maps.Clone(violatingSyncs)
+}
+
+func (c *PodSecurityReadinessController) setLastConditions(conditions podSecurityOperatorConditions) {
+	c.lastConditionsLock.Lock()
+	defer c.lastConditionsLock.Unlock()
+
+	c.lastConditions = conditions.deepCopy()
+}
+
+// setLastReport keeps the report of the last complete evaluation for
+// GenerateReport.
+func (c *PodSecurityReadinessController) setLastReport(report *Report) {
+	c.lastConditionsLock.Lock()
+	defer c.lastConditionsLock.Unlock()
+
+	c.lastReport = report
+}
+
+// updateStatus applies the condition updates to the operator status, or only
+// logs them if the status is updated with a dry run.
+func (c *PodSecurityReadinessController) updateStatus(ctx context.Context, updateFuncs ...v1helpers.UpdateStatusFunc) error {
+	if !c.config.Conditions.DryRun {
+		if len(c.config.Conditions.FieldManager) > 0 {
+			return c.applyStatus(ctx, updateFuncs...)
+		}
+		_, _, err := v1helpers.UpdateStatus(ctx, c.operatorClient, updateFuncs...)
+		return err
+	}
+
+	_, oldStatus, _, err := c.operatorClient.GetOperatorState()
+	if err != nil {
+		return err
+	}
+
+	newStatus := oldStatus.DeepCopy()
+	for _, update := range updateFuncs {
+		if err := update(newStatus); err != nil {
+			return err
+		}
+	}
+
+	for _, condition := range newStatus.Conditions {
+		oldCondition := v1helpers.FindOperatorCondition(oldStatus.Conditions, condition.Type)
+		if oldCondition != nil &&
+			oldCondition.Status == condition.Status &&
+			oldCondition.Reason == condition.Reason &&
+			oldCondition.Message == condition.Message {
+			continue
+		}
+
+		klog.InfoS("Would update condition", "type", condition.Type, "status", condition.Status, "reason", condition.Reason, "message", condition.Message)
+	}
+	for _, condition := range oldStatus.Conditions {
+		if v1helpers.FindOperatorCondition(newStatus.Conditions, condition.Type) == nil {
+			klog.InfoS("Would remove condition", "type", condition.Type)
+		}
+	}
+	klog.V(2).InfoS("Intended conditions", "conditions", newStatus.Conditions)
+
+	return nil
+}
+
+// applyStatus applies the condition updates to the operator status with the
+// status field manager. Conditions the field manager applied before but omits
+// are removed, so every Apply lists all conditions of the controller, based on
+// a live read of the status.
+func (c *PodSecurityReadinessController) applyStatus(ctx context.Context, updateFuncs ...v1helpers.UpdateStatusFunc) error {
+	_, oldStatus, _, err := c.operatorClient.GetOperatorStateWithQuorum(ctx)
+	if err != nil {
+		return err
+	}
+
+	newStatus := oldStatus.DeepCopy()
+	for _, update := range updateFuncs {
+		if err := update(newStatus); err != nil {
+			return err
+		}
+	}
+
+	status := applyoperatorv1.OperatorStatus()
+	for _, condition := range newStatus.Conditions {
+		if !strings.HasPrefix(condition.Type, conditionTypePrefix) {
+			continue
+		}
+
+		status.WithConditions(applyoperatorv1.OperatorCondition().
+			WithType(condition.Type).
+			WithStatus(condition.Status).
+			WithReason(condition.Reason).
+			WithMessage(condition.Message).
+			WithLastTransitionTime(condition.LastTransitionTime))
+	}
+
+	return c.operatorClient.ApplyOperatorStatus(ctx, c.config.Conditions.FieldManager, status)
+}
+
+// snapshot returns a copy of the conditions computed by the last sync.
+func (c *PodSecurityReadinessController) snapshot() podSecurityOperatorConditions {
+	c.lastConditionsLock.RLock()
+	defer c.lastConditionsLock.RUnlock()
+
+	return c.lastConditions.deepCopy()
+}
+
+// Snapshot returns the namespaces of every category as computed by the last
+// sync, without evaluating any namespace. The slices are copies, so callers
+// can't change the state of the controller through them.
+func (c *PodSecurityReadinessController) Snapshot() CategorizedNamespaces {
+	c.lastConditionsLock.RLock()
+	defer c.lastConditionsLock.RUnlock()
+
+	return newCategorizedNamespaces(&c.lastConditions)
+}
+
+// clusterDefaultEnforceLevel reads the default enforce level of the PodSecurity
+// admission plugin from the observed config.
+func clusterDefaultEnforceLevel(operatorClient v1helpers.OperatorClient) (string, error) {
+	spec, _, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		return "", err
+	}
+
+	observedConfig := map[string]interface{}{}
+	if len(spec.ObservedConfig.Raw) > 0 {
+		if err := json.Unmarshal(spec.ObservedConfig.Raw, &observedConfig); err != nil {
+			return "", err
+		}
+	}
+
+	level, found, err := unstructured.NestedString(observedConfig, defaultEnforceLevelPath...)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("no default enforce level found in the observed config")
+	}
+
+	if _, err := psapi.ParseLevel(level); err != nil {
+		return "", err
+	}
+
+	return level, nil
 }
 
 func nonEnforcingSelector() (string, error) {
@@ -108,13 +1074,45 @@ func nonEnforcingSelector() (string, error) {
 	return selector.Add(*labelsRequirement).String(), nil
 }
 
-func newWarningAwareKubeClient(warningsHandler *warningsHandler, kubeConfig *rest.Config) (*kubernetes.Clientset, error) {
+// excludingSelector narrows the selector down to the namespaces whose label
+// doesn't have any of the values, or that don't have the label at all if no
+// values are given.
+func excludingSelector(selector, label string, values []string) (string, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return "", err
+	}
+
+	operator := selection.NotIn
+	if len(values) == 0 {
+		operator = selection.DoesNotExist
+	}
+	labelsRequirement, err := labels.NewRequirement(label, operator, values)
+	if err != nil {
+		return "", err
+	}
+
+	return parsed.Add(*labelsRequirement).String(), nil
+}
+
+func enforcingSelector() (string, error) {
+	selector := labels.NewSelector()
+	labelsRequirement, err := labels.NewRequirement(psapi.EnforceLevelLabel, selection.Exists, []string{})
+	if err != nil {
+		return "", err
+	}
+
+	return selector.Add(*labelsRequirement).String(), nil
+}
+
+// newWarningAwareKubeClient returns a client that passes the warnings it
+// receives to the handler. Requests are limited by the rate limiter, which is
+// throttled by 429 responses.
+func newWarningAwareKubeClient(warningsHandler WarningsHandler, kubeConfig *rest.Config, rateLimiter *backpressureLimiter) (*kubernetes.Clientset, error) {
 	kubeClientCopy := rest.CopyConfig(kubeConfig)
 	kubeClientCopy.WarningHandler = warningsHandler
-	// We don't want to overwhelm the apiserver with requests. On a cluster with
-	// 10k namespaces, we would send 10k + 1 requests to the apiserver.
-	kubeClientCopy.QPS = 2
-	kubeClientCopy.Burst = 2
+	kubeClientCopy.RateLimiter = rateLimiter
+	kubeClientCopy.Wrap(rateLimiter.wrapTransport)
 
 	return kubernetes.NewForConfig(kubeClientCopy)
 }
@@ -3,16 +3,40 @@ package podsecurityreadinesscontroller
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr/funcr"
+	operatorv1 "github.com/openshift/api/operator/v1"
 	securityv1 "github.com/openshift/api/security/v1"
+	applyoperatorv1 "github.com/openshift/client-go/operator/applyconfigurations/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
 	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/klog/v2"
 	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+
+	"github.com/openshift/cluster-kube-apiserver-operator/pkg/operator/operatorclient"
 )
 
 func TestPodSecurityViolationController(t *testing.T) {
@@ -334,6 +358,7 @@ func TestPodSecurityViolationController(t *testing.T) {
 	} {
 		tt := tt
 		t.Run(tt.name, func(t *testing.T) {
+			handler := &warningsHandler{}
 			fakeClient := fake.NewSimpleClientset()
 			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
 				patchAction, ok := action.(clienttesting.PatchAction)
@@ -347,23 +372,22 @@ func TestPodSecurityViolationController(t *testing.T) {
 					return false, nil, fmt.Errorf("failed to unmarshal patch: %v", err)
 				}
 
+				handleWarnings(handler, tt.warnings)
 				return true, nil, nil
 			})
 
 			controller := &PodSecurityReadinessController{
-				kubeClient: fakeClient,
-				warningsHandler: &warningsHandler{
-					warnings: tt.warnings,
-				},
+				kubeClient:      fakeClient,
+				warningsHandler: handler,
 			}
 
-			isViolating, err := controller.isNamespaceViolating(context.TODO(), tt.namespace)
+			result, err := controller.evaluateNamespaceViolation(context.TODO(), tt.namespace)
 			if (err != nil) != tt.expectedError {
 				t.Errorf("expected error %v, got %v", tt.expectedError, err)
 			}
 
-			if isViolating != tt.expectedViolation {
-				t.Errorf("expected violation %v, got %v", tt.expectedViolation, isViolating)
+			if result.Violating != tt.expectedViolation {
+				t.Errorf("expected violation %v, got %v", tt.expectedViolation, result.Violating)
 			}
 		})
 	}
@@ -414,3 +438,2349 @@ func TestNamespaceSelector(t *testing.T) {
 		}
 	}
 }
+
+func TestNamespaceExclusion(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "deferred",
+			Labels: map[string]string{"psa-migration": "deferred"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "scheduled",
+			Labels: map[string]string{"psa-migration": "scheduled"},
+		}},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "enforcing",
+			Labels: map[string]string{psapi.EnforceLevelLabel: "restricted"},
+		}},
+	)
+
+	base, err := nonEnforcingSelector()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name     string
+		values   []string
+		expected []string
+	}{
+		{
+			name:     "excluded value",
+			values:   []string{"deferred"},
+			expected: []string{"scheduled", "unlabeled"},
+		},
+		{
+			name:     "excluded values",
+			values:   []string{"deferred", "scheduled"},
+			expected: []string{"unlabeled"},
+		},
+		{
+			name:     "excluded label",
+			expected: []string{"unlabeled"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			selector, err := excludingSelector(base, "psa-migration", tt.values)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nsList, err := fakeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := []string{}
+			for _, ns := range nsList.Items {
+				actual = append(actual, ns.Name)
+			}
+			sort.Strings(actual)
+			if !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected namespaces %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+
+	t.Run("invalid label", func(t *testing.T) {
+		_, _, err := NewPodSecurityReadinessController(
+			&rest.Config{Host: "https://localhost:6443"},
+			v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			events.NewInMemoryRecorder("test", clock.RealClock{}),
+			NewWarningsHandler(),
+			Config{Namespaces: NamespacesConfig{ExclusionLabel: "psa migration", ExclusionValues: []string{"deferred"}}},
+		)
+		if err == nil {
+			t.Error("expected an invalid exclusion label to be rejected")
+		}
+	})
+}
+
+func TestSnapshot(t *testing.T) {
+	controller := &PodSecurityReadinessController{}
+
+	conditions := podSecurityOperatorConditions{}
+	conditions.addViolation(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "customer-ns"}})
+	conditions.addInconclusive(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "inconclusive-ns"}})
+	controller.setLastConditions(conditions)
+
+	// Mutating the conditions after storing them must not leak into the snapshot.
+	conditions.violatingCustomerNamespaces[0] = "mutated-after-store"
+
+	snapshot := controller.snapshot()
+	if len(snapshot.violatingCustomerNamespaces) != 1 || snapshot.violatingCustomerNamespaces[0] != "customer-ns" {
+		t.Fatalf("unexpected customer namespaces in snapshot: %v", snapshot.violatingCustomerNamespaces)
+	}
+
+	// Mutating a snapshot must not leak into subsequent snapshots.
+	snapshot.violatingCustomerNamespaces[0] = "mutated-snapshot"
+	snapshot.inconclusiveNamespaces = append(snapshot.inconclusiveNamespaces, "appended-ns")
+
+	snapshot = controller.snapshot()
+	if snapshot.violatingCustomerNamespaces[0] != "customer-ns" {
+		t.Errorf("expected customer namespace to be unchanged, got %q", snapshot.violatingCustomerNamespaces[0])
+	}
+	if len(snapshot.inconclusiveNamespaces) != 1 {
+		t.Errorf("expected a single inconclusive namespace, got %v", snapshot.inconclusiveNamespaces)
+	}
+
+	// The same applies to the exported snapshot.
+	categories := controller.Snapshot()
+	categories.Customer[0] = "mutated-categories"
+	categories.Inconclusive = append(categories.Inconclusive, "appended-ns")
+
+	categories = controller.Snapshot()
+	if !reflect.DeepEqual(categories.Customer, []string{"customer-ns"}) || !reflect.DeepEqual(categories.Inconclusive, []string{"inconclusive-ns"}) {
+		t.Errorf("expected the exported snapshot to be unchanged, got %+v", categories)
+	}
+}
+
+func TestClusterDefaultEvaluation(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		observedConfig string
+		warnings       []string
+
+		expectedLevel          string
+		expectedLevelError     bool
+		expectedViolation      bool
+		expectedAppliedEnforce string
+	}{
+		{
+			name:                   "restricted cluster default",
+			observedConfig:         `{"admission":{"pluginConfig":{"PodSecurity":{"configuration":{"defaults":{"enforce":"restricted"}}}}}}`,
+			warnings:               []string{"existing pods in namespace \"unlabeled\" violate the new PodSecurity enforce level \"restricted:latest\""},
+			expectedLevel:          "restricted",
+			expectedViolation:      true,
+			expectedAppliedEnforce: "restricted",
+		},
+		{
+			name:                   "baseline cluster default",
+			observedConfig:         `{"admission":{"pluginConfig":{"PodSecurity":{"configuration":{"defaults":{"enforce":"baseline"}}}}}}`,
+			expectedLevel:          "baseline",
+			expectedViolation:      false,
+			expectedAppliedEnforce: "baseline",
+		},
+		{
+			name:               "missing cluster default",
+			observedConfig:     `{}`,
+			expectedLevelError: true,
+		},
+		{
+			name:               "invalid cluster default",
+			observedConfig:     `{"admission":{"pluginConfig":{"PodSecurity":{"configuration":{"defaults":{"enforce":"invalid-to-force-substitution"}}}}}}`,
+			expectedLevelError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			operatorClient := v1helpers.NewFakeOperatorClient(
+				&operatorv1.OperatorSpec{
+					ObservedConfig: runtime.RawExtension{Raw: []byte(tt.observedConfig)},
+				},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+
+			level, err := clusterDefaultEnforceLevel(operatorClient)
+			if (err != nil) != tt.expectedLevelError {
+				t.Fatalf("expected error %v, got %v", tt.expectedLevelError, err)
+			}
+			if level != tt.expectedLevel {
+				t.Errorf("expected level %q, got %q", tt.expectedLevel, level)
+			}
+			if tt.expectedLevelError {
+				return
+			}
+
+			var appliedEnforce string
+			handler := &warningsHandler{}
+			fakeClient := fake.NewSimpleClientset()
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				ns := &corev1.Namespace{}
+				if err := json.Unmarshal(action.(clienttesting.PatchAction).GetPatch(), ns); err != nil {
+					return true, nil, err
+				}
+				appliedEnforce = ns.Labels[psapi.EnforceLevelLabel]
+
+				handleWarnings(handler, tt.warnings)
+				return true, nil, nil
+			})
+
+			controller := &PodSecurityReadinessController{
+				kubeClient:                 fakeClient,
+				warningsHandler:            handler,
+				clusterDefaultEnforceLevel: level,
+			}
+
+			result, err := controller.evaluateNamespaceViolation(context.TODO(), &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Violating != tt.expectedViolation {
+				t.Errorf("expected violation %v, got %v", tt.expectedViolation, result.Violating)
+			}
+			if result.Level != tt.expectedAppliedEnforce {
+				t.Errorf("expected evaluated level %q, got %q", tt.expectedAppliedEnforce, result.Level)
+			}
+			if appliedEnforce != tt.expectedAppliedEnforce {
+				t.Errorf("expected enforce label %q, got %q", tt.expectedAppliedEnforce, appliedEnforce)
+			}
+		})
+	}
+}
+
+func TestForbiddenPodList(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "violating-namespace",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	})
+	handler := &warningsHandler{}
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		handleWarnings(handler, []string{"existing pods in namespace \"violating-namespace\" violate the new PodSecurity enforce level \"restricted:latest\""})
+		return true, nil, nil
+	})
+	fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, apierrors.NewForbidden(corev1.Resource("pods"), "", fmt.Errorf("not allowed"))
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: handler,
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conditions := controller.snapshot()
+	if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, []string{"violating-namespace"}) {
+		t.Errorf("expected namespace to be violating, got %v", conditions.violatingCustomerNamespaces)
+	}
+	if !reflect.DeepEqual(conditions.userSCCInconclusiveNamespaces, []string{"violating-namespace"}) {
+		t.Errorf("expected user SCC evaluation to be inconclusive, got %v", conditions.userSCCInconclusiveNamespaces)
+	}
+	if len(conditions.inconclusiveNamespaces) != 0 {
+		t.Errorf("expected no inconclusive namespaces, got %v", conditions.inconclusiveNamespaces)
+	}
+}
+
+func TestForbiddenNamespaceList(t *testing.T) {
+	forbidden := true
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("list", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if !forbidden {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewForbidden(corev1.Resource("namespaces"), "", fmt.Errorf("not allowed"))
+	})
+
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  operatorClient,
+		clock:           fakeClock,
+		warningsHandler: &warningsHandler{},
+		dryRunVerified:  true,
+	}
+
+	expectCondition := func(t *testing.T, status operatorv1.ConditionStatus, reason string) {
+		t.Helper()
+
+		_, operatorStatus, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		condition := v1helpers.FindOperatorCondition(operatorStatus.Conditions, PodSecurityNamespaceListDegradedType)
+		if condition == nil {
+			t.Fatalf("expected condition %s to be set", PodSecurityNamespaceListDegradedType)
+		}
+		if condition.Status != status || condition.Reason != reason {
+			t.Errorf("expected condition %s with reason %s, got %s with reason %s", status, reason, condition.Status, condition.Reason)
+		}
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); !apierrors.IsForbidden(err) {
+		t.Fatalf("expected a Forbidden error, got %v", err)
+	}
+
+	// The failure isn't reported while it is tolerated.
+	_, status, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityNamespaceListDegradedType); condition != nil {
+		t.Errorf("expected condition %s not to be set within the tolerance, got %v", PodSecurityNamespaceListDegradedType, condition)
+	}
+
+	fakeClock.SetTime(fakeClock.Now().Add(syncFailureTolerance))
+	if err := controller.sync(context.TODO(), syncCtx); !apierrors.IsForbidden(err) {
+		t.Fatalf("expected a Forbidden error, got %v", err)
+	}
+	expectCondition(t, operatorv1.ConditionTrue, listFailedReason)
+
+	_, status, _, err = operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityCustomerType); condition != nil {
+		t.Errorf("expected no violation condition to be reported, got %v", condition)
+	}
+
+	forbidden = false
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectCondition(t, operatorv1.ConditionFalse, expectedReason)
+}
+
+func TestSyncFailureHealth(t *testing.T) {
+	failing := true
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("list", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if !failing {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewServiceUnavailable("apiserver unavailable")
+	})
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clocktesting.NewFakePassiveClock(start)
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{
+		Conditions: []operatorv1.OperatorCondition{{Type: legacyReadinessAvailableType, Status: operatorv1.ConditionFalse}},
+	}, nil)
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  operatorClient,
+		clock:           fakeClock,
+		warningsHandler: &warningsHandler{},
+		dryRunVerified:  true,
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+
+	expectHealthy := func(t *testing.T, status operatorv1.ConditionStatus) *operatorv1.OperatorCondition {
+		t.Helper()
+
+		_, operatorStatus, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if v1helpers.FindOperatorCondition(operatorStatus.Conditions, legacyReadinessAvailableType) != nil {
+			t.Errorf("expected condition %s to be removed", legacyReadinessAvailableType)
+		}
+		condition := v1helpers.FindOperatorCondition(operatorStatus.Conditions, PodSecurityReadinessSyncHealthyType)
+		if condition == nil {
+			t.Fatalf("expected condition %s to be set", PodSecurityReadinessSyncHealthyType)
+		}
+		if condition.Status != status {
+			t.Errorf("expected condition status %s, got %s: %s", status, condition.Status, condition.Message)
+		}
+		return condition
+	}
+
+	// Failures are tolerated, however many syncs fail, until they last long
+	// enough.
+	for _, offset := range []time.Duration{0, time.Second, 2 * time.Second, syncFailureTolerance - time.Second} {
+		fakeClock.SetTime(start.Add(offset))
+		if err := controller.sync(context.TODO(), syncCtx); err == nil {
+			t.Fatalf("expected the sync after %v to fail", offset)
+		}
+		expectHealthy(t, operatorv1.ConditionTrue)
+	}
+
+	fakeClock.SetTime(start.Add(syncFailureTolerance))
+	if err := controller.sync(context.TODO(), syncCtx); err == nil {
+		t.Fatal("expected the sync to fail")
+	}
+	condition := expectHealthy(t, operatorv1.ConditionFalse)
+	if !strings.Contains(condition.Message, "since 2024-01-01T00:00:00Z") {
+		t.Errorf("expected the message to report when the syncs started failing, got %q", condition.Message)
+	}
+
+	// The message doesn't change while the syncs keep failing.
+	fakeClock.SetTime(start.Add(2 * syncFailureTolerance))
+	if err := controller.sync(context.TODO(), syncCtx); err == nil {
+		t.Fatal("expected the sync to fail")
+	}
+	if next := expectHealthy(t, operatorv1.ConditionFalse); next.Message != condition.Message {
+		t.Errorf("expected message %q to be unchanged, got %q", condition.Message, next.Message)
+	}
+
+	failing = false
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !controller.failingSince.IsZero() {
+		t.Errorf("expected the failures to be reset, got %v", controller.failingSince)
+	}
+	expectHealthy(t, operatorv1.ConditionTrue)
+}
+
+func TestInitializingCondition(t *testing.T) {
+	for _, terse := range []bool{false, true} {
+		t.Run(fmt.Sprintf("terse=%v", terse), func(t *testing.T) {
+			failing := true
+			fakeClient := fake.NewSimpleClientset()
+			fakeClient.PrependReactor("list", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if !failing {
+					return false, nil, nil
+				}
+				return true, nil, apierrors.NewServiceUnavailable("apiserver unavailable")
+			})
+
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  operatorClient,
+				clock:           clock.RealClock{},
+				warningsHandler: &warningsHandler{},
+				dryRunVerified:  true,
+				config:          Config{Conditions: ConditionsConfig{Terse: terse}},
+			}
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+
+			findInitializing := func(t *testing.T) *operatorv1.OperatorCondition {
+				t.Helper()
+
+				_, operatorStatus, _, err := operatorClient.GetOperatorState()
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return v1helpers.FindOperatorCondition(operatorStatus.Conditions, PodSecurityReadinessInitializingType)
+			}
+
+			if err := controller.sync(context.TODO(), syncCtx); err == nil {
+				t.Fatal("expected the first sync to fail")
+			}
+			condition := findInitializing(t)
+			if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != initializingReason {
+				t.Fatalf("expected the controller to be initializing after an incomplete sync, got %v", condition)
+			}
+
+			failing = false
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			condition = findInitializing(t)
+			switch {
+			case terse && condition != nil:
+				t.Errorf("expected the initializing condition to be removed, got %v", condition)
+			case !terse && (condition == nil || condition.Status != operatorv1.ConditionFalse):
+				t.Errorf("expected the initializing condition to be cleared, got %v", condition)
+			}
+			if !controller.initialized {
+				t.Error("expected the controller to be initialized after a complete sync")
+			}
+		})
+	}
+}
+
+func TestFullyEnforcedCondition(t *testing.T) {
+	selector, err := nonEnforcingSelector()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failing := true
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "enforcing-namespace",
+			Labels: map[string]string{psapi.EnforceLevelLabel: "restricted"},
+		},
+	})
+	fakeClient.PrependReactor("list", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if !failing {
+			return false, nil, nil
+		}
+		return true, nil, apierrors.NewServiceUnavailable("apiserver unavailable")
+	})
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, nil
+	})
+
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	controller := &PodSecurityReadinessController{
+		kubeClient:        fakeClient,
+		operatorClient:    operatorClient,
+		clock:             clock.RealClock{},
+		warningsHandler:   &warningsHandler{},
+		dryRunVerified:    true,
+		namespaceSelector: selector,
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+
+	findFullyEnforced := func(t *testing.T) *operatorv1.OperatorCondition {
+		t.Helper()
+
+		_, operatorStatus, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return v1helpers.FindOperatorCondition(operatorStatus.Conditions, PodSecurityFullyEnforcedType)
+	}
+
+	t.Run("not synced yet", func(t *testing.T) {
+		if err := controller.sync(context.TODO(), syncCtx); err == nil {
+			t.Fatal("expected the sync to fail")
+		}
+		if condition := findFullyEnforced(t); condition != nil {
+			t.Errorf("expected no fully enforced condition before a complete sync, got %v", condition)
+		}
+	})
+
+	t.Run("all namespaces enforcing", func(t *testing.T) {
+		failing = false
+		if err := controller.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		condition := findFullyEnforced(t)
+		if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != fullyEnforcedReason {
+			t.Errorf("expected the fully enforced condition to be raised, got %v", condition)
+		}
+	})
+
+	t.Run("namespace without an enforced level", func(t *testing.T) {
+		if _, err := fakeClient.CoreV1().Namespaces().Create(context.TODO(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "clean-namespace",
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		}, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := controller.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		condition := findFullyEnforced(t)
+		if condition == nil || condition.Status != operatorv1.ConditionFalse {
+			t.Errorf("expected the fully enforced condition to be cleared, got %v", condition)
+		}
+	})
+}
+
+func TestOperatorNamespace(t *testing.T) {
+	newNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+	// userPod violates baseline and was admitted through a user-bound SCC.
+	userPod := func(namespace string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "user-pod",
+				Namespace: namespace,
+				Annotations: map[string]string{
+					securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+				},
+			},
+			Spec: corev1.PodSpec{HostNetwork: true},
+		}
+	}
+
+	t.Run("sync", func(t *testing.T) {
+		psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		handler := &warningsHandler{}
+		fakeClient := fake.NewSimpleClientset(
+			newNamespace(operatorclient.OperatorNamespace), userPod(operatorclient.OperatorNamespace),
+			newNamespace("customer-ns"), userPod("customer-ns"),
+		)
+		fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			name := action.(clienttesting.PatchAction).GetName()
+			handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"restricted:latest\"", name))
+			return true, nil, nil
+		})
+
+		operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+		controller := &PodSecurityReadinessController{
+			kubeClient:      fakeClient,
+			operatorClient:  operatorClient,
+			clock:           clock.RealClock{},
+			warningsHandler: handler,
+			dryRunVerified:  true,
+			psaEvaluator:    psaEvaluator,
+			// Even an override must not route the operator namespace to
+			// the customer conditions.
+			config: Config{
+				Namespaces: NamespacesConfig{CustomerOverrides: []string{operatorclient.OperatorNamespace}},
+				Pods:       PodsConfig{WorkloadKindHints: true},
+				Evaluation: EvaluationConfig{ClassifyRemediation: true},
+				Conditions: ConditionsConfig{BlockUpgradeOnCustomerViolations: true},
+			},
+		}
+
+		syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+		if err := controller.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		conditions := controller.snapshot()
+		if !reflect.DeepEqual(conditions.violatingOpenShiftNamespaces, []string{operatorclient.OperatorNamespace}) {
+			t.Errorf("expected the operator namespace to be reported as an OpenShift namespace, got %v", conditions.violatingOpenShiftNamespaces)
+		}
+		if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, []string{"customer-ns"}) {
+			t.Errorf("expected only the customer namespace to be reported as a customer namespace, got %v", conditions.violatingCustomerNamespaces)
+		}
+		if !reflect.DeepEqual(conditions.userSCCViolatingNamespaces, []string{"customer-ns"}) {
+			t.Errorf("expected only the customer namespace to have user SCC violations, got %v", conditions.userSCCViolatingNamespaces)
+		}
+		if slices.Contains(conditions.workloadBlockingNamespaces, operatorclient.OperatorNamespace) {
+			t.Errorf("expected the operator namespace not to be blocked by user workloads, got %v", conditions.workloadBlockingNamespaces)
+		}
+		if _, ok := conditions.workloadKinds[operatorclient.OperatorNamespace]; ok {
+			t.Errorf("expected no workload kinds for the operator namespace, got %v", conditions.workloadKinds)
+		}
+	})
+
+	t.Run("customer override rejected by the constructor", func(t *testing.T) {
+		_, _, err := NewPodSecurityReadinessController(
+			&rest.Config{Host: "https://localhost:6443"},
+			v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			events.NewInMemoryRecorder("test", clock.RealClock{}),
+			NewWarningsHandler(),
+			Config{Namespaces: NamespacesConfig{CustomerOverrides: []string{"customer-ns", operatorclient.OperatorNamespace}}},
+		)
+		if err == nil {
+			t.Error("expected the operator namespace override to be rejected")
+		}
+	})
+}
+
+func TestStatusDryRun(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "violating-namespace",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	})
+	handler := &warningsHandler{}
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		handleWarnings(handler, []string{"existing pods in namespace \"violating-namespace\" violate the new PodSecurity enforce level \"restricted:latest\""})
+		return true, nil, nil
+	})
+
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  operatorClient,
+		clock:           clock.RealClock{},
+		warningsHandler: handler,
+		config:          Config{Conditions: ConditionsConfig{DryRun: true}},
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conditions := controller.snapshot()
+	if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, []string{"violating-namespace"}) {
+		t.Errorf("expected the conditions to be computed, got %v", conditions.violatingCustomerNamespaces)
+	}
+
+	_, status, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(status.Conditions) != 0 {
+		t.Errorf("expected no status write, got conditions %v", status.Conditions)
+	}
+}
+
+func TestViolationHandler(t *testing.T) {
+	newController := func(handler ViolationHandler, timeout time.Duration) *PodSecurityReadinessController {
+		fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "violating-namespace",
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		})
+		warnings := &warningsHandler{}
+		fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			warnings.HandleWarningHeader(299, "", "existing pods in namespace \"violating-namespace\" violate the new PodSecurity enforce level \"restricted:latest\"")
+			return true, nil, nil
+		})
+
+		controller := &PodSecurityReadinessController{
+			kubeClient:      fakeClient,
+			operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			clock:           clock.RealClock{},
+			warningsHandler: warnings,
+			dryRunVerified:  true,
+			config:          Config{Results: ResultsConfig{ViolationHandler: handler, ViolationHandlerTimeout: timeout}},
+
+			violationHandlerSlots: make(chan struct{}, maxConcurrentViolationHandlers),
+		}
+		return controller
+	}
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+
+	t.Run("invoked without blocking the sync", func(t *testing.T) {
+		release := make(chan struct{})
+		invoked := make(chan EvaluationResult, 1)
+		controller := newController(func(ctx context.Context, ns *corev1.Namespace, result EvaluationResult) error {
+			<-release
+			if ns.Name != "violating-namespace" {
+				t.Errorf("unexpected namespace %q", ns.Name)
+			}
+			invoked <- result
+			return nil
+		}, time.Minute)
+
+		if err := controller.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		close(release)
+
+		select {
+		case result := <-invoked:
+			if !result.Violating || result.Level != "restricted" {
+				t.Errorf("unexpected result %+v", result)
+			}
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatal("the violation handler wasn't invoked")
+		}
+	})
+
+	t.Run("cancelled after the timeout", func(t *testing.T) {
+		cancelled := make(chan error, 1)
+		controller := newController(func(ctx context.Context, ns *corev1.Namespace, result EvaluationResult) error {
+			<-ctx.Done()
+			cancelled <- ctx.Err()
+			return ctx.Err()
+		}, time.Millisecond)
+
+		if err := controller.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		select {
+		case err := <-cancelled:
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("expected the deadline to be exceeded, got %v", err)
+			}
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatal("the violation handler wasn't cancelled")
+		}
+	})
+
+	t.Run("invoked once after a conflict", func(t *testing.T) {
+		invoked := make(chan struct{}, 2)
+		controller := newController(func(ctx context.Context, ns *corev1.Namespace, result EvaluationResult) error {
+			invoked <- struct{}{}
+			return nil
+		}, time.Minute)
+		conflicted := false
+		controller.kubeClient.(*fake.Clientset).PrependReactor("get", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			if conflicted {
+				return false, nil, nil
+			}
+			conflicted = true
+			return true, nil, apierrors.NewConflict(corev1.Resource("namespaces"), "violating-namespace", errors.New("conflict"))
+		})
+
+		if err := controller.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		select {
+		case <-invoked:
+		case <-time.After(wait.ForeverTestTimeout):
+			t.Fatal("the violation handler wasn't invoked")
+		}
+		select {
+		case <-invoked:
+			t.Error("expected the violation handler to be invoked once")
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("bounded across syncs", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		var running atomic.Int32
+		controller := newController(func(ctx context.Context, ns *corev1.Namespace, result EvaluationResult) error {
+			running.Add(1)
+			<-release
+			return nil
+		}, time.Minute)
+
+		for i := 0; i < maxConcurrentViolationHandlers+5; i++ {
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		}
+
+		if err := wait.PollUntilContextTimeout(context.TODO(), 10*time.Millisecond, wait.ForeverTestTimeout, true, func(context.Context) (bool, error) {
+			return running.Load() == maxConcurrentViolationHandlers, nil
+		}); err != nil {
+			t.Fatalf("expected %d running handlers, got %d", maxConcurrentViolationHandlers, running.Load())
+		}
+		time.Sleep(100 * time.Millisecond)
+		if n := running.Load(); n != maxConcurrentViolationHandlers {
+			t.Errorf("expected at most %d running handlers, got %d", maxConcurrentViolationHandlers, n)
+		}
+	})
+}
+
+func TestStaleNamespaceSelection(t *testing.T) {
+	listed := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "enforcing-namespace",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	}
+	// The syncer set the enforce level after the namespace was listed.
+	enforcing := listed.DeepCopy()
+	enforcing.Labels = map[string]string{psapi.EnforceLevelLabel: "restricted"}
+
+	var patchedNamespaces []string
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset(enforcing)
+	fakeClient.PrependReactor("list", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, &corev1.NamespaceList{Items: []corev1.Namespace{*listed}}, nil
+	})
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		// The syncer owns the enforce label now.
+		patchedNamespaces = append(patchedNamespaces, action.(clienttesting.PatchAction).GetName())
+		return true, nil, apierrors.NewConflict(corev1.Resource("namespaces"), "enforcing-namespace", fmt.Errorf("conflict with %q: .metadata.labels.pod-security.kubernetes.io/enforce", defaultSyncerControllerName))
+	})
+
+	selector, err := nonEnforcingSelector()
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller := &PodSecurityReadinessController{
+		kubeClient:        fakeClient,
+		operatorClient:    v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:             clock.RealClock{},
+		namespaceSelector: selector,
+		warningsHandler:   handler,
+		dryRunVerified:    true,
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The namespace is re-read once the dry-run Apply conflicts.
+	if !reflect.DeepEqual(patchedNamespaces, []string{"enforcing-namespace"}) {
+		t.Errorf("expected a single dry-run Apply on the listed namespace, got %v", patchedNamespaces)
+	}
+	conditions := controller.snapshot()
+	if len(conditions.violatingCustomerNamespaces) != 0 || len(conditions.inconclusiveNamespaces) != 0 {
+		t.Errorf("expected the enforcing namespace not to be reported, got violating %v and inconclusive %v",
+			conditions.violatingCustomerNamespaces, conditions.inconclusiveNamespaces)
+	}
+}
+
+func TestRemediationClassification(t *testing.T) {
+	privileged := true
+	newNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+	newPrivilegedPod := func(namespace, subjectType string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "privileged-pod",
+				Namespace: namespace,
+				Annotations: map[string]string{
+					securityv1.ValidatedSCCSubjectTypeAnnotation: subjectType,
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:            "privileged",
+					SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+				}},
+			},
+		}
+	}
+
+	// violatedLevels holds the levels each namespace violates.
+	violatedLevels := map[string][]string{
+		"label-fixable":     {"restricted"},
+		"workload-blocking": {"restricted", "baseline"},
+		"service-account":   {"restricted", "baseline"},
+	}
+
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset(
+		newNamespace("label-fixable"),
+		newNamespace("workload-blocking"),
+		newNamespace("service-account"),
+		newPrivilegedPod("workload-blocking", "user"),
+		newPrivilegedPod("service-account", "serviceaccount"),
+	)
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		ns := &corev1.Namespace{}
+		if err := json.Unmarshal(action.(clienttesting.PatchAction).GetPatch(), ns); err != nil {
+			return true, nil, err
+		}
+
+		level := ns.Labels[psapi.EnforceLevelLabel]
+		for _, violated := range violatedLevels[ns.Name] {
+			if violated == level {
+				handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"%s:latest\"", ns.Name, level))
+			}
+		}
+
+		return true, nil, nil
+	})
+
+	psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: handler,
+		psaEvaluator:    psaEvaluator,
+		dryRunVerified:  true,
+		config:          Config{Evaluation: EvaluationConfig{ClassifyRemediation: true}},
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conditions := controller.snapshot()
+	if expected := []string{"label-fixable", "service-account"}; !reflect.DeepEqual(conditions.labelFixableNamespaces, expected) {
+		t.Errorf("expected label-fixable namespaces %v, got %v", expected, conditions.labelFixableNamespaces)
+	}
+	if expected := []string{"workload-blocking"}; !reflect.DeepEqual(conditions.workloadBlockingNamespaces, expected) {
+		t.Errorf("expected workload-blocking namespaces %v, got %v", expected, conditions.workloadBlockingNamespaces)
+	}
+
+	_, status, _, err := controller.operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityWorkloadBlockingType)
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != blockingReason {
+		t.Errorf("expected condition %s to be raised, got %v", PodSecurityWorkloadBlockingType, condition)
+	}
+}
+
+func TestNewNamespaceGracePeriod(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		name  string
+		grace time.Duration
+		age   time.Duration
+
+		expectedInconclusive []string
+	}{
+		{
+			name:                 "young namespace without grace period",
+			age:                  5 * time.Minute,
+			expectedInconclusive: []string{"unlabeled-namespace"},
+		},
+		{
+			name:  "young namespace within grace period",
+			grace: 10 * time.Minute,
+			age:   5 * time.Minute,
+		},
+		{
+			name:                 "namespace older than the grace period",
+			grace:                10 * time.Minute,
+			age:                  20 * time.Minute,
+			expectedInconclusive: []string{"unlabeled-namespace"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "unlabeled-namespace",
+					CreationTimestamp: metav1.NewTime(now.Add(-tt.age)),
+				},
+			})
+
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				clock:           clocktesting.NewFakePassiveClock(now),
+				warningsHandler: &warningsHandler{},
+				dryRunVerified:  true,
+				config:          Config{Namespaces: NamespacesConfig{NewNamespaceGracePeriod: tt.grace}},
+			}
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.inconclusiveNamespaces, tt.expectedInconclusive) {
+				t.Errorf("expected inconclusive namespaces %v, got %v", tt.expectedInconclusive, conditions.inconclusiveNamespaces)
+			}
+		})
+	}
+}
+
+func TestUnparseableAlertLabels(t *testing.T) {
+	// Only namespaces whose labels are applied would warn about their pods.
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "misconfigured",
+			Labels: map[string]string{
+				psapi.WarnLevelLabel:  "restricetd",
+				psapi.AuditLevelLabel: "garbage",
+			},
+			ManagedFields: managedFields,
+		},
+	})
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		handler.HandleWarningHeader(299, "", "existing pods in namespace \"misconfigured\" violate the new PodSecurity enforce level \"restricted:latest\"")
+		return true, nil, nil
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: handler,
+		dryRunVerified:  true,
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conditions := controller.snapshot()
+	if expected := []string{"misconfigured"}; !reflect.DeepEqual(conditions.inconclusiveNamespaces, expected) {
+		t.Errorf("expected inconclusive namespaces %v, got %v", expected, conditions.inconclusiveNamespaces)
+	}
+	if len(conditions.violatingCustomerNamespaces) != 0 {
+		t.Errorf("expected no violating namespaces, got %v", conditions.violatingCustomerNamespaces)
+	}
+}
+
+func TestCleanCounts(t *testing.T) {
+	newNamespace := func(name, level string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: level,
+				},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset(
+		newNamespace("clean-restricted-a", "restricted"),
+		newNamespace("clean-restricted-b", "restricted"),
+		newNamespace("clean-baseline", "baseline"),
+		newNamespace("violating", "restricted"),
+	)
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if name := action.(clienttesting.PatchAction).GetName(); name == "violating" {
+			handler.HandleWarningHeader(299, "", "existing pods in namespace \"violating\" violate the new PodSecurity enforce level \"restricted:latest\"")
+		}
+		return true, nil, nil
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: handler,
+		dryRunVerified:  true,
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]int{"restricted": 2, "baseline": 1}
+	if conditions := controller.snapshot(); !reflect.DeepEqual(conditions.cleanCounts, expected) {
+		t.Errorf("expected clean counts %v, got %v", expected, conditions.cleanCounts)
+	}
+}
+
+func TestReadinessOptOut(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		optOut string
+
+		expectedEvaluated []string
+		expectedViolating []string
+		expectedOptedOut  []string
+	}{
+		{
+			name:             "opted out namespace",
+			optOut:           "true",
+			expectedOptedOut: []string{"test-ns"},
+		},
+		{
+			name:              "opt-out annotation set to false",
+			optOut:            "false",
+			expectedEvaluated: []string{"test-ns"},
+			expectedViolating: []string{"test-ns"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &warningsHandler{}
+			var evaluated []string
+			fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ns",
+					Annotations: map[string]string{
+						securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+						readinessOptOutAnnotation:                         tt.optOut,
+					},
+					ManagedFields: managedFields,
+				},
+			})
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				evaluated = append(evaluated, action.(clienttesting.PatchAction).GetName())
+				handler.HandleWarningHeader(299, "", "existing pods in namespace \"test-ns\" violate the new PodSecurity enforce level \"restricted:latest\"")
+				return true, nil, nil
+			})
+
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				clock:           clock.RealClock{},
+				warningsHandler: handler,
+				dryRunVerified:  true,
+				config:          Config{Conditions: ConditionsConfig{Diagnostics: true}},
+			}
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(evaluated, tt.expectedEvaluated) {
+				t.Errorf("expected evaluated namespaces %v, got %v", tt.expectedEvaluated, evaluated)
+			}
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, tt.expectedViolating) {
+				t.Errorf("expected violating namespaces %v, got %v", tt.expectedViolating, conditions.violatingCustomerNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.optedOutNamespaces, tt.expectedOptedOut) {
+				t.Errorf("expected opted out namespaces %v, got %v", tt.expectedOptedOut, conditions.optedOutNamespaces)
+			}
+
+			_, status, _, err := controller.operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityOptedOutType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", PodSecurityOptedOutType)
+			}
+			if expected := len(tt.expectedOptedOut) > 0; (condition.Status == operatorv1.ConditionTrue) != expected {
+				t.Errorf("expected condition %s to be raised: %v, got %+v", PodSecurityOptedOutType, expected, condition)
+			}
+		})
+	}
+}
+
+func TestUserSCCCheckDisabled(t *testing.T) {
+	privileged := true
+	userPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "user-pod",
+			Namespace: "test-ns",
+			Annotations: map[string]string{
+				securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:            "privileged",
+				SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+			}},
+		},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		skip     bool
+		expected []string
+	}{
+		{
+			name:     "enabled",
+			expected: []string{"test-ns"},
+		},
+		{
+			name: "disabled",
+			skip: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &warningsHandler{}
+			fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ns",
+					Annotations: map[string]string{
+						securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+					},
+					ManagedFields: managedFields,
+				},
+			}, userPod)
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				handler.HandleWarningHeader(299, "", "existing pods in namespace \"test-ns\" violate the new PodSecurity enforce level \"restricted:latest\"")
+				return true, nil, nil
+			})
+			podLists := 0
+			fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				podLists++
+				return false, nil, nil
+			})
+
+			psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				clock:           clock.RealClock{},
+				warningsHandler: handler,
+				dryRunVerified:  true,
+				psaEvaluator:    psaEvaluator,
+				config:          Config{Pods: PodsConfig{SkipUserSCCCheck: tt.skip}},
+			}
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, []string{"test-ns"}) {
+				t.Errorf("expected test-ns to be violating regardless of the user SCC check, got %v", conditions.violatingCustomerNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.userSCCViolatingNamespaces, tt.expected) {
+				t.Errorf("expected user SCC violating namespaces %v, got %v", tt.expected, conditions.userSCCViolatingNamespaces)
+			}
+			if tt.skip && podLists > 0 {
+				t.Errorf("expected no pods to be listed, got %d lists", podLists)
+			}
+
+			_, status, _, err := controller.operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, conditionType := range []string{PodSecurityUserSCCType, PodSecurityUserSCCInconclusiveType} {
+				if condition := v1helpers.FindOperatorCondition(status.Conditions, conditionType); (condition == nil) != tt.skip {
+					t.Errorf("expected condition %s to be removed: %v, got %v", conditionType, tt.skip, condition)
+				}
+			}
+		})
+	}
+}
+
+func TestEnforcedNamespaceAudit(t *testing.T) {
+	privileged := true
+	enforcingNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Labels: map[string]string{
+					psapi.EnforceLevelLabel: "baseline",
+				},
+			},
+		}
+	}
+	pod := func(namespace string, privileged *bool) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pod",
+				Namespace: namespace,
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:            "container",
+					SecurityContext: &corev1.SecurityContext{Privileged: privileged},
+				}},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name              string
+		audit             bool
+		expectedRegressed []string
+	}{
+		{
+			name:              "audit enabled",
+			audit:             true,
+			expectedRegressed: []string{"regressed-namespace"},
+		},
+		{
+			name:              "audit disabled",
+			audit:             false,
+			expectedRegressed: nil,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(
+				enforcingNamespace("regressed-namespace"),
+				pod("regressed-namespace", &privileged),
+				enforcingNamespace("clean-namespace"),
+				pod("clean-namespace", nil),
+			)
+
+			psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			selector, err := nonEnforcingSelector()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			enforcingSelector, err := enforcingSelector()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			controller := &PodSecurityReadinessController{
+				kubeClient:                 fakeClient,
+				operatorClient:             v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				clock:                      clock.RealClock{},
+				warningsHandler:            &warningsHandler{},
+				namespaceSelector:          selector,
+				enforcingNamespaceSelector: enforcingSelector,
+				psaEvaluator:               psaEvaluator,
+				dryRunVerified:             true,
+				config:                     Config{Evaluation: EvaluationConfig{AuditEnforcedNamespaces: tt.audit}},
+			}
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.regressedEnforcingNamespaces, tt.expectedRegressed) {
+				t.Errorf("expected regressed namespaces %v, got %v", tt.expectedRegressed, conditions.regressedEnforcingNamespaces)
+			}
+			if len(conditions.inconclusiveNamespaces) != 0 {
+				t.Errorf("expected no inconclusive namespaces, got %v", conditions.inconclusiveNamespaces)
+			}
+		})
+	}
+}
+
+func TestInvalidEnforceLabelDetection(t *testing.T) {
+	enforcingNamespace := func(name, level string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{psapi.EnforceLevelLabel: level},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name   string
+		detect bool
+		audit  bool
+
+		expectedMisconfigured []string
+		expectedInconclusive  []string
+		expectedStatus        operatorv1.ConditionStatus
+	}{
+		{
+			name:                  "detection enabled",
+			detect:                true,
+			expectedMisconfigured: []string{"capitalized", "empty", "typo"},
+			expectedStatus:        operatorv1.ConditionTrue,
+		},
+		{
+			name:                  "detection and audit enabled",
+			detect:                true,
+			audit:                 true,
+			expectedMisconfigured: []string{"capitalized", "empty", "typo"},
+			expectedStatus:        operatorv1.ConditionTrue,
+		},
+		{
+			// Invalid levels are audited as restricted, like the admission
+			// plugin enforces them.
+			name:  "audit enabled without detection",
+			audit: true,
+		},
+		{
+			name: "detection disabled",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(
+				enforcingNamespace("valid", "baseline"),
+				enforcingNamespace("typo", "restricetd"),
+				enforcingNamespace("capitalized", "Restricted"),
+				enforcingNamespace("empty", ""),
+			)
+
+			psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			selector, err := nonEnforcingSelector()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			enforcingSelector, err := enforcingSelector()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			controller := &PodSecurityReadinessController{
+				kubeClient:                 fakeClient,
+				operatorClient:             v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				clock:                      clock.RealClock{},
+				warningsHandler:            &warningsHandler{},
+				namespaceSelector:          selector,
+				enforcingNamespaceSelector: enforcingSelector,
+				psaEvaluator:               psaEvaluator,
+				dryRunVerified:             true,
+				config:                     Config{Evaluation: EvaluationConfig{AuditEnforcedNamespaces: tt.audit, DetectInvalidEnforceLabels: tt.detect}},
+			}
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.misconfiguredNamespaces, tt.expectedMisconfigured) {
+				t.Errorf("expected misconfigured namespaces %v, got %v", tt.expectedMisconfigured, conditions.misconfiguredNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.inconclusiveNamespaces, tt.expectedInconclusive) {
+				t.Errorf("expected inconclusive namespaces %v, got %v", tt.expectedInconclusive, conditions.inconclusiveNamespaces)
+			}
+
+			_, status, _, err := controller.operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityMisconfiguredType)
+			if !tt.detect {
+				if condition != nil {
+					t.Errorf("expected condition %s to be removed, got %+v", PodSecurityMisconfiguredType, condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", PodSecurityMisconfiguredType)
+			}
+			if condition.Status != tt.expectedStatus {
+				t.Errorf("expected condition status %s, got %s", tt.expectedStatus, condition.Status)
+			}
+		})
+	}
+}
+
+func TestTrackViolatingSince(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fakeClock := clocktesting.NewFakePassiveClock(start)
+	controller := &PodSecurityReadinessController{clock: fakeClock}
+
+	since := controller.trackViolatingSince([]string{"ns-a"})
+	if !reflect.DeepEqual(since, map[string]time.Time{"ns-a": start}) {
+		t.Errorf("unexpected starts after first sync: %v", since)
+	}
+
+	fakeClock.SetTime(start.Add(time.Hour))
+	since = controller.trackViolatingSince([]string{"ns-a", "ns-b"})
+	if !reflect.DeepEqual(since, map[string]time.Time{"ns-a": start, "ns-b": start.Add(time.Hour)}) {
+		t.Errorf("unexpected starts after second sync: %v", since)
+	}
+
+	// ns-a stopped violating, so it starts over once it violates again.
+	fakeClock.SetTime(start.Add(2 * time.Hour))
+	since = controller.trackViolatingSince([]string{"ns-b"})
+	if !reflect.DeepEqual(since, map[string]time.Time{"ns-b": start.Add(time.Hour)}) {
+		t.Errorf("unexpected starts after third sync: %v", since)
+	}
+
+	fakeClock.SetTime(start.Add(3 * time.Hour))
+	since = controller.trackViolatingSince([]string{"ns-a", "ns-b"})
+	if !reflect.DeepEqual(since, map[string]time.Time{"ns-a": start.Add(3 * time.Hour), "ns-b": start.Add(time.Hour)}) {
+		t.Errorf("unexpected starts after fourth sync: %v", since)
+	}
+}
+
+func TestTrackViolatingSyncs(t *testing.T) {
+	controller := &PodSecurityReadinessController{}
+
+	syncs := controller.trackViolatingSyncs([]string{"ns-a"})
+	if !reflect.DeepEqual(syncs, map[string]int{"ns-a": 1}) {
+		t.Errorf("unexpected syncs after first sync: %v", syncs)
+	}
+
+	syncs = controller.trackViolatingSyncs([]string{"ns-a", "ns-b"})
+	if !reflect.DeepEqual(syncs, map[string]int{"ns-a": 2, "ns-b": 1}) {
+		t.Errorf("unexpected syncs after second sync: %v", syncs)
+	}
+
+	// ns-a became clean, so it is counted from scratch once it violates
+	// again.
+	syncs = controller.trackViolatingSyncs([]string{"ns-b"})
+	if !reflect.DeepEqual(syncs, map[string]int{"ns-b": 2}) {
+		t.Errorf("unexpected syncs after third sync: %v", syncs)
+	}
+
+	syncs = controller.trackViolatingSyncs([]string{"ns-a", "ns-b"})
+	if !reflect.DeepEqual(syncs, map[string]int{"ns-a": 1, "ns-b": 3}) {
+		t.Errorf("unexpected syncs after fourth sync: %v", syncs)
+	}
+}
+
+func TestSyncLogLine(t *testing.T) {
+	// The global logger and verbosity are restored for the other tests.
+	t.Cleanup(klog.CaptureState().Restore)
+
+	var logLines []map[string]interface{}
+	klog.SetLogger(funcr.NewJSON(func(obj string) {
+		line := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(obj), &line); err != nil {
+			t.Errorf("unexpected log line %s: %v", obj, err)
+		}
+		logLines = append(logLines, line)
+	}, funcr.Options{Verbosity: 2}))
+
+	var flags flag.FlagSet
+	klog.InitFlags(&flags)
+	if err := flags.Set("v", "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	handler := &warningsHandler{}
+	newNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+	fakeClient := fake.NewSimpleClientset(newNamespace("violating"), newNamespace("clean"), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled"}})
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if action.(clienttesting.PatchAction).GetName() == "violating" {
+			handler.HandleWarningHeader(299, "", "existing pods in namespace \"violating\" violate the new PodSecurity enforce level \"restricted:latest\"")
+		}
+		return true, nil, nil
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clocktesting.NewFakePassiveClock(time.Now()),
+		warningsHandler: handler,
+		dryRunVerified:  true,
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var summaries []map[string]interface{}
+	for _, line := range logLines {
+		if line["msg"] == "Evaluated namespaces for pod security readiness" {
+			summaries = append(summaries, line)
+		}
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("expected a single summary log line, got %v", summaries)
+	}
+	summary := summaries[0]
+
+	if summary["level"] != float64(2) {
+		t.Errorf("expected the summary to be logged at V(2), got %v", summary["level"])
+	}
+	if summary["policyVersion"] != psapi.LatestVersion().String() {
+		t.Errorf("expected policy version %q, got %v", psapi.LatestVersion().String(), summary["policyVersion"])
+	}
+	if _, ok := summary["duration"]; !ok {
+		t.Errorf("expected the sync duration to be logged, got %v", summary)
+	}
+	for key, expected := range map[string]float64{
+		"customer":     1,
+		"openshift":    0,
+		"inconclusive": 1,
+		"clean":        1,
+	} {
+		if summary[key] != expected {
+			t.Errorf("expected %s to be %v, got %v", key, expected, summary[key])
+		}
+	}
+}
+
+func TestNamespaceEvaluationTimeout(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		timeout time.Duration
+
+		expectedViolating    []string
+		expectedInconclusive []string
+	}{
+		{
+			name:                 "slow namespace times out",
+			timeout:              50 * time.Millisecond,
+			expectedViolating:    []string{"fast"},
+			expectedInconclusive: []string{"slow"},
+		},
+		{
+			name:              "timeout disabled",
+			expectedViolating: []string{"fast", "slow"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			newNamespace := func(name string) *corev1.Namespace {
+				return &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+						Annotations: map[string]string{
+							securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+						},
+						ManagedFields: managedFields,
+					},
+				}
+			}
+
+			handler := &warningsHandler{}
+			fakeClient := fake.NewSimpleClientset(newNamespace("fast"), newNamespace("slow"))
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				name := action.(clienttesting.PatchAction).GetName()
+				if name == "slow" {
+					time.Sleep(200 * time.Millisecond)
+				}
+				handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"restricted:latest\"", name))
+				return true, nil, nil
+			})
+
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				clock:           clock.RealClock{},
+				warningsHandler: handler,
+				dryRunVerified:  true,
+				config:          Config{Evaluation: EvaluationConfig{NamespaceTimeout: tt.timeout}},
+			}
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, tt.expectedViolating) {
+				t.Errorf("expected violating namespaces %v, got %v", tt.expectedViolating, conditions.violatingCustomerNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.inconclusiveNamespaces, tt.expectedInconclusive) {
+				t.Errorf("expected inconclusive namespaces %v, got %v", tt.expectedInconclusive, conditions.inconclusiveNamespaces)
+			}
+		})
+	}
+}
+
+// applyRecordingOperatorClient records the field managers the operator status
+// is applied with.
+type applyRecordingOperatorClient struct {
+	v1helpers.OperatorClient
+
+	statusFieldManagers []string
+}
+
+func (c *applyRecordingOperatorClient) ApplyOperatorStatus(ctx context.Context, fieldManager string, applyConfiguration *applyoperatorv1.OperatorStatusApplyConfiguration) error {
+	c.statusFieldManagers = append(c.statusFieldManagers, fieldManager)
+	return c.OperatorClient.ApplyOperatorStatus(ctx, fieldManager, applyConfiguration)
+}
+
+func TestFieldManagers(t *testing.T) {
+	fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	})
+	var namespaceFieldManagers []string
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		namespaceFieldManagers = append(namespaceFieldManagers, action.(clienttesting.PatchActionImpl).PatchOptions.FieldManager)
+		return true, nil, nil
+	})
+
+	operatorClient := &applyRecordingOperatorClient{
+		OperatorClient: v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{
+			Conditions: []operatorv1.OperatorCondition{{Type: "OtherControllerDegraded", Status: operatorv1.ConditionFalse}},
+		}, nil),
+	}
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  operatorClient,
+		clock:           clock.RealClock{},
+		warningsHandler: &warningsHandler{},
+		config: Config{
+			Conditions: ConditionsConfig{FieldManager: "status-manager"},
+			Client:     ClientConfig{FieldManager: "namespace-manager"},
+		},
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The self check and the evaluation of the namespace are both applied.
+	if !reflect.DeepEqual(namespaceFieldManagers, []string{"namespace-manager", "namespace-manager"}) {
+		t.Errorf("expected the namespaces to be applied by namespace-manager, got %v", namespaceFieldManagers)
+	}
+	if len(operatorClient.statusFieldManagers) == 0 {
+		t.Fatal("expected the operator status to be applied")
+	}
+	for _, fieldManager := range operatorClient.statusFieldManagers {
+		if fieldManager != "status-manager" {
+			t.Errorf("expected the operator status to be applied by status-manager, got %v", operatorClient.statusFieldManagers)
+			break
+		}
+	}
+
+	_, status, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, conditionType := range []string{PodSecurityCustomerType, PodSecurityReadinessSyncHealthyType, "OtherControllerDegraded"} {
+		if v1helpers.FindOperatorCondition(status.Conditions, conditionType) == nil {
+			t.Errorf("expected condition %s to be set", conditionType)
+		}
+	}
+}
+
+func TestFieldManagerValidation(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		config Config
+
+		expectError bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name: "distinct field managers",
+			config: Config{
+				Client:     ClientConfig{FieldManager: "namespace-manager"},
+				Conditions: ConditionsConfig{FieldManager: "status-manager"},
+			},
+		},
+		{
+			name:        "status field manager matching the default",
+			config:      Config{Conditions: ConditionsConfig{FieldManager: readinessFieldManager}},
+			expectError: true,
+		},
+		{
+			name: "same field managers",
+			config: Config{
+				Client:     ClientConfig{FieldManager: "shared-manager"},
+				Conditions: ConditionsConfig{FieldManager: "shared-manager"},
+			},
+			expectError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewPodSecurityReadinessController(
+				&rest.Config{Host: "https://localhost:6443"},
+				v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				events.NewInMemoryRecorder("test", clock.RealClock{}),
+				NewWarningsHandler(),
+				tt.config,
+			)
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestCustomerNamespaceOverrides(t *testing.T) {
+	privileged := true
+	objects := []runtime.Object{}
+	for _, name := range []string{"openshift-console", "kube-system"} {
+		objects = append(objects,
+			&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Annotations: map[string]string{
+						securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+					},
+					ManagedFields: managedFields,
+				},
+			},
+			&corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "user-pod",
+					Namespace: name,
+					Annotations: map[string]string{
+						securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{
+						Name:            "privileged",
+						SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+					}},
+				},
+			},
+		)
+	}
+
+	for _, tt := range []struct {
+		name              string
+		overrides         []string
+		expectedCustomer  []string
+		expectedOpenShift []string
+		expectedRunLevel  []string
+		expectedKinds     map[string][]string
+	}{
+		{
+			name:              "no overrides",
+			expectedOpenShift: []string{"openshift-console"},
+			expectedRunLevel:  []string{"kube-system"},
+		},
+		{
+			name:             "openshift namespace overridden",
+			overrides:        []string{"openshift-console"},
+			expectedCustomer: []string{"openshift-console"},
+			expectedRunLevel: []string{"kube-system"},
+			expectedKinds:    map[string][]string{"openshift-console": {"Pod"}},
+		},
+		{
+			name:              "run-level zero namespace overridden",
+			overrides:         []string{"kube-system"},
+			expectedCustomer:  []string{"kube-system"},
+			expectedOpenShift: []string{"openshift-console"},
+			expectedKinds:     map[string][]string{"kube-system": {"Pod"}},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &warningsHandler{}
+			fakeClient := fake.NewSimpleClientset(objects...)
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				name := action.(clienttesting.PatchAction).GetName()
+				handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"restricted:latest\"", name))
+				return true, nil, nil
+			})
+			// The workload kinds reuse the pods listed for the user SCC check.
+			podLists := map[string]int{}
+			fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				podLists[action.GetNamespace()]++
+				return false, nil, nil
+			})
+
+			psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				clock:           clock.RealClock{},
+				warningsHandler: handler,
+				dryRunVerified:  true,
+				psaEvaluator:    psaEvaluator,
+				config: Config{
+					Namespaces: NamespacesConfig{CustomerOverrides: tt.overrides},
+					Pods:       PodsConfig{WorkloadKindHints: true},
+				},
+			}
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, tt.expectedCustomer) {
+				t.Errorf("expected customer namespaces %v, got %v", tt.expectedCustomer, conditions.violatingCustomerNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.violatingOpenShiftNamespaces, tt.expectedOpenShift) {
+				t.Errorf("expected openshift namespaces %v, got %v", tt.expectedOpenShift, conditions.violatingOpenShiftNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.violatingRunLevelZeroNamespaces, tt.expectedRunLevel) {
+				t.Errorf("expected run-level zero namespaces %v, got %v", tt.expectedRunLevel, conditions.violatingRunLevelZeroNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.workloadKinds, tt.expectedKinds) {
+				t.Errorf("expected workload kinds %v, got %v", tt.expectedKinds, conditions.workloadKinds)
+			}
+			for namespace, lists := range podLists {
+				if lists > 1 {
+					t.Errorf("expected the pods of namespace %s to be listed at most once, got %d lists", namespace, lists)
+				}
+			}
+		})
+	}
+}
+
+func TestFailClosed(t *testing.T) {
+	for _, tt := range []struct {
+		name                      string
+		failClosed                bool
+		expectViolating           []string
+		expectUserSCC             []string
+		expectInconclusive        []string
+		expectUserSCCInconclusive []string
+		expectFailedClosed        []string
+		expectCondition           bool
+	}{
+		{
+			name:                      "fail to inconclusive",
+			expectViolating:           []string{"forbidden-pods"},
+			expectInconclusive:        []string{"apply-error"},
+			expectUserSCCInconclusive: []string{"forbidden-pods"},
+		},
+		{
+			name:               "fail closed",
+			failClosed:         true,
+			expectViolating:    []string{"apply-error", "forbidden-pods"},
+			expectUserSCC:      []string{"forbidden-pods"},
+			expectFailedClosed: []string{"apply-error", "forbidden-pods"},
+			expectCondition:    true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := []runtime.Object{}
+			for _, name := range []string{"apply-error", "forbidden-pods"} {
+				objects = append(objects, &corev1.Namespace{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: name,
+						Annotations: map[string]string{
+							securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+						},
+						ManagedFields: managedFields,
+					},
+				})
+			}
+
+			handler := &warningsHandler{}
+			fakeClient := fake.NewSimpleClientset(objects...)
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				if action.(clienttesting.PatchAction).GetName() == "apply-error" {
+					return true, nil, apierrors.NewInternalError(fmt.Errorf("apply failed"))
+				}
+				handler.HandleWarningHeader(299, "", "existing pods in namespace \"forbidden-pods\" violate the new PodSecurity enforce level \"restricted:latest\"")
+				return true, nil, nil
+			})
+			fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, nil, apierrors.NewForbidden(corev1.Resource("pods"), "", fmt.Errorf("not allowed"))
+			})
+
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				clock:           clock.RealClock{},
+				warningsHandler: handler,
+				dryRunVerified:  true,
+				config:          Config{Evaluation: EvaluationConfig{FailClosed: tt.failClosed}},
+			}
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, tt.expectViolating) {
+				t.Errorf("expected violating namespaces %v, got %v", tt.expectViolating, conditions.violatingCustomerNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.userSCCViolatingNamespaces, tt.expectUserSCC) {
+				t.Errorf("expected user SCC violating namespaces %v, got %v", tt.expectUserSCC, conditions.userSCCViolatingNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.inconclusiveNamespaces, tt.expectInconclusive) {
+				t.Errorf("expected inconclusive namespaces %v, got %v", tt.expectInconclusive, conditions.inconclusiveNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.userSCCInconclusiveNamespaces, tt.expectUserSCCInconclusive) {
+				t.Errorf("expected user SCC inconclusive namespaces %v, got %v", tt.expectUserSCCInconclusive, conditions.userSCCInconclusiveNamespaces)
+			}
+			if !reflect.DeepEqual(conditions.failedClosedNamespaces, tt.expectFailedClosed) {
+				t.Errorf("expected failed closed namespaces %v, got %v", tt.expectFailedClosed, conditions.failedClosedNamespaces)
+			}
+
+			_, status, _, err := controller.operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityFailedClosedType)
+			if (condition != nil) != tt.expectCondition {
+				t.Fatalf("expected condition %s to be reported: %v, got %v", PodSecurityFailedClosedType, tt.expectCondition, condition)
+			}
+			if condition != nil && (condition.Status != operatorv1.ConditionTrue || condition.Reason != failedClosedReason) {
+				t.Errorf("expected condition %s to be raised with reason %s, got %v", PodSecurityFailedClosedType, failedClosedReason, condition)
+			}
+		})
+	}
+}
+
+func TestViolationResolvedEvent(t *testing.T) {
+	objects := []runtime.Object{}
+	for _, name := range []string{"remediated", "still-violating"} {
+		objects = append(objects, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		})
+	}
+
+	violating := sets.New("remediated", "still-violating")
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset(objects...)
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if name := action.(clienttesting.PatchAction).GetName(); violating.Has(name) {
+			handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"restricted:latest\"", name))
+		}
+		return true, nil, nil
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: handler,
+		dryRunVerified:  true,
+		config:          Config{Pods: PodsConfig{SkipUserSCCCheck: true}},
+	}
+
+	resolvedEvents := func(recorder events.InMemoryRecorder) []string {
+		messages := []string{}
+		for _, event := range recorder.Events() {
+			if event.Reason != violationResolvedReason {
+				continue
+			}
+			if event.Type != corev1.EventTypeNormal {
+				t.Errorf("expected a %s event, got %s", corev1.EventTypeNormal, event.Type)
+			}
+			messages = append(messages, event.Message)
+		}
+		return messages
+	}
+
+	recorder := events.NewInMemoryRecorder("test", clock.RealClock{})
+	if err := controller.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if messages := resolvedEvents(recorder); len(messages) != 0 {
+		t.Errorf("expected no resolved events on the first sync, got %v", messages)
+	}
+
+	violating.Delete("remediated")
+	recorder = events.NewInMemoryRecorder("test", clock.RealClock{})
+	if err := controller.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"Namespace remediated no longer violates the PodSecurity enforce level \"restricted\""}
+	if messages := resolvedEvents(recorder); !reflect.DeepEqual(messages, expected) {
+		t.Errorf("expected resolved events %v, got %v", expected, messages)
+	}
+
+	recorder = events.NewInMemoryRecorder("test", clock.RealClock{})
+	if err := controller.sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if messages := resolvedEvents(recorder); len(messages) != 0 {
+		t.Errorf("expected the resolution to be reported only once, got %v", messages)
+	}
+}
+
+func TestPrioritizedNamespaces(t *testing.T) {
+	namespaces := []corev1.Namespace{}
+	for _, name := range []string{"app-a", "openshift-etcd", "default", "app-b", "openshift-override", "kube-system", "openshift-apiserver"} {
+		namespaces = append(namespaces, corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+	}
+	conditions := podSecurityOperatorConditions{customerOverrides: sets.New("openshift-override")}
+
+	names := func(namespaces []corev1.Namespace) []string {
+		result := []string{}
+		for _, ns := range namespaces {
+			result = append(result, ns.Name)
+		}
+		return result
+	}
+
+	expected := []string{"default", "kube-system", "openshift-etcd", "openshift-apiserver", "app-a", "app-b", "openshift-override"}
+	if actual := names(prioritizedNamespaces(namespaces, conditions.classify)); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected order %v, got %v", expected, actual)
+	}
+	if namespaces[0].Name != "app-a" {
+		t.Errorf("expected the listed namespaces to be left untouched, got %v", names(namespaces))
+	}
+
+	t.Run("sync", func(t *testing.T) {
+		objects := []runtime.Object{}
+		for i := range namespaces {
+			// The evaluation is observed through the dry-run Apply.
+			ns := namespaces[i].DeepCopy()
+			ns.Annotations = map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"}
+			ns.ManagedFields = managedFields
+			objects = append(objects, ns)
+		}
+
+		evaluated := []string{}
+		fakeClient := fake.NewSimpleClientset(objects...)
+		fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			evaluated = append(evaluated, action.(clienttesting.PatchAction).GetName())
+			return true, nil, nil
+		})
+
+		controller := &PodSecurityReadinessController{
+			kubeClient:      fakeClient,
+			operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			clock:           clock.RealClock{},
+			warningsHandler: &warningsHandler{},
+			dryRunVerified:  true,
+			config: Config{
+				Namespaces: NamespacesConfig{CustomerOverrides: []string{"openshift-override"}, PriorityOrdering: true},
+				Pods:       PodsConfig{SkipUserSCCCheck: true},
+			},
+		}
+		if err := controller.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		// The fake clientset lists the namespaces in its own order, so only
+		// the order of the categories is checked.
+		priorities := []namespaceCategory{categoryRunLevelZero, categoryOpenShift, categoryCustomer}
+		next := 0
+		for _, name := range evaluated {
+			category := conditions.classify(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name}})
+			for next < len(priorities) && priorities[next] != category {
+				next++
+			}
+			if next == len(priorities) {
+				t.Fatalf("expected namespaces to be evaluated by priority, got %v", evaluated)
+			}
+		}
+		if len(evaluated) != len(namespaces) {
+			t.Errorf("expected all %d namespaces to be evaluated, got %v", len(namespaces), evaluated)
+		}
+	})
+}
+
+func TestTrackingResetAfterGap(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tt := range []struct {
+		name             string
+		trackingResetGap time.Duration
+
+		// expectedSyncs holds the violating syncs reported after syncing
+		// at start and one, two and five hours later.
+		expectedSyncs []int
+	}{
+		{
+			name:          "without reset",
+			expectedSyncs: []int{1, 2, 3, 4},
+		},
+		{
+			name:             "reset after gap",
+			trackingResetGap: 2 * time.Hour,
+			expectedSyncs:    []int{1, 2, 3, 1},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &warningsHandler{}
+			fakeClient := fake.NewSimpleClientset(&corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "violating",
+					Annotations: map[string]string{
+						securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+					},
+					ManagedFields: managedFields,
+				},
+			})
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				handler.HandleWarningHeader(299, "", "existing pods in namespace \"violating\" violate the new PodSecurity enforce level \"restricted:latest\"")
+				return true, nil, nil
+			})
+
+			fakeClock := clocktesting.NewFakePassiveClock(start)
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				clock:           fakeClock,
+				warningsHandler: handler,
+				dryRunVerified:  true,
+				config: Config{
+					Pods:       PodsConfig{SkipUserSCCCheck: true},
+					Evaluation: EvaluationConfig{TrackingResetGap: tt.trackingResetGap},
+				},
+			}
+
+			for i, offset := range []time.Duration{0, time.Hour, 2 * time.Hour, 5 * time.Hour} {
+				fakeClock.SetTime(start.Add(offset))
+				if err := controller.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if syncs := controller.violatingSyncs["violating"]; syncs != tt.expectedSyncs[i] {
+					t.Errorf("expected %d violating syncs after %v, got %d", tt.expectedSyncs[i], offset, syncs)
+				}
+				conditions := controller.snapshot()
+				if since := conditions.violatingSince["violating"]; tt.expectedSyncs[i] == 1 && !since.Equal(fakeClock.Now()) {
+					t.Errorf("expected the violation to start over after %v, got %v", offset, since)
+				}
+			}
+		})
+	}
+}
+
+func TestSyncerPending(t *testing.T) {
+	unlabeled := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	fakeClient := fake.NewSimpleClientset(
+		unlabeled("pending", nil),
+		unlabeled("disabled", map[string]string{labelSyncControlLabel: "false"}),
+		unlabeled("openshift-skipped", nil),
+		unlabeled("openshift-opted-in", map[string]string{labelSyncControlLabel: "true"}),
+		unlabeled("kube-system", nil),
+		&corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "synced",
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		},
+	)
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, nil
+	})
+
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  operatorClient,
+		clock:           clock.RealClock{},
+		warningsHandler: &warningsHandler{},
+		dryRunVerified:  true,
+		config:          Config{Conditions: ConditionsConfig{Diagnostics: true}},
+	}
+	if err := controller.sync(context.TODO(), factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	conditions := controller.snapshot()
+	if expected, actual := []string{"openshift-opted-in", "pending"}, sortedClone(conditions.syncerPendingNamespaces); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected syncer pending namespaces %v, got %v", expected, actual)
+	}
+	// The namespaces still can't be evaluated.
+	if expected, actual := []string{"disabled", "kube-system", "openshift-opted-in", "openshift-skipped", "pending"}, sortedClone(conditions.inconclusiveNamespaces); !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected inconclusive namespaces %v, got %v", expected, actual)
+	}
+
+	_, status, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecuritySyncerPendingType)
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != syncerPendingReason {
+		t.Fatalf("expected condition %s to be raised, got %v", PodSecuritySyncerPendingType, condition)
+	}
+	if expected := "Pod security labels haven't been synced yet in namespaces: [openshift-opted-in pending]"; condition.Message != expected {
+		t.Errorf("expected message %q, got %q", expected, condition.Message)
+	}
+}
+
+// handleWarnings passes the warnings to the handler, as the client does for
+// the warnings of a response.
+func handleWarnings(handler rest.WarningHandler, warnings []string) {
+	for _, warning := range warnings {
+		handler.HandleWarningHeader(299, "", warning)
+	}
+}
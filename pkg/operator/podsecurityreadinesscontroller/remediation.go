@@ -0,0 +1,201 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	applyconfiguration "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/klog/v2"
+	psapi "k8s.io/pod-security-admission/api"
+)
+
+const (
+	// remediationAnnotation carries the remediation suggestion on violating
+	// customer namespaces, see ResultsConfig.RemediationAnnotations. It is specific to
+	// the controller rather than part of an API.
+	remediationAnnotation = "pod-security-readiness-controller.openshift.io/remediation"
+	// remediationFieldManager owns remediationAnnotation. It differs from the
+	// field manager of the dry-run Applies, which must never persist
+	// anything.
+	remediationFieldManager = "pod-security-readiness-remediation"
+	// maxRemediationWorkloads is the number of violating workloads a
+	// suggestion names, to keep the annotation short.
+	maxRemediationWorkloads = 3
+)
+
+// remediationSuggestion formats the enforce label that would admit the pods of
+// the namespace and the first few workloads with violating pods, e.g.
+//
+//	pod-security.kubernetes.io/enforce=baseline; violating workloads: ReplicaSet/a, StatefulSet/b, Pod/c and 2 more
+func remediationSuggestion(level string, workloads []string) string {
+	suggestion := fmt.Sprintf("%s=%s", psapi.EnforceLevelLabel, level)
+	if len(workloads) == 0 {
+		return suggestion
+	}
+
+	named := workloads[:min(len(workloads), maxRemediationWorkloads)]
+	suggestion += "; violating workloads: " + strings.Join(named, ", ")
+	if more := len(workloads) - len(named); more > 0 {
+		suggestion += fmt.Sprintf(" and %d more", more)
+	}
+
+	return suggestion
+}
+
+// annotateRemediation writes the remediation suggestion to a violating
+// customer namespace, and removes it once the namespace isn't violating
+// anymore, if remediation annotations are enabled. The suggestion only changes
+// with the violating workloads, not their pods, so that the namespace, and
+// with it the caches keyed by its resource version, is rarely written. It is
+// only a hint, so failures are logged rather than failing the evaluation.
+func (c *PodSecurityReadinessController) annotateRemediation(ctx context.Context, conditions *podSecurityOperatorConditions, ns *corev1.Namespace, result EvaluationResult) {
+	if !c.config.Results.RemediationAnnotations {
+		return
+	}
+
+	suggestion := ""
+	if result.Violating && conditions.classify(ns) == categoryCustomer {
+		var err error
+		suggestion, err = c.remediationSuggestion(ctx, conditions, ns, result.Level)
+		if err != nil {
+			klog.V(2).ErrorS(err, "Failed to determine the remediation suggestion", "namespace", ns.Name)
+			return
+		}
+	}
+
+	if len(suggestion) == 0 && !ownsRemediationAnnotation(ns) {
+		// The annotation of another field manager wouldn't be removed.
+		return
+	}
+	if current, ok := ns.Annotations[remediationAnnotation]; ok && current == suggestion {
+		// Applying the same suggestion again would be a no-op, but still
+		// costs a request.
+		return
+	}
+
+	// Applying a configuration without the annotation removes it, as the
+	// remediation field manager doesn't own any other field.
+	nsApply := applyconfiguration.Namespace(ns.Name)
+	if len(suggestion) > 0 {
+		nsApply.WithAnnotations(map[string]string{remediationAnnotation: suggestion})
+	}
+	_, err := c.namespacesClient().
+		Apply(ctx, nsApply, metav1.ApplyOptions{
+			FieldManager: remediationFieldManager,
+			Force:        true,
+		})
+	// Warnings about the annotation must not be attributed to the evaluation.
+	c.warningsHandler.PopAll()
+	if err != nil {
+		klog.V(2).ErrorS(err, "Failed to annotate the remediation suggestion", "namespace", ns.Name)
+		return
+	}
+	klog.V(4).InfoS("Annotated the remediation suggestion", "namespace", ns.Name, "suggestion", suggestion)
+}
+
+// remediationSuggestion suggests the strictest enforce level the pods of the
+// namespace satisfy and names the workloads whose pods violate the given
+// level. Unless the achievable level was already probed, it is determined
+// from the pods the evaluation listed rather than with more dry-run Applies.
+func (c *PodSecurityReadinessController) remediationSuggestion(ctx context.Context, conditions *podSecurityOperatorConditions, ns *corev1.Namespace, label string) (string, error) {
+	violated, err := psapi.ParseLevel(label)
+	if err != nil {
+		return "", err
+	}
+
+	pods, err := c.podsClient().List(ctx, ns.Name, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	// candidates are the levels less strict than the violated one that
+	// restrict anything, strictest first.
+	var candidates []psapi.Level
+	for _, level := range []psapi.Level{psapi.LevelRestricted, psapi.LevelBaseline} {
+		if psapi.CompareLevels(level, violated) < 0 {
+			candidates = append(candidates, level)
+		}
+	}
+
+	version := c.evaluationVersion(ns)
+	violatedCandidates := sets.New[psapi.Level]()
+	workloads := sets.New[string]()
+	evaluated := 0
+	for _, pod := range pods.Items {
+		if !c.isPodPhaseEvaluated(&pod) {
+			continue
+		}
+		// The suggestion is only a pointer, so evaluating part of the pods is
+		// good enough.
+		if c.config.Pods.MaxEvaluated > 0 && evaluated >= int(c.config.Pods.MaxEvaluated) {
+			break
+		}
+		evaluated++
+
+		if c.violatesLevel(&pod, violated, version) {
+			workloads.Insert(controllingWorkload(&pod))
+		}
+		for _, level := range candidates {
+			if !violatedCandidates.Has(level) && c.violatesLevel(&pod, level, version) {
+				violatedCandidates.Insert(level)
+			}
+		}
+	}
+
+	achievable, ok := conditions.achievableLevels[ns.Name]
+	if !ok {
+		achievable = string(psapi.LevelPrivileged)
+		for _, level := range candidates {
+			if !violatedCandidates.Has(level) {
+				achievable = string(level)
+				break
+			}
+		}
+	}
+
+	return remediationSuggestion(achievable, slices.Sorted(slices.Values(workloads.UnsortedList()))), nil
+}
+
+// violatesLevel checks whether the pod fails any check of the level.
+func (c *PodSecurityReadinessController) violatesLevel(pod *corev1.Pod, level psapi.Level, version psapi.Version) bool {
+	if level == psapi.LevelPrivileged {
+		return false
+	}
+
+	enforcement := psapi.LevelVersion{Level: level, Version: version}
+	for _, result := range c.psaEvaluator.EvaluatePod(enforcement, &pod.ObjectMeta, &pod.Spec) {
+		if !result.Allowed {
+			return true
+		}
+	}
+
+	return false
+}
+
+// controllingWorkload identifies the controller of the pod as kind and name,
+// or the pod itself if it has none. Unlike the name of the pod, it doesn't
+// change when the pod is recreated.
+func controllingWorkload(pod *corev1.Pod) string {
+	if owner := metav1.GetControllerOf(pod); owner != nil {
+		return owner.Kind + "/" + owner.Name
+	}
+
+	return "Pod/" + pod.Name
+}
+
+// ownsRemediationAnnotation checks whether the remediation field manager owns
+// any field of the namespace, which can only be the remediation annotation.
+func ownsRemediationAnnotation(ns *corev1.Namespace) bool {
+	for _, entry := range ns.ManagedFields {
+		if entry.Manager == remediationFieldManager && entry.Operation == metav1.ManagedFieldsOperationApply {
+			return true
+		}
+	}
+
+	return false
+}
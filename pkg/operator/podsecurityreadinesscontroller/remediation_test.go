@@ -0,0 +1,255 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+)
+
+func TestRemediationSuggestion(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		level     string
+		workloads []string
+		expected  string
+	}{
+		{
+			name:     "no workloads",
+			level:    "baseline",
+			expected: "pod-security.kubernetes.io/enforce=baseline",
+		},
+		{
+			name:      "few workloads",
+			level:     "baseline",
+			workloads: []string{"Pod/a", "ReplicaSet/b"},
+			expected:  "pod-security.kubernetes.io/enforce=baseline; violating workloads: Pod/a, ReplicaSet/b",
+		},
+		{
+			name:      "many workloads",
+			level:     "privileged",
+			workloads: []string{"Pod/a", "Pod/b", "Pod/c", "Pod/d", "Pod/e"},
+			expected:  "pod-security.kubernetes.io/enforce=privileged; violating workloads: Pod/a, Pod/b, Pod/c and 2 more",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if suggestion := remediationSuggestion(tt.level, tt.workloads); suggestion != tt.expected {
+				t.Errorf("expected suggestion %q, got %q", tt.expected, suggestion)
+			}
+		})
+	}
+}
+
+func TestRemediationAnnotations(t *testing.T) {
+	newNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:          name,
+				Annotations:   map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+	// The pods violate restricted, but not baseline.
+	newPod := func(name string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "customer",
+				OwnerReferences: []metav1.OwnerReference{{
+					APIVersion: "apps/v1",
+					Kind:       "ReplicaSet",
+					Name:       "app-5d8f",
+					Controller: ptr.To(true),
+				}},
+			},
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+	}
+
+	psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newController := func(t *testing.T, violating map[string]bool, results ResultsConfig) (*PodSecurityReadinessController, *fake.Clientset, *[]string) {
+		handler := &warningsHandler{}
+		fakeClient := fake.NewSimpleClientset(newNamespace("customer"), newNamespace("openshift-platform"), newPod("app-5d8f-x2k9p"))
+
+		// Dry runs of restricted warn about the violating namespaces, the
+		// remediation annotation is persisted like a server-side apply would.
+		var annotated []string
+		fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			patch := action.(clienttesting.PatchActionImpl)
+			if len(patch.PatchOptions.DryRun) > 0 {
+				level := fmt.Sprintf("%q:%q", psapi.EnforceLevelLabel, psapi.LevelRestricted)
+				if violating[patch.Name] && strings.Contains(string(patch.Patch), level) {
+					handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"restricted:latest\"", patch.Name))
+				}
+				return true, nil, nil
+			}
+			if patch.PatchOptions.FieldManager != remediationFieldManager {
+				t.Fatalf("unexpected field manager %q", patch.PatchOptions.FieldManager)
+			}
+
+			var applied corev1.Namespace
+			if err := json.Unmarshal(patch.Patch, &applied); err != nil {
+				return true, nil, err
+			}
+			obj, err := fakeClient.Tracker().Get(corev1.SchemeGroupVersion.WithResource("namespaces"), "", patch.Name)
+			if err != nil {
+				return true, nil, err
+			}
+			ns := obj.(*corev1.Namespace)
+			ns.ManagedFields = managedFields
+			delete(ns.Annotations, remediationAnnotation)
+			if suggestion, ok := applied.Annotations[remediationAnnotation]; ok {
+				ns.Annotations[remediationAnnotation] = suggestion
+				ns.ManagedFields = append(managedFields, metav1.ManagedFieldsEntry{
+					Manager:   remediationFieldManager,
+					Operation: metav1.ManagedFieldsOperationApply,
+				})
+			}
+			annotated = append(annotated, patch.Name)
+
+			return true, ns, fakeClient.Tracker().Update(corev1.SchemeGroupVersion.WithResource("namespaces"), ns, "")
+		})
+
+		controller := &PodSecurityReadinessController{
+			kubeClient:      fakeClient,
+			operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			clock:           clock.RealClock{},
+			warningsHandler: handler,
+			psaEvaluator:    psaEvaluator,
+			dryRunVerified:  true,
+			config:          Config{Results: results},
+		}
+
+		return controller, fakeClient, &annotated
+	}
+
+	annotation := func(t *testing.T, fakeClient *fake.Clientset, name string) (string, bool) {
+		ns, err := fakeClient.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		suggestion, ok := ns.Annotations[remediationAnnotation]
+		return suggestion, ok
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+
+	t.Run("annotates and removes the suggestion", func(t *testing.T) {
+		violating := map[string]bool{"customer": true, "openshift-platform": true}
+		controller, fakeClient, annotated := newController(t, violating, ResultsConfig{RemediationAnnotations: true})
+		var podLists []string
+		fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			podLists = append(podLists, action.GetNamespace())
+			return false, nil, nil
+		})
+
+		for _, step := range []struct {
+			name      string
+			recreate  bool
+			remediate bool
+
+			expectedAnnotated  []string
+			expectedSuggestion string
+		}{
+			{
+				name:               "violating",
+				expectedAnnotated:  []string{"customer"},
+				expectedSuggestion: "pod-security.kubernetes.io/enforce=baseline; violating workloads: ReplicaSet/app-5d8f",
+			},
+			{
+				name:               "still violating",
+				expectedSuggestion: "pod-security.kubernetes.io/enforce=baseline; violating workloads: ReplicaSet/app-5d8f",
+			},
+			{
+				name:               "pod recreated",
+				recreate:           true,
+				expectedSuggestion: "pod-security.kubernetes.io/enforce=baseline; violating workloads: ReplicaSet/app-5d8f",
+			},
+			{
+				name:              "remediated",
+				remediate:         true,
+				expectedAnnotated: []string{"customer"},
+			},
+			{
+				name: "still remediated",
+			},
+		} {
+			t.Run(step.name, func(t *testing.T) {
+				*annotated = nil
+				podLists = nil
+				if step.recreate {
+					if err := fakeClient.CoreV1().Pods("customer").Delete(context.TODO(), "app-5d8f-x2k9p", metav1.DeleteOptions{}); err != nil {
+						t.Fatal(err)
+					}
+					if _, err := fakeClient.CoreV1().Pods("customer").Create(context.TODO(), newPod("app-5d8f-7vqzt"), metav1.CreateOptions{}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				if step.remediate {
+					violating["customer"] = false
+				}
+
+				if err := controller.sync(context.TODO(), syncCtx); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if strings.Join(*annotated, ",") != strings.Join(step.expectedAnnotated, ",") {
+					t.Errorf("expected applied remediation annotations to %v, got %v", step.expectedAnnotated, *annotated)
+				}
+				suggestion, ok := annotation(t, fakeClient, "customer")
+				if expected := len(step.expectedSuggestion) > 0; ok != expected || suggestion != step.expectedSuggestion {
+					t.Errorf("expected suggestion %q, got %q (annotated: %v)", step.expectedSuggestion, suggestion, ok)
+				}
+				if suggestion, ok := annotation(t, fakeClient, "openshift-platform"); ok {
+					t.Errorf("expected no suggestion on the platform namespace, got %q", suggestion)
+				}
+				lists := 0
+				for _, namespace := range podLists {
+					if namespace == "customer" {
+						lists++
+					}
+				}
+				if lists > 1 {
+					t.Errorf("expected the pods of the namespace to be listed at most once, got %d lists", lists)
+				}
+			})
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		controller, fakeClient, annotated := newController(t, map[string]bool{"customer": true}, ResultsConfig{})
+
+		if err := controller.sync(context.TODO(), syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if len(*annotated) != 0 {
+			t.Errorf("expected no remediation annotations to be applied, got %v", *annotated)
+		}
+		if suggestion, ok := annotation(t, fakeClient, "customer"); ok {
+			t.Errorf("expected no suggestion, got %q", suggestion)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	psapi "k8s.io/pod-security-admission/api"
+)
+
+// Report is the outcome of a full evaluation of all namespaces that don't
+// enforce pod security yet.
+type Report struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	// PolicyVersion is the pod security admission version namespaces
+	// without an enforce version are evaluated against.
+	PolicyVersion string `json:"policyVersion"`
+
+	Categories CategorizedNamespaces `json:"categories"`
+	Namespaces []NamespaceReport     `json:"namespaces"`
+}
+
+// NamespaceReport is the outcome of the evaluation of a single namespace.
+type NamespaceReport struct {
+	Name string `json:"name"`
+	// Category is the category the namespace is reported in if it is
+	// violating.
+	Category      string    `json:"category"`
+	PolicyVersion string    `json:"policyVersion,omitempty"`
+	EvaluatedAt   time.Time `json:"evaluatedAt"`
+	// OptedOut is set if the namespace wasn't evaluated because its owner
+	// opted it out.
+	OptedOut bool `json:"optedOut,omitempty"`
+
+	EvaluationResult `json:",inline"`
+	// Error is set if the namespace couldn't be evaluated.
+	Error string `json:"error,omitempty"`
+}
+
+// ErrNoReport is returned by GenerateReport until a sync completed the
+// evaluation of all namespaces.
+var ErrNoReport = errors.New("no evaluation of the namespaces has completed yet")
+
+// GenerateReport returns the outcome of the last sync that evaluated all
+// namespaces that don't enforce pod security yet. It doesn't evaluate any
+// namespace itself, so it can be called at any time, e.g. by tooling through
+// DebugHandler, without competing with the sync or adding load to the
+// apiserver. The report is a copy, so callers can't change the state of the
+// controller through it.
+func (c *PodSecurityReadinessController) GenerateReport(ctx context.Context) (*Report, error) {
+	c.lastConditionsLock.RLock()
+	defer c.lastConditionsLock.RUnlock()
+
+	if c.lastReport == nil {
+		return nil, ErrNoReport
+	}
+
+	report := *c.lastReport
+	report.Categories = c.lastReport.Categories.deepCopy()
+	report.Namespaces = slices.Clone(c.lastReport.Namespaces)
+	return &report, nil
+}
+
+// newReport returns the report of an evaluation from its conditions and the
+// outcome of every namespace.
+func newReport(generatedAt time.Time, conditions *podSecurityOperatorConditions, namespaces []NamespaceReport) *Report {
+	slices.SortFunc(namespaces, func(a, b NamespaceReport) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return &Report{
+		GeneratedAt:   generatedAt,
+		PolicyVersion: psapi.LatestVersion().String(),
+		Categories:    newCategorizedNamespaces(conditions),
+		Namespaces:    namespaces,
+	}
+}
+
+// newNamespaceReport returns the report of the namespace without the outcome
+// of its evaluation.
+func newNamespaceReport(conditions *podSecurityOperatorConditions, ns *corev1.Namespace) NamespaceReport {
+	return NamespaceReport{
+		Name:          ns.Name,
+		Category:      conditions.classify(ns).String(),
+		PolicyVersion: enforceVersionForNamespace(ns).String(),
+	}
+}
+
+// NamespaceReadiness evaluates the namespace with the given name and returns
+// the result. It bypasses the evaluation cache and doesn't depend on or change
+// the state of the sync, so it can back targeted queries, e.g. by admission
+// integrations. As warnings can't be attributed to concurrent evaluations, it
+// waits for a running sync to complete. The error of a namespace that doesn't
+// exist satisfies apierrors.IsNotFound.
+func (c *PodSecurityReadinessController) NamespaceReadiness(ctx context.Context, name string) (*EvaluationResult, error) {
+	c.evaluationLock.Lock()
+	defer c.evaluationLock.Unlock()
+
+	ns, err := c.namespacesClient().Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := c.evaluateNamespaceViolation(ctx, ns)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
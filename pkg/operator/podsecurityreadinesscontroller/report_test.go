@@ -0,0 +1,235 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+)
+
+func TestGenerateReport(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	newNamespace := func(name string, annotations, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:          name,
+				Annotations:   annotations,
+				Labels:        labels,
+				ManagedFields: managedFields,
+			},
+		}
+	}
+	restricted := map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"}
+	violationWarning := "existing pods in namespace \"customer-violating\" violate the new PodSecurity enforce level \"restricted:latest\""
+
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset(
+		newNamespace("customer-violating", restricted, nil),
+		newNamespace("openshift-clean", map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "baseline"}, nil),
+		newNamespace("unlabeled", nil, nil),
+		newNamespace("versioned", restricted, map[string]string{psapi.EnforceVersionLabel: "v1.29"}),
+		newNamespace("opted-out", map[string]string{readinessOptOutAnnotation: "true"}, nil),
+	)
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if action.(clienttesting.PatchAction).GetName() == "customer-violating" {
+			handler.HandleWarningHeader(299, "", violationWarning)
+		}
+		return true, nil, nil
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clocktesting.NewFakePassiveClock(now),
+		warningsHandler: handler,
+		dryRunVerified:  true,
+		config:          Config{Pods: PodsConfig{SkipUserSCCCheck: true}},
+	}
+
+	if _, err := controller.GenerateReport(context.TODO()); !errors.Is(err, ErrNoReport) {
+		t.Fatalf("expected no report before the first sync, got %v", err)
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The report is taken from the sync, it doesn't evaluate any namespace.
+	actions := len(fakeClient.Actions())
+	report, err := controller.GenerateReport(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fakeClient.Actions()) != actions {
+		t.Errorf("expected no requests, got %v", fakeClient.Actions()[actions:])
+	}
+
+	if !report.GeneratedAt.Equal(now) {
+		t.Errorf("expected the report to be generated at %v, got %v", now, report.GeneratedAt)
+	}
+	if report.PolicyVersion != psapi.LatestVersion().String() {
+		t.Errorf("expected policy version %q, got %q", psapi.LatestVersion().String(), report.PolicyVersion)
+	}
+
+	expectedCategories := CategorizedNamespaces{
+		Customer:     []string{"customer-violating"},
+		Inconclusive: []string{"unlabeled"},
+		OptedOut:     []string{"opted-out"},
+		// The syncer is enabled, but didn't label the namespace.
+		SyncerPending: []string{"unlabeled"},
+	}
+	if !reflect.DeepEqual(report.Categories, expectedCategories) {
+		t.Errorf("expected categories %+v, got %+v", expectedCategories, report.Categories)
+	}
+
+	latest := psapi.LatestVersion().String()
+	expectedNamespaces := []NamespaceReport{
+		{
+			Name:          "customer-violating",
+			Category:      "customer",
+			PolicyVersion: latest,
+			EvaluatedAt:   now,
+			EvaluationResult: EvaluationResult{
+				Violating: true,
+				Level:     "restricted",
+				Reason:    violationWarning,
+			},
+		},
+		{
+			Name:             "openshift-clean",
+			Category:         "openShift",
+			PolicyVersion:    latest,
+			EvaluatedAt:      now,
+			EvaluationResult: EvaluationResult{Level: "baseline"},
+		},
+		{
+			Name:          "opted-out",
+			Category:      "customer",
+			PolicyVersion: latest,
+			OptedOut:      true,
+		},
+		{
+			Name:          "unlabeled",
+			Category:      "customer",
+			PolicyVersion: latest,
+			EvaluatedAt:   now,
+			Error:         ErrNoLabels.Error(),
+		},
+		{
+			Name:             "versioned",
+			Category:         "customer",
+			PolicyVersion:    "v1.29",
+			EvaluatedAt:      now,
+			EvaluationResult: EvaluationResult{Level: "restricted"},
+		},
+	}
+	if !reflect.DeepEqual(report.Namespaces, expectedNamespaces) {
+		t.Errorf("expected namespaces\n%+v\ngot\n%+v", expectedNamespaces, report.Namespaces)
+	}
+
+	// The report is a copy.
+	report.Namespaces[0].Name = "changed"
+	report.Categories.Customer[0] = "changed"
+	again, err := controller.GenerateReport(context.TODO())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(again.Namespaces, expectedNamespaces) || !reflect.DeepEqual(again.Categories, expectedCategories) {
+		t.Errorf("expected the report to be unchanged, got %+v", again)
+	}
+}
+
+func TestNamespaceReadiness(t *testing.T) {
+	newNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:          name,
+				Annotations:   map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+	violationWarning := "existing pods in namespace \"violating\" violate the new PodSecurity enforce level \"restricted:latest\""
+
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset(newNamespace("violating"), newNamespace("clean"))
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if action.(clienttesting.PatchAction).GetName() == "violating" {
+			handler.HandleWarningHeader(299, "", violationWarning)
+		}
+		return true, nil, nil
+	})
+
+	psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The sync never ran.
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		clock:           clocktesting.NewFakePassiveClock(time.Now()),
+		warningsHandler: handler,
+		psaEvaluator:    psaEvaluator,
+	}
+
+	for _, tt := range []struct {
+		name     string
+		expected *EvaluationResult
+	}{
+		{
+			name: "violating",
+			expected: &EvaluationResult{
+				Violating: true,
+				Level:     "restricted",
+				Reason:    violationWarning,
+			},
+		},
+		{
+			name:     "clean",
+			expected: &EvaluationResult{Level: "restricted"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := controller.NamespaceReadiness(context.TODO(), tt.name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("expected %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+
+	t.Run("nonexistent", func(t *testing.T) {
+		result, err := controller.NamespaceReadiness(context.TODO(), "nonexistent")
+		if !apierrors.IsNotFound(err) {
+			t.Errorf("expected a not found error, got %v", err)
+		}
+		if result != nil {
+			t.Errorf("expected no result, got %+v", result)
+		}
+	})
+
+	if snapshot := controller.snapshot(); len(snapshot.violatingCustomerNamespaces) != 0 {
+		t.Errorf("expected the conditions of the sync to be unchanged, got %v", snapshot.violatingCustomerNamespaces)
+	}
+}
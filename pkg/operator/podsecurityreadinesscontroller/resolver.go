@@ -0,0 +1,199 @@
+package podsecurityreadinesscontroller
+
+import (
+	"errors"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	applyconfiguration "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/klog/v2"
+	psapi "k8s.io/pod-security-admission/api"
+)
+
+// levelSource is where the enforce level of a namespace is resolved from.
+type levelSource string
+
+const (
+	// levelSourceEnforceLabel is the enforce label of the namespace, which
+	// the apiserver already enforces.
+	levelSourceEnforceLabel levelSource = "enforceLabel"
+	// levelSourceSyncer is the level the syncer would set, derived from the
+	// minimally sufficient pod security annotation or the alert labels.
+	levelSourceSyncer levelSource = "syncer"
+	// levelSourceClusterDefault is the default level of the PodSecurity
+	// admission plugin, for namespaces without any pod security labels.
+	levelSourceClusterDefault levelSource = "clusterDefault"
+)
+
+// resolvedLevel is the enforce level and version a namespace is evaluated
+// against. The level is kept as a string, as the level the syncer would set
+// isn't necessarily valid.
+type resolvedLevel struct {
+	level   string
+	version psapi.Version
+	source  levelSource
+}
+
+// levelCache remembers the resolved levels of namespaces by resource version.
+// Extracting the syncer-managed fields is the expensive part of the
+// resolution, and most namespaces don't change between syncs.
+type levelCache struct {
+	lock    sync.Mutex
+	entries map[string]levelCacheEntry
+}
+
+type levelCacheEntry struct {
+	resourceVersion     string
+	clusterDefaultLevel string
+
+	nsApplyConfig *applyconfiguration.NamespaceApplyConfiguration
+	resolved      resolvedLevel
+}
+
+func newLevelCache() *levelCache {
+	return &levelCache{
+		entries: map[string]levelCacheEntry{},
+	}
+}
+
+// get returns the cached entry of the namespace if it is still valid for the
+// given key.
+func (l *levelCache) get(namespace string, key levelCacheEntry) (levelCacheEntry, bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	entry, ok := l.entries[namespace]
+	if !ok || entry.resourceVersion != key.resourceVersion || entry.clusterDefaultLevel != key.clusterDefaultLevel {
+		return levelCacheEntry{}, false
+	}
+
+	return entry, true
+}
+
+func (l *levelCache) set(namespace string, entry levelCacheEntry) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.entries[namespace] = entry
+}
+
+// retain drops the entries of all namespaces that aren't listed anymore.
+func (l *levelCache) retain(namespaces sets.Set[string]) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	for namespace := range l.entries {
+		if !namespaces.Has(namespace) {
+			delete(l.entries, namespace)
+		}
+	}
+}
+
+// resolveEnforceLevelCached resolves the enforce level of the namespace,
+// reusing the previous resolution as long as the namespace and the cluster
+// default didn't change. The returned apply configuration is shared and must
+// not be modified.
+func (c *PodSecurityReadinessController) resolveEnforceLevelCached(ns *corev1.Namespace) (*applyconfiguration.NamespaceApplyConfiguration, resolvedLevel, error) {
+	// Objects that weren't read from the apiserver don't have a resource
+	// version to tell their changes apart.
+	if c.levelCache == nil || len(ns.ResourceVersion) == 0 {
+		return c.resolveEnforceLevel(ns)
+	}
+
+	key := levelCacheEntry{
+		resourceVersion:     ns.ResourceVersion,
+		clusterDefaultLevel: c.clusterDefaultEnforceLevel,
+	}
+	if entry, ok := c.levelCache.get(ns.Name, key); ok {
+		return entry.nsApplyConfig, entry.resolved, nil
+	}
+
+	nsApplyConfig, resolved, err := c.resolveEnforceLevel(ns)
+	if err != nil {
+		return nil, resolvedLevel{}, err
+	}
+
+	key.nsApplyConfig = nsApplyConfig
+	key.resolved = resolved
+	c.levelCache.set(ns.Name, key)
+
+	return nsApplyConfig, resolved, nil
+}
+
+// extractSyncerFields returns the fields of the namespace that are owned by the
+// syncer, together with the labels and annotations owned by any of the trusted
+// field managers. Fields owned by several managers have the same value.
+func (c *PodSecurityReadinessController) extractSyncerFields(ns *corev1.Namespace) (*applyconfiguration.NamespaceApplyConfiguration, error) {
+	nsApplyConfig, err := applyconfiguration.ExtractNamespace(ns, c.config.Levels.syncerControllerName())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, manager := range c.config.Levels.TrustedFieldManagers {
+		trusted, err := applyconfiguration.ExtractNamespace(ns, manager)
+		if err != nil {
+			return nil, err
+		}
+		if len(trusted.Labels) > 0 {
+			nsApplyConfig.WithLabels(trusted.Labels)
+		}
+		if len(trusted.Annotations) > 0 {
+			nsApplyConfig.WithAnnotations(trusted.Annotations)
+		}
+	}
+
+	return nsApplyConfig, nil
+}
+
+// resolveEnforceLevel returns the syncer-managed fields of the namespace and
+// the enforce level the apiserver would apply to it, in order of precedence:
+//
+//  1. The enforce label of the namespace, with invalid levels enforced as
+//     restricted, like the admission plugin does.
+//  2. The level the syncer would set, clamped to the minimum and maximum
+//     levels.
+//  3. The cluster default, if it is evaluated, clamped likewise.
+//
+// The version is the enforce version of the namespace, see
+// enforceVersionForNamespace.
+func (c *PodSecurityReadinessController) resolveEnforceLevel(ns *corev1.Namespace) (*applyconfiguration.NamespaceApplyConfiguration, resolvedLevel, error) {
+	nsApplyConfig, err := c.extractSyncerFields(ns)
+	if err != nil {
+		return nil, resolvedLevel{}, err
+	}
+
+	version := enforceVersionForNamespace(ns)
+	if value, ok := ns.Labels[psapi.EnforceLevelLabel]; ok {
+		// Like the admission plugin, invalid levels are enforced as
+		// restricted.
+		level, err := psapi.ParseLevel(value)
+		if err != nil {
+			klog.V(4).InfoS("Invalid enforce level, resolving to restricted", "namespace", ns.Name, "value", value, "err", err)
+		}
+
+		return nsApplyConfig, resolvedLevel{
+			level:   string(level),
+			version: version,
+			source:  levelSourceEnforceLabel,
+		}, nil
+	}
+
+	source := levelSourceSyncer
+	level, err := determineEnforceLabelForNamespace(nsApplyConfig, c.config.Levels.AlertLabelPreference)
+	if errors.Is(err, ErrNoLabels) && c.clusterDefaultEnforceLevel != "" {
+		// The apiserver falls back to the cluster-wide default for namespaces
+		// without any pod security labels.
+		source = levelSourceClusterDefault
+		level, err = c.clusterDefaultEnforceLevel, nil
+	}
+	if err != nil {
+		return nil, resolvedLevel{}, err
+	}
+
+	return nsApplyConfig, resolvedLevel{
+		level:   clampLevel(level, c.config.Levels.Minimum, c.config.Levels.Maximum),
+		version: version,
+		source:  source,
+	}, nil
+}
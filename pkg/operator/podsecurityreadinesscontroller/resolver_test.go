@@ -0,0 +1,313 @@
+package podsecurityreadinesscontroller
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/utils/clock"
+)
+
+func TestResolveEnforceLevel(t *testing.T) {
+	for _, tt := range []struct {
+		name                string
+		annotations         map[string]string
+		labels              map[string]string
+		clusterDefaultLevel string
+		minimumLevel        psapi.Level
+		maximumLevel        psapi.Level
+
+		expected    resolvedLevel
+		expectError error
+	}{
+		{
+			name:        "enforce label takes precedence over the annotation",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			labels:      map[string]string{psapi.EnforceLevelLabel: "baseline"},
+			expected:    resolvedLevel{level: "baseline", version: psapi.LatestVersion(), source: levelSourceEnforceLabel},
+		},
+		{
+			name:                "enforce label takes precedence over the cluster default",
+			labels:              map[string]string{psapi.EnforceLevelLabel: "privileged"},
+			clusterDefaultLevel: "restricted",
+			expected:            resolvedLevel{level: "privileged", version: psapi.LatestVersion(), source: levelSourceEnforceLabel},
+		},
+		{
+			name:     "invalid enforce label is enforced as restricted",
+			labels:   map[string]string{psapi.EnforceLevelLabel: "restricetd"},
+			expected: resolvedLevel{level: "restricted", version: psapi.LatestVersion(), source: levelSourceEnforceLabel},
+		},
+		{
+			name:         "enforce label isn't clamped",
+			labels:       map[string]string{psapi.EnforceLevelLabel: "privileged"},
+			minimumLevel: psapi.LevelBaseline,
+			expected:     resolvedLevel{level: "privileged", version: psapi.LatestVersion(), source: levelSourceEnforceLabel},
+		},
+		{
+			name:     "enforce label with version",
+			labels:   map[string]string{psapi.EnforceLevelLabel: "baseline", psapi.EnforceVersionLabel: "v1.25"},
+			expected: resolvedLevel{level: "baseline", version: psapi.MajorMinorVersion(1, 25), source: levelSourceEnforceLabel},
+		},
+		{
+			name:        "annotation",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			expected:    resolvedLevel{level: "restricted", version: psapi.LatestVersion(), source: levelSourceSyncer},
+		},
+		{
+			name:     "alert labels",
+			labels:   map[string]string{psapi.WarnLevelLabel: "baseline", psapi.AuditLevelLabel: "restricted"},
+			expected: resolvedLevel{level: "restricted", version: psapi.LatestVersion(), source: levelSourceSyncer},
+		},
+		{
+			name:                "annotation takes precedence over the cluster default",
+			annotations:         map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "baseline"},
+			clusterDefaultLevel: "restricted",
+			expected:            resolvedLevel{level: "baseline", version: psapi.LatestVersion(), source: levelSourceSyncer},
+		},
+		{
+			name:         "annotation is clamped",
+			annotations:  map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "privileged"},
+			minimumLevel: psapi.LevelBaseline,
+			expected:     resolvedLevel{level: "baseline", version: psapi.LatestVersion(), source: levelSourceSyncer},
+		},
+		{
+			name:        "annotation with enforce version",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			labels:      map[string]string{psapi.EnforceVersionLabel: "v1.29"},
+			expected:    resolvedLevel{level: "restricted", version: psapi.MajorMinorVersion(1, 29), source: levelSourceSyncer},
+		},
+		{
+			name:        "annotation with invalid enforce version",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			labels:      map[string]string{psapi.EnforceVersionLabel: "v2"},
+			expected:    resolvedLevel{level: "restricted", version: psapi.LatestVersion(), source: levelSourceSyncer},
+		},
+		{
+			name:                "cluster default",
+			clusterDefaultLevel: "baseline",
+			expected:            resolvedLevel{level: "baseline", version: psapi.LatestVersion(), source: levelSourceClusterDefault},
+		},
+		{
+			name:                "cluster default is clamped",
+			clusterDefaultLevel: "restricted",
+			maximumLevel:        psapi.LevelBaseline,
+			expected:            resolvedLevel{level: "baseline", version: psapi.LatestVersion(), source: levelSourceClusterDefault},
+		},
+		{
+			name:        "no labels without cluster default",
+			expectError: ErrNoLabels,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &PodSecurityReadinessController{
+				clusterDefaultEnforceLevel: tt.clusterDefaultLevel,
+				config:                     Config{Levels: LevelsConfig{Minimum: tt.minimumLevel, Maximum: tt.maximumLevel}},
+			}
+
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:          "test-ns",
+					Annotations:   tt.annotations,
+					Labels:        tt.labels,
+					ManagedFields: managedFields,
+				},
+			}
+
+			_, resolved, err := controller.resolveEnforceLevel(ns)
+			if !errors.Is(err, tt.expectError) {
+				t.Fatalf("expected error %v, got %v", tt.expectError, err)
+			}
+			if resolved != tt.expected {
+				t.Errorf("expected %+v, got %+v", tt.expected, resolved)
+			}
+		})
+	}
+}
+
+func TestLevelCache(t *testing.T) {
+	controller := &PodSecurityReadinessController{
+		levelCache: newLevelCache(),
+	}
+	namespace := func(resourceVersion, level string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "test-ns",
+				ResourceVersion: resourceVersion,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: level,
+				},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+	expectLevel := func(ns *corev1.Namespace, expected string) {
+		t.Helper()
+
+		_, resolved, err := controller.resolveEnforceLevelCached(ns)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resolved.level != expected {
+			t.Errorf("expected level %q, got %q", expected, resolved.level)
+		}
+	}
+
+	expectLevel(namespace("1", "restricted"), "restricted")
+	// The level isn't derived again for the same resource version.
+	expectLevel(namespace("1", "baseline"), "restricted")
+	expectLevel(namespace("2", "baseline"), "baseline")
+
+	// Namespaces without a resource version aren't cached.
+	expectLevel(namespace("", "privileged"), "privileged")
+	expectLevel(namespace("2", "restricted"), "baseline")
+
+	// A changed cluster default invalidates the cached levels.
+	unlabeled := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "unlabeled", ResourceVersion: "1"}}
+	controller.clusterDefaultEnforceLevel = "baseline"
+	expectLevel(unlabeled, "baseline")
+	controller.clusterDefaultEnforceLevel = "restricted"
+	expectLevel(unlabeled, "restricted")
+
+	controller.levelCache.retain(sets.New("unlabeled"))
+	expectLevel(namespace("2", "restricted"), "restricted")
+}
+
+func BenchmarkResolveEnforceLevel(b *testing.B) {
+	namespaces := make([]*corev1.Namespace, 1000)
+	for i := range namespaces {
+		namespaces[i] = &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            fmt.Sprintf("ns-%d", i),
+				ResourceVersion: "1",
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				Labels: map[string]string{
+					psapi.WarnLevelLabel:  "restricted",
+					psapi.AuditLevelLabel: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+
+	for _, bb := range []struct {
+		name       string
+		levelCache *levelCache
+	}{
+		{name: "uncached"},
+		{name: "cached", levelCache: newLevelCache()},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			controller := &PodSecurityReadinessController{
+				levelCache: bb.levelCache,
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, ns := range namespaces {
+					if _, _, err := controller.resolveEnforceLevelCached(ns); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestTrustedFieldManagers(t *testing.T) {
+	managedFieldsOf := func(manager, fields string) metav1.ManagedFieldsEntry {
+		return metav1.ManagedFieldsEntry{
+			Manager:   manager,
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1:  &metav1.FieldsV1{Raw: []byte(fields)},
+		}
+	}
+	// The labels are split across the syncer and another syncer.
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Labels: map[string]string{
+				psapi.WarnLevelLabel:  "baseline",
+				psapi.AuditLevelLabel: "restricted",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				managedFieldsOf(defaultSyncerControllerName, fmt.Sprintf(`{"f:metadata":{"f:labels":{"f:%s":{}}}}`, psapi.WarnLevelLabel)),
+				managedFieldsOf("hybrid-syncer", fmt.Sprintf(`{"f:metadata":{"f:labels":{"f:%s":{}}}}`, psapi.AuditLevelLabel)),
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		managers []string
+
+		expectedLevel  string
+		expectedLabels map[string]string
+	}{
+		{
+			name:           "syncer only",
+			expectedLevel:  "baseline",
+			expectedLabels: map[string]string{psapi.WarnLevelLabel: "baseline"},
+		},
+		{
+			name:           "trusted field manager",
+			managers:       []string{"hybrid-syncer"},
+			expectedLevel:  "restricted",
+			expectedLabels: map[string]string{psapi.WarnLevelLabel: "baseline", psapi.AuditLevelLabel: "restricted"},
+		},
+		{
+			name:           "trusted field manager without fields",
+			managers:       []string{"unrelated"},
+			expectedLevel:  "baseline",
+			expectedLabels: map[string]string{psapi.WarnLevelLabel: "baseline"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &PodSecurityReadinessController{
+				config: Config{Levels: LevelsConfig{TrustedFieldManagers: tt.managers}},
+			}
+
+			nsApplyConfig, resolved, err := controller.resolveEnforceLevel(ns)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resolved.level != tt.expectedLevel {
+				t.Errorf("expected level %q, got %q", tt.expectedLevel, resolved.level)
+			}
+			if !reflect.DeepEqual(nsApplyConfig.Labels, tt.expectedLabels) {
+				t.Errorf("expected labels %v, got %v", tt.expectedLabels, nsApplyConfig.Labels)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		name     string
+		managers []string
+	}{
+		{name: "empty manager", managers: []string{"hybrid-syncer", ""}},
+		{name: "own field manager", managers: []string{readinessFieldManager}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newPodSecurityReadinessController(
+				v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				events.NewInMemoryRecorder("test", clock.RealClock{}),
+				NewWarningsHandler(),
+				Config{Levels: LevelsConfig{TrustedFieldManagers: tt.managers}},
+			)
+			if err == nil {
+				t.Errorf("expected the trusted field managers %q to be rejected", tt.managers)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applyconfiguration "k8s.io/client-go/applyconfigurations/core/v1"
+)
+
+const (
+	resultsFieldManager = "pod-security-readiness-results"
+	resultsKey          = "results.json"
+)
+
+// CategorizedNamespaces holds the namespaces of every category. It is the
+// content of the results ConfigMap, for consumers that can't read the
+// operator status.
+type CategorizedNamespaces struct {
+	Customer            []string `json:"customer,omitempty"`
+	OpenShift           []string `json:"openshift,omitempty"`
+	RunLevelZero        []string `json:"runLevelZero,omitempty"`
+	DisabledSyncer      []string `json:"disabledSyncer,omitempty"`
+	AddOn               []string `json:"addOn,omitempty"`
+	Inconclusive        []string `json:"inconclusive,omitempty"`
+	UserSCC             []string `json:"userSCC,omitempty"`
+	UserSCCInconclusive []string `json:"userSCCInconclusive,omitempty"`
+	EnforcedRegression  []string `json:"enforcedRegression,omitempty"`
+	OptedOut            []string `json:"optedOut,omitempty"`
+	Misconfigured       []string `json:"misconfigured,omitempty"`
+	VolumeOnly          []string `json:"volumeOnly,omitempty"`
+	FailedClosed        []string `json:"failedClosed,omitempty"`
+	SyncerPending       []string `json:"syncerPending,omitempty"`
+	BackedOff           []string `json:"backedOff,omitempty"`
+}
+
+func newCategorizedNamespaces(conditions *podSecurityOperatorConditions) CategorizedNamespaces {
+	return CategorizedNamespaces{
+		Customer:            sortedClone(conditions.violatingCustomerNamespaces),
+		OpenShift:           sortedClone(conditions.violatingOpenShiftNamespaces),
+		RunLevelZero:        sortedClone(conditions.violatingRunLevelZeroNamespaces),
+		DisabledSyncer:      sortedClone(conditions.violatingDisabledSyncerNamespaces),
+		AddOn:               sortedClone(conditions.violatingAddOnNamespaces),
+		Inconclusive:        sortedClone(conditions.inconclusiveNamespaces),
+		UserSCC:             sortedClone(conditions.userSCCViolatingNamespaces),
+		UserSCCInconclusive: sortedClone(conditions.userSCCInconclusiveNamespaces),
+		EnforcedRegression:  sortedClone(conditions.regressedEnforcingNamespaces),
+		OptedOut:            sortedClone(conditions.optedOutNamespaces),
+		Misconfigured:       sortedClone(conditions.misconfiguredNamespaces),
+		VolumeOnly:          sortedClone(conditions.volumeOnlyNamespaces),
+		FailedClosed:        sortedClone(conditions.failedClosedNamespaces),
+		SyncerPending:       sortedClone(conditions.syncerPendingNamespaces),
+		BackedOff:           sortedClone(conditions.backedOffNamespaces),
+	}
+}
+
+// deepCopy returns a copy that doesn't share any slices with the original.
+func (c CategorizedNamespaces) deepCopy() CategorizedNamespaces {
+	return CategorizedNamespaces{
+		Customer:            slices.Clone(c.Customer),
+		OpenShift:           slices.Clone(c.OpenShift),
+		RunLevelZero:        slices.Clone(c.RunLevelZero),
+		DisabledSyncer:      slices.Clone(c.DisabledSyncer),
+		AddOn:               slices.Clone(c.AddOn),
+		Inconclusive:        slices.Clone(c.Inconclusive),
+		UserSCC:             slices.Clone(c.UserSCC),
+		UserSCCInconclusive: slices.Clone(c.UserSCCInconclusive),
+		EnforcedRegression:  slices.Clone(c.EnforcedRegression),
+		OptedOut:            slices.Clone(c.OptedOut),
+		Misconfigured:       slices.Clone(c.Misconfigured),
+		VolumeOnly:          slices.Clone(c.VolumeOnly),
+		FailedClosed:        slices.Clone(c.FailedClosed),
+		SyncerPending:       slices.Clone(c.SyncerPending),
+		BackedOff:           slices.Clone(c.BackedOff),
+	}
+}
+
+// CategoryCounts holds the number of namespaces of every category. Unlike
+// CategorizedNamespaces, it doesn't contain any namespace names, so that it can
+// be collected by telemetry. Fields are only ever added.
+type CategoryCounts struct {
+	Customer            int `json:"customer"`
+	OpenShift           int `json:"openshift"`
+	RunLevelZero        int `json:"runLevelZero"`
+	DisabledSyncer      int `json:"disabledSyncer"`
+	AddOn               int `json:"addOn"`
+	Inconclusive        int `json:"inconclusive"`
+	UserSCC             int `json:"userSCC"`
+	UserSCCInconclusive int `json:"userSCCInconclusive"`
+	EnforcedRegression  int `json:"enforcedRegression"`
+	OptedOut            int `json:"optedOut"`
+	// Clean is the number of namespaces without violations, at any level.
+	Clean         int `json:"clean"`
+	Misconfigured int `json:"misconfigured"`
+	VolumeOnly    int `json:"volumeOnly"`
+	FailedClosed  int `json:"failedClosed"`
+	SyncerPending int `json:"syncerPending"`
+	BackedOff     int `json:"backedOff"`
+}
+
+func newCategoryCounts(conditions *podSecurityOperatorConditions) CategoryCounts {
+	clean := 0
+	for _, count := range conditions.cleanCounts {
+		clean += count
+	}
+
+	return CategoryCounts{
+		Customer:            len(conditions.violatingCustomerNamespaces),
+		OpenShift:           len(conditions.violatingOpenShiftNamespaces),
+		RunLevelZero:        len(conditions.violatingRunLevelZeroNamespaces),
+		DisabledSyncer:      len(conditions.violatingDisabledSyncerNamespaces),
+		AddOn:               len(conditions.violatingAddOnNamespaces),
+		Inconclusive:        len(conditions.inconclusiveNamespaces),
+		UserSCC:             len(conditions.userSCCViolatingNamespaces),
+		UserSCCInconclusive: len(conditions.userSCCInconclusiveNamespaces),
+		EnforcedRegression:  len(conditions.regressedEnforcingNamespaces),
+		OptedOut:            len(conditions.optedOutNamespaces),
+		Clean:               clean,
+		Misconfigured:       len(conditions.misconfiguredNamespaces),
+		VolumeOnly:          len(conditions.volumeOnlyNamespaces),
+		FailedClosed:        len(conditions.failedClosedNamespaces),
+		SyncerPending:       len(conditions.syncerPendingNamespaces),
+		BackedOff:           len(conditions.backedOffNamespaces),
+	}
+}
+
+// keysAndValues returns the counts as structured logging key-value pairs,
+// keyed like their JSON fields.
+func (c CategoryCounts) keysAndValues() []interface{} {
+	return []interface{}{
+		"customer", c.Customer,
+		"openshift", c.OpenShift,
+		"runLevelZero", c.RunLevelZero,
+		"disabledSyncer", c.DisabledSyncer,
+		"addOn", c.AddOn,
+		"inconclusive", c.Inconclusive,
+		"userSCC", c.UserSCC,
+		"userSCCInconclusive", c.UserSCCInconclusive,
+		"enforcedRegression", c.EnforcedRegression,
+		"optedOut", c.OptedOut,
+		"clean", c.Clean,
+		"misconfigured", c.Misconfigured,
+		"volumeOnly", c.VolumeOnly,
+		"failedClosed", c.FailedClosed,
+		"syncerPending", c.SyncerPending,
+		"backedOff", c.BackedOff,
+	}
+}
+
+func sortedClone(namespaces []string) []string {
+	sorted := slices.Clone(namespaces)
+	slices.Sort(sorted)
+	return sorted
+}
+
+// writeResults applies the categorized namespaces to the results ConfigMap,
+// unless they didn't change since the last write.
+func (c *PodSecurityReadinessController) writeResults(ctx context.Context, conditions *podSecurityOperatorConditions) error {
+	results, err := json.Marshal(newCategorizedNamespaces(conditions))
+	if err != nil {
+		return err
+	}
+	if string(results) == c.lastWrittenResults {
+		return nil
+	}
+
+	configMap := applyconfiguration.ConfigMap(c.config.Results.ConfigMapName, c.config.Results.ConfigMapNamespace).
+		WithData(map[string]string{
+			resultsKey: string(results),
+		})
+	_, err = c.kubeClient.CoreV1().
+		ConfigMaps(c.config.Results.ConfigMapNamespace).
+		Apply(ctx, configMap, metav1.ApplyOptions{
+			FieldManager: resultsFieldManager,
+			Force:        true,
+		})
+	// Warnings about the ConfigMap must not be attributed to a namespace.
+	c.warningsHandler.PopAll()
+	if err != nil {
+		return err
+	}
+
+	c.lastWrittenResults = string(results)
+	return nil
+}
+
+// writeArtifact writes the categorized namespaces as JSON to the artifact
+// path, for support tooling like must-gather. The file is replaced atomically,
+// so that readers never see a partial write.
+func (c *PodSecurityReadinessController) writeArtifact(conditions *podSecurityOperatorConditions) error {
+	results, err := json.MarshalIndent(newCategorizedNamespaces(conditions), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.config.Results.ArtifactPath), "."+filepath.Base(c.config.Results.ArtifactPath)+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(results); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), c.config.Results.ArtifactPath)
+}
@@ -0,0 +1,214 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestWriteResults(t *testing.T) {
+	var written []map[string]string
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("patch", "configmaps", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		patch := action.(clienttesting.PatchActionImpl)
+		if patch.GetNamespace() != "operator-ns" || patch.GetName() != "results" {
+			t.Errorf("unexpected ConfigMap %s/%s", patch.GetNamespace(), patch.GetName())
+		}
+		if patch.PatchOptions.FieldManager != resultsFieldManager {
+			t.Errorf("expected field manager %q, got %q", resultsFieldManager, patch.PatchOptions.FieldManager)
+		}
+
+		configMap := &corev1.ConfigMap{}
+		if err := json.Unmarshal(patch.GetPatch(), configMap); err != nil {
+			return true, nil, err
+		}
+		written = append(written, configMap.Data)
+
+		return true, configMap, nil
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		warningsHandler: &warningsHandler{},
+		config:          Config{Results: ResultsConfig{ConfigMapNamespace: "operator-ns", ConfigMapName: "results"}},
+	}
+
+	conditions := &podSecurityOperatorConditions{
+		violatingCustomerNamespaces: []string{"customer-b", "customer-a"},
+		inconclusiveNamespaces:      []string{"inconclusive"},
+	}
+
+	t.Run("writes the results", func(t *testing.T) {
+		if err := controller.writeResults(context.TODO(), conditions); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(written) != 1 {
+			t.Fatalf("expected a single write, got %d", len(written))
+		}
+
+		expected := `{"customer":["customer-a","customer-b"],"inconclusive":["inconclusive"]}`
+		if written[0][resultsKey] != expected {
+			t.Errorf("expected results %s, got %s", expected, written[0][resultsKey])
+		}
+	})
+
+	t.Run("skips unchanged results", func(t *testing.T) {
+		reordered := conditions.deepCopy()
+		reordered.violatingCustomerNamespaces = []string{"customer-a", "customer-b"}
+
+		if err := controller.writeResults(context.TODO(), &reordered); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(written) != 1 {
+			t.Errorf("expected no further write, got %d writes", len(written))
+		}
+	})
+
+	t.Run("writes changed results", func(t *testing.T) {
+		changed := conditions.deepCopy()
+		changed.inconclusiveNamespaces = nil
+
+		if err := controller.writeResults(context.TODO(), &changed); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(written) != 2 {
+			t.Fatalf("expected a second write, got %d writes", len(written))
+		}
+
+		expected := `{"customer":["customer-a","customer-b"]}`
+		if written[1][resultsKey] != expected {
+			t.Errorf("expected results %s, got %s", expected, written[1][resultsKey])
+		}
+	})
+}
+
+func TestCountsCondition(t *testing.T) {
+	conditions := &podSecurityOperatorConditions{
+		violatingCustomerNamespaces:  []string{"customer-a", "customer-b"},
+		violatingOpenShiftNamespaces: []string{"openshift-a"},
+		inconclusiveNamespaces:       []string{"undetermined-ns"},
+		userSCCViolatingNamespaces:   []string{"customer-a"},
+		optedOutNamespaces:           []string{"accepted-risk-ns"},
+		cleanCounts:                  map[string]int{"restricted": 3, "baseline": 2},
+		summaries:                    true,
+	}
+
+	status := &operatorv1.OperatorStatus{}
+	for _, fn := range conditions.toConditionFuncs() {
+		if err := fn(status); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityCountsType)
+	if condition == nil {
+		t.Fatalf("expected condition %s to be set", PodSecurityCountsType)
+	}
+
+	expected := `{"customer":2,"openshift":1,"runLevelZero":0,"disabledSyncer":0,"addOn":0,"inconclusive":1,"userSCC":1,"userSCCInconclusive":0,"enforcedRegression":0,"optedOut":1,"clean":5,"misconfigured":0,"volumeOnly":0,"failedClosed":0,"syncerPending":0,"backedOff":0}`
+	if condition.Message != expected {
+		t.Errorf("expected message %s, got %s", expected, condition.Message)
+	}
+	for _, ns := range []string{"customer-a", "customer-b", "openshift-a", "undetermined-ns", "accepted-risk-ns"} {
+		if strings.Contains(condition.Message, ns) {
+			t.Errorf("expected no namespace names in the message, found %q", ns)
+		}
+	}
+
+	var counts CategoryCounts
+	if err := json.Unmarshal([]byte(condition.Message), &counts); err != nil {
+		t.Fatalf("expected a parseable message: %v", err)
+	}
+	if counts.Customer != 2 || counts.Clean != 5 {
+		t.Errorf("unexpected counts %+v", counts)
+	}
+}
+
+func TestWriteArtifact(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "pod-security-readiness.json")
+
+	controller := &PodSecurityReadinessController{config: Config{Results: ResultsConfig{ArtifactPath: path}}}
+
+	conditions := &podSecurityOperatorConditions{
+		violatingCustomerNamespaces: []string{"customer-b", "customer-a"},
+	}
+	if err := controller.writeArtifact(conditions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results CategorizedNamespaces
+	if err := json.Unmarshal(written, &results); err != nil {
+		t.Fatalf("expected the artifact to be JSON: %v", err)
+	}
+	if expected := (CategorizedNamespaces{Customer: []string{"customer-a", "customer-b"}}); !reflect.DeepEqual(results, expected) {
+		t.Errorf("expected results %+v, got %+v", expected, results)
+	}
+
+	// A reader of the previous artifact keeps reading it in full, the new one
+	// is renamed into its place.
+	previous, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer previous.Close()
+
+	conditions.inconclusiveNamespaces = []string{"undetermined"}
+	if err := controller.writeArtifact(conditions); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	previousContent, err := io.ReadAll(previous)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(previousContent) != string(written) {
+		t.Errorf("expected the previous artifact to be left intact, got %s", previousContent)
+	}
+
+	written, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results = CategorizedNamespaces{}
+	if err := json.Unmarshal(written, &results); err != nil {
+		t.Fatalf("expected the artifact to be JSON: %v", err)
+	}
+	if expected := []string{"undetermined"}; !reflect.DeepEqual(results.Inconclusive, expected) {
+		t.Errorf("expected inconclusive namespaces %v, got %v", expected, results.Inconclusive)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected no temporary files to be left behind, got %v", entries)
+	}
+}
+
+func TestWriteArtifactFailure(t *testing.T) {
+	controller := &PodSecurityReadinessController{
+		config: Config{Results: ResultsConfig{ArtifactPath: filepath.Join(t.TempDir(), "missing", "pod-security-readiness.json")}},
+	}
+
+	if err := controller.writeArtifact(&podSecurityOperatorConditions{}); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
@@ -0,0 +1,77 @@
+package podsecurityreadinesscontroller
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+)
+
+// ResyncTrigger forces the controller to re-evaluate all namespaces without
+// waiting for the resync interval, e.g. after a bulk remediation. Cached and
+// incrementally carried results are discarded for that sync. It is passed to
+// the controller in Config.ResyncTrigger.
+type ResyncTrigger struct {
+	lock    sync.Mutex
+	queue   workqueue.RateLimitingInterface
+	pending bool
+}
+
+// NewResyncTrigger returns a trigger that isn't bound to a controller yet.
+func NewResyncTrigger() *ResyncTrigger {
+	return &ResyncTrigger{}
+}
+
+// TriggerResync enqueues a full re-evaluation. Triggers coalesce with each
+// other and with the periodic resync: while a sync is queued, no further one
+// is added, and while a sync is in flight, exactly one more runs after it. A
+// trigger before the controller is started takes effect with its first sync.
+func (t *ResyncTrigger) TriggerResync() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.pending = true
+	if t.queue != nil {
+		t.queue.Add(factory.DefaultQueueKey)
+	}
+}
+
+// bind makes the trigger enqueue syncs of the controller with the queue.
+func (t *ResyncTrigger) bind(queue workqueue.RateLimitingInterface) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.queue != nil {
+		return fmt.Errorf("the resync trigger is already bound to a controller")
+	}
+
+	t.queue = queue
+	return nil
+}
+
+// consume checks whether a full re-evaluation was triggered since the last
+// call.
+func (t *ResyncTrigger) consume() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	pending := t.pending
+	t.pending = false
+
+	return pending
+}
+
+// discardEvaluationResults drops the results that would otherwise be reused
+// instead of evaluating the namespaces again, and the backoff of namespaces
+// whose evaluation kept failing.
+func (c *PodSecurityReadinessController) discardEvaluationResults() {
+	c.namespaceErrors = nil
+	if c.evaluationCache != nil {
+		c.evaluationCache.reset()
+	}
+	if c.changeTracker != nil {
+		c.changeTracker.reset()
+	}
+}
@@ -0,0 +1,197 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+)
+
+func TestResyncTrigger(t *testing.T) {
+	newController := func(trigger *ResyncTrigger) error {
+		_, _, err := NewPodSecurityReadinessController(
+			&rest.Config{Host: "https://localhost:6443"},
+			v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			events.NewInMemoryRecorder("test", clock.RealClock{}),
+			NewWarningsHandler(),
+			Config{ResyncTrigger: trigger},
+		)
+		return err
+	}
+
+	t.Run("enqueues a sync", func(t *testing.T) {
+		trigger := NewResyncTrigger()
+		if err := newController(trigger); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		trigger.TriggerResync()
+		if trigger.queue.Len() != 1 {
+			t.Fatalf("expected a queued sync, got %d", trigger.queue.Len())
+		}
+		if !trigger.consume() {
+			t.Error("expected a full re-evaluation to be pending")
+		}
+		if trigger.consume() {
+			t.Error("expected the full re-evaluation to be consumed")
+		}
+	})
+
+	t.Run("coalesces with a queued sync", func(t *testing.T) {
+		trigger := NewResyncTrigger()
+		if err := newController(trigger); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		trigger.TriggerResync()
+		trigger.TriggerResync()
+		if trigger.queue.Len() != 1 {
+			t.Errorf("expected a single queued sync, got %d", trigger.queue.Len())
+		}
+	})
+
+	t.Run("coalesces with an in-flight sync", func(t *testing.T) {
+		trigger := NewResyncTrigger()
+		if err := newController(trigger); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		trigger.TriggerResync()
+		key, _ := trigger.queue.Get()
+		trigger.TriggerResync()
+		trigger.TriggerResync()
+		if trigger.queue.Len() != 0 {
+			t.Errorf("expected no sync to be queued while one is in flight, got %d", trigger.queue.Len())
+		}
+
+		trigger.queue.Done(key)
+		if trigger.queue.Len() != 1 {
+			t.Errorf("expected a single sync to follow the in-flight one, got %d", trigger.queue.Len())
+		}
+	})
+
+	t.Run("before the controller is built", func(t *testing.T) {
+		trigger := NewResyncTrigger()
+		trigger.TriggerResync()
+		if err := newController(trigger); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if !trigger.consume() {
+			t.Error("expected the full re-evaluation to be pending for the first sync")
+		}
+	})
+
+	t.Run("shared between controllers", func(t *testing.T) {
+		trigger := NewResyncTrigger()
+		if err := newController(trigger); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := newController(trigger); err == nil {
+			t.Error("expected an error for a trigger bound to another controller")
+		}
+	})
+}
+
+func TestForcedResync(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	}
+
+	handler := &warningsHandler{}
+	violating := sets.New(namespace.Name)
+	var evaluated []string
+	fakeClient := fake.NewSimpleClientset(namespace)
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		name := action.(clienttesting.PatchAction).GetName()
+		evaluated = append(evaluated, name)
+		if violating.Has(name) {
+			handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"restricted:latest\"", name))
+		}
+		return true, nil, nil
+	})
+
+	trigger := NewResyncTrigger()
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: handler,
+		dryRunVerified:  true,
+		changeTracker:   newChangeTracker(),
+		config:          Config{ResyncTrigger: trigger},
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	for _, step := range []struct {
+		name      string
+		remediate bool
+		trigger   bool
+
+		expectedEvaluated []string
+		expectedViolating []string
+	}{
+		{
+			name:              "initial sync",
+			expectedEvaluated: []string{namespace.Name},
+			expectedViolating: []string{namespace.Name},
+		},
+		{
+			// The remediation isn't observed by the change tracker.
+			name:              "remediated without an event",
+			remediate:         true,
+			expectedViolating: []string{namespace.Name},
+		},
+		{
+			name:              "forced resync",
+			trigger:           true,
+			expectedEvaluated: []string{namespace.Name},
+		},
+		{
+			name: "carried forward after the forced resync",
+		},
+	} {
+		t.Run(step.name, func(t *testing.T) {
+			evaluated = nil
+			if step.remediate {
+				violating.Delete(namespace.Name)
+			}
+			if step.trigger {
+				trigger.TriggerResync()
+			}
+
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(evaluated, step.expectedEvaluated) {
+				t.Errorf("expected evaluated namespaces %v, got %v", step.expectedEvaluated, evaluated)
+			}
+			conditions := controller.snapshot()
+			if !reflect.DeepEqual(conditions.violatingCustomerNamespaces, step.expectedViolating) {
+				t.Errorf("expected violating namespaces %v, got %v", step.expectedViolating, conditions.violatingCustomerNamespaces)
+			}
+		})
+	}
+}
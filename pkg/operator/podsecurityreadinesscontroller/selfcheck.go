@@ -0,0 +1,112 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applyconfiguration "k8s.io/client-go/applyconfigurations/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// selfCheckNamespace is expected to exist on every cluster.
+	selfCheckNamespace = "default"
+
+	// heartbeatPodName is the name of the pod that is created with a dry run
+	// to verify that warnings are captured. It is never persisted.
+	heartbeatPodName = "pod-security-readiness-heartbeat"
+	// heartbeatNodeSelector is deprecated, so the apiserver always warns
+	// about it, independently of the pod security configuration.
+	heartbeatNodeSelector = "beta.kubernetes.io/os"
+)
+
+// verifyDryRunApply performs a dry-run Apply on a known namespace to make sure
+// the controller is allowed to evaluate namespaces at all. A Forbidden error
+// is returned until it persisted for longer than syncFailureTolerance, so
+// that the sync is retried, and recorded in the conditions afterwards. Any
+// other error is returned.
+//
+// The Apply doesn't set any labels, so it can't produce pod security warnings
+// that would be attributed to the next evaluated namespace.
+func (c *PodSecurityReadinessController) verifyDryRunApply(ctx context.Context, conditions *podSecurityOperatorConditions) error {
+	_, err := c.namespacesClient().
+		Apply(ctx, applyconfiguration.Namespace(selfCheckNamespace), metav1.ApplyOptions{
+			DryRun:       []string{metav1.DryRunAll},
+			FieldManager: c.config.Client.fieldManager(),
+		})
+	if apierrors.IsForbidden(err) {
+		klog.ErrorS(err, "Dry-run Apply on namespaces is forbidden, pod security violations can't be evaluated")
+
+		if !c.failingBeyondTolerance(&c.dryRunFailingSince) {
+			return err
+		}
+		conditions.dryRunFailure = err.Error()
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	c.dryRunVerified = true
+	c.dryRunFailingSince = time.Time{}
+	return nil
+}
+
+// verifyWarningsCaptured creates a pod with a deprecated node selector with a
+// dry run and records in the conditions whether the warning the apiserver
+// returns for it reached the warnings handler. Violations are detected solely
+// through warnings, so losing them would make every namespace look clean.
+//
+// A synthetic namespace can't be used for this: pod security admission only
+// warns about existing pods when the enforce label of an existing namespace
+// changes. If the heartbeat can't be performed, an event is recorded and the
+// previous outcome is kept.
+func (c *PodSecurityReadinessController) verifyWarningsCaptured(ctx context.Context, conditions *podSecurityOperatorConditions) {
+	// Don't attribute stale warnings to the heartbeat.
+	c.warningsHandler.PopAll()
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      heartbeatPodName,
+			Namespace: selfCheckNamespace,
+		},
+		Spec: corev1.PodSpec{
+			NodeSelector: map[string]string{
+				heartbeatNodeSelector: "linux",
+			},
+			Containers: []corev1.Container{{
+				Name:  "heartbeat",
+				Image: "heartbeat",
+			}},
+		},
+	}
+	_, err := c.kubeClient.CoreV1().
+		Pods(selfCheckNamespace).
+		Create(ctx, pod, metav1.CreateOptions{
+			DryRun:       []string{metav1.DryRunAll},
+			FieldManager: c.config.Client.fieldManager(),
+		})
+	warnings := c.warningsHandler.PopAll()
+	if err != nil {
+		klog.ErrorS(err, "Failed to verify that warnings are captured")
+		if c.recorder != nil {
+			c.recorder.Warningf("PodSecurityWarningHeartbeatFailed", "Failed to verify that warnings are captured: %v", err)
+		}
+
+		conditions.warningHeartbeatFailed = true
+		return
+	}
+
+	conditions.warningHeartbeat = true
+	conditions.warningsDropped = !slices.ContainsFunc(warnings, func(warning string) bool {
+		return strings.Contains(warning, heartbeatNodeSelector)
+	})
+	if conditions.warningsDropped {
+		klog.ErrorS(nil, "No warning was captured for the heartbeat pod, pod security violations may go unnoticed")
+	}
+}
@@ -0,0 +1,279 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+)
+
+func TestDryRunSelfCheck(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		forbidden bool
+
+		expectedVerified      bool
+		expectedStatus        operatorv1.ConditionStatus
+		expectedNamespaceList bool
+	}{
+		{
+			name:                  "dry-run Apply allowed",
+			expectedVerified:      true,
+			expectedStatus:        operatorv1.ConditionFalse,
+			expectedNamespaceList: true,
+		},
+		{
+			name:                  "dry-run Apply forbidden",
+			forbidden:             true,
+			expectedVerified:      false,
+			expectedStatus:        operatorv1.ConditionTrue,
+			expectedNamespaceList: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var appliedNamespaces []string
+			fakeClient := fake.NewSimpleClientset()
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				appliedNamespaces = append(appliedNamespaces, action.(clienttesting.PatchAction).GetName())
+				if tt.forbidden {
+					return true, nil, apierrors.NewForbidden(corev1.Resource("namespaces"), selfCheckNamespace, fmt.Errorf("not allowed"))
+				}
+				return true, nil, nil
+			})
+
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  operatorClient,
+				clock:           clock.RealClock{},
+				warningsHandler: &warningsHandler{},
+			}
+			if tt.forbidden {
+				// The failure has persisted for longer than tolerated.
+				controller.dryRunFailingSince = time.Now().Add(-syncFailureTolerance)
+			}
+
+			syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(appliedNamespaces) != 1 || appliedNamespaces[0] != selfCheckNamespace {
+				t.Errorf("expected a single dry-run Apply on %q, got %v", selfCheckNamespace, appliedNamespaces)
+			}
+			if controller.dryRunVerified != tt.expectedVerified {
+				t.Errorf("expected verified %v, got %v", tt.expectedVerified, controller.dryRunVerified)
+			}
+
+			listedNamespaces := false
+			for _, action := range fakeClient.Actions() {
+				if action.Matches("list", "namespaces") {
+					listedNamespaces = true
+				}
+			}
+			if listedNamespaces != tt.expectedNamespaceList {
+				t.Errorf("expected namespaces to be listed %v, got %v", tt.expectedNamespaceList, listedNamespaces)
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityDryRunDegradedType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", PodSecurityDryRunDegradedType)
+			}
+			if condition.Status != tt.expectedStatus {
+				t.Errorf("expected condition status %s, got %s", tt.expectedStatus, condition.Status)
+			}
+
+			// The self-check isn't repeated once it succeeded.
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			expectedApplies := 2
+			if tt.expectedVerified {
+				expectedApplies = 1
+			}
+			if len(appliedNamespaces) != expectedApplies {
+				t.Errorf("expected %d dry-run Applies, got %d", expectedApplies, len(appliedNamespaces))
+			}
+		})
+	}
+}
+
+func TestDryRunSelfCheckTolerance(t *testing.T) {
+	forbidden := true
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if forbidden {
+			return true, nil, apierrors.NewForbidden(corev1.Resource("namespaces"), selfCheckNamespace, fmt.Errorf("not allowed"))
+		}
+		return true, nil, nil
+	})
+
+	fakeClock := clocktesting.NewFakePassiveClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  operatorClient,
+		clock:           fakeClock,
+		warningsHandler: &warningsHandler{},
+	}
+
+	expectCondition := func(t *testing.T, expected *operatorv1.ConditionStatus) {
+		t.Helper()
+
+		_, status, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityDryRunDegradedType)
+		switch {
+		case expected == nil && condition != nil:
+			t.Errorf("expected condition %s not to be set, got %v", PodSecurityDryRunDegradedType, condition)
+		case expected != nil && condition == nil:
+			t.Errorf("expected condition %s to be set", PodSecurityDryRunDegradedType)
+		case expected != nil && condition.Status != *expected:
+			t.Errorf("expected condition status %s, got %s", *expected, condition.Status)
+		}
+	}
+
+	// The failure is retried without being reported while it is tolerated.
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); !apierrors.IsForbidden(err) {
+		t.Fatalf("expected a Forbidden error, got %v", err)
+	}
+	expectCondition(t, nil)
+
+	fakeClock.SetTime(fakeClock.Now().Add(syncFailureTolerance))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectCondition(t, ptr.To(operatorv1.ConditionTrue))
+
+	// A later failure is tolerated again.
+	forbidden = false
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectCondition(t, ptr.To(operatorv1.ConditionFalse))
+	if !controller.dryRunFailingSince.IsZero() {
+		t.Errorf("expected the failure to be reset, failing since %v", controller.dryRunFailingSince)
+	}
+}
+
+func TestWarningHeartbeat(t *testing.T) {
+	for _, tt := range []struct {
+		name              string
+		previouslyDropped bool
+		brokenHandler     bool
+		unrelatedWarning  bool
+		failure           error
+
+		expectedStatus operatorv1.ConditionStatus
+		expectedEvents int
+	}{
+		{
+			name:              "warnings captured",
+			previouslyDropped: true,
+			expectedStatus:    operatorv1.ConditionFalse,
+		},
+		{
+			name:           "warnings dropped",
+			brokenHandler:  true,
+			expectedStatus: operatorv1.ConditionTrue,
+		},
+		{
+			name:             "only unrelated warnings captured",
+			brokenHandler:    true,
+			unrelatedWarning: true,
+			expectedStatus:   operatorv1.ConditionTrue,
+		},
+		{
+			name:              "heartbeat failed",
+			previouslyDropped: true,
+			failure:           fmt.Errorf("connection refused"),
+			// The previous outcome is kept.
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedEvents: 1,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &warningsHandler{}
+			fakeClient := fake.NewSimpleClientset()
+			fakeClient.PrependReactor("create", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				createAction := action.(clienttesting.CreateAction)
+				if createAction.GetNamespace() != selfCheckNamespace {
+					return true, nil, fmt.Errorf("unexpected pod creation in namespace %s", createAction.GetNamespace())
+				}
+				pod := createAction.GetObject().(*corev1.Pod)
+				if _, ok := pod.Spec.NodeSelector[heartbeatNodeSelector]; !ok {
+					return true, nil, fmt.Errorf("expected the deprecated node selector %s, got %v", heartbeatNodeSelector, pod.Spec.NodeSelector)
+				}
+				if tt.failure != nil {
+					return true, nil, tt.failure
+				}
+				if tt.unrelatedWarning {
+					handler.HandleWarningHeader(299, "", "unrelated warning")
+				}
+				if !tt.brokenHandler {
+					// The warning the apiserver returns for the deprecated node selector.
+					handler.HandleWarningHeader(299, "", `spec.nodeSelector[beta.kubernetes.io/os]: deprecated since v1.14; use "kubernetes.io/os" instead`)
+				}
+				return true, pod, nil
+			})
+
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{
+				Conditions: []operatorv1.OperatorCondition{makeWarningsDegradedCondition(tt.previouslyDropped)},
+			}, nil)
+			recorder := events.NewInMemoryRecorder("test", clock.RealClock{})
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				operatorClient:  operatorClient,
+				recorder:        recorder,
+				clock:           clock.RealClock{},
+				warningsHandler: handler,
+				dryRunVerified:  true,
+				config:          Config{Evaluation: EvaluationConfig{WarningHeartbeat: true}},
+			}
+
+			syncCtx := factory.NewSyncContext("test", recorder)
+			if err := controller.sync(context.TODO(), syncCtx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if len(handler.warnings) != 0 {
+				t.Errorf("expected the heartbeat warnings to be consumed, got %v", handler.warnings)
+			}
+			if len(recorder.Events()) != tt.expectedEvents {
+				t.Errorf("expected %d events, got %v", tt.expectedEvents, recorder.Events())
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, PodSecurityWarningsDegradedType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", PodSecurityWarningsDegradedType)
+			}
+			if condition.Status != tt.expectedStatus {
+				t.Errorf("expected condition status %s, got %s", tt.expectedStatus, condition.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/utils/clock"
+)
+
+func TestSimulateEnforceFlip(t *testing.T) {
+	privileged := true
+	namespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: syncerManagedFields(),
+			},
+		}
+	}
+	kubeClient := fake.NewSimpleClientset(
+		namespace("violating"),
+		namespace("clean"),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "user-pod",
+				Namespace: "violating",
+				Annotations: map[string]string{
+					securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+				},
+			},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name:            "privileged",
+					SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+				}},
+			},
+		},
+	)
+	warnings := map[string][]string{
+		"violating": {"existing pods in namespace \"violating\" violate the new PodSecurity enforce level \"restricted:latest\""},
+	}
+
+	conditions, err := simulateEnforceFlip(context.TODO(), kubeClient, warnings, Config{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, conditionType := range []string{PodSecurityCustomerType, PodSecurityUserSCCType} {
+		condition := v1helpers.FindOperatorCondition(conditions, conditionType)
+		if condition == nil {
+			t.Fatalf("expected condition %s to be reported", conditionType)
+		}
+		if condition.Status != operatorv1.ConditionTrue || !strings.Contains(condition.Message, "[violating]") {
+			t.Errorf("expected condition %s to report the violating namespace, got %v", conditionType, condition)
+		}
+	}
+	if condition := v1helpers.FindOperatorCondition(conditions, PodSecurityInconclusiveType); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected no inconclusive namespaces, got %v", condition)
+	}
+
+	t.Run("config", func(t *testing.T) {
+		conditions, err := simulateEnforceFlip(context.TODO(), fake.NewSimpleClientset(namespace("clean")), nil, Config{Conditions: ConditionsConfig{Terse: true}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if condition := v1helpers.FindOperatorCondition(conditions, PodSecurityCustomerType); condition != nil {
+			t.Errorf("expected terse conditions to leave out the customer condition, got %v", condition)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		if _, err := simulateEnforceFlip(context.TODO(), fake.NewSimpleClientset(), nil, Config{Levels: LevelsConfig{Maximum: "unknown"}}); err == nil {
+			t.Error("expected the config to be validated")
+		}
+	})
+}
+
+// simulateEnforceFlip runs a complete evaluation of the namespaces and pods in
+// the fake clientset and returns the conditions it would report.
+//
+// The fake clientset doesn't run pod security admission, so the warnings a
+// dry-run Apply of the enforce label would return are passed in per namespace,
+// e.g.
+//
+//	existing pods in namespace "my-ns" violate the new PodSecurity enforce level "restricted:latest"
+//
+// Namespaces without warnings are clean. The pods are still evaluated for user
+// SCC violations. The levels of the namespaces are only considered if the
+// syncer owns them, see syncerManagedFields.
+//
+// A reactor for namespace patches is prepended to the clientset.
+func simulateEnforceFlip(
+	ctx context.Context,
+	kubeClient *fake.Clientset,
+	warnings map[string][]string,
+	config Config,
+) ([]operatorv1.OperatorCondition, error) {
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	recorder := events.NewInMemoryRecorder("pod-security-readiness-simulation", clock.RealClock{})
+	handler := &warningsHandler{}
+
+	c, err := newPodSecurityReadinessController(operatorClient, recorder, handler, config)
+	if err != nil {
+		return nil, err
+	}
+	c.kubeClient = kubeClient
+
+	kubeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		patch, ok := action.(clienttesting.PatchAction)
+		if !ok {
+			return false, nil, nil
+		}
+		// Only setting the enforce level warns about existing pods.
+		if strings.Contains(string(patch.GetPatch()), psapi.EnforceLevelLabel) {
+			for _, warning := range warnings[patch.GetName()] {
+				handler.HandleWarningHeader(299, "", warning)
+			}
+		}
+		return true, nil, nil
+	})
+
+	if err := c.sync(ctx, factory.NewSyncContext("pod-security-readiness-simulation", recorder)); err != nil {
+		return nil, fmt.Errorf("simulated sync failed: %w", err)
+	}
+
+	_, status, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		return nil, err
+	}
+
+	return status.Conditions, nil
+}
+
+// syncerManagedFields returns the managed fields of a namespace whose pod
+// security labels and annotation are owned by the label syncer.
+func syncerManagedFields() []metav1.ManagedFieldsEntry {
+	return []metav1.ManagedFieldsEntry{
+		{
+			Manager:   defaultSyncerControllerName,
+			Operation: metav1.ManagedFieldsOperationApply,
+			FieldsV1: &metav1.FieldsV1{
+				Raw: []byte(fmt.Sprintf(`{"f:metadata":{"f:annotations":{"f:%s":{}},"f:labels":{"f:%s":{},"f:%s":{},"f:%s":{}}}}`,
+					securityv1.MinimallySufficientPodSecurityStandard,
+					psapi.WarnLevelLabel,
+					psapi.AuditLevelLabel,
+					psapi.EnforceLevelLabel,
+				)),
+			},
+		},
+	}
+}
@@ -0,0 +1,86 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	applyconfiguration "k8s.io/client-go/applyconfigurations/core/v1"
+)
+
+// The names of the spans, see Config.Tracer.
+const (
+	syncSpanName              = "PodSecurityReadinessSync"
+	evaluateNamespaceSpanName = "EvaluateNamespace"
+	applyNamespaceSpanName    = "ApplyNamespace"
+	listPodsSpanName          = "ListPods"
+
+	namespaceAttribute = "namespace"
+)
+
+// noopTracer starts non-recording spans while tracing is disabled.
+var noopTracer = noop.NewTracerProvider().Tracer("")
+
+// startSpan starts a span with the tracer of the controller, which is a
+// non-recording one unless tracing is enabled.
+func (c *PodSecurityReadinessController) startSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	tracer := c.config.Tracer
+	if tracer == nil {
+		tracer = noopTracer
+	}
+
+	return tracer.Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// endSpan ends the span, marking it as failed if there is an error.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracingNamespaceClient adds a span for every Apply, which is the dry run
+// that pod security admission evaluates the existing pods in.
+type tracingNamespaceClient struct {
+	namespaceClient
+	tracer trace.Tracer
+}
+
+func (t tracingNamespaceClient) Apply(ctx context.Context, namespace *applyconfiguration.NamespaceApplyConfiguration, opts metav1.ApplyOptions) (*corev1.Namespace, error) {
+	var name string
+	if namespace.Name != nil {
+		name = *namespace.Name
+	}
+
+	ctx, span := t.tracer.Start(ctx, applyNamespaceSpanName, trace.WithAttributes(
+		attribute.String(namespaceAttribute, name),
+		attribute.Bool("dryRun", len(opts.DryRun) > 0),
+	))
+	applied, err := t.namespaceClient.Apply(ctx, namespace, opts)
+	endSpan(span, err)
+
+	return applied, err
+}
+
+// tracingPodClient adds a span for every pod list.
+type tracingPodClient struct {
+	podClient
+	tracer trace.Tracer
+}
+
+func (t tracingPodClient) List(ctx context.Context, namespace string, opts metav1.ListOptions) (*corev1.PodList, error) {
+	ctx, span := t.tracer.Start(ctx, listPodsSpanName, trace.WithAttributes(attribute.String(namespaceAttribute, namespace)))
+	pods, err := t.podClient.List(ctx, namespace, opts)
+	if err == nil {
+		span.SetAttributes(attribute.Int("pods", len(pods.Items)))
+	}
+	endSpan(span, err)
+
+	return pods, err
+}
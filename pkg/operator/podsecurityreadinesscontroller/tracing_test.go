@@ -0,0 +1,174 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/pod-security-admission/policy"
+	"k8s.io/utils/clock"
+)
+
+// recordingExporter keeps the exported spans in memory.
+type recordingExporter struct {
+	lock  sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *recordingExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func spanNamespace(span sdktrace.ReadOnlySpan) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == namespaceAttribute {
+			return kv.Value.AsString()
+		}
+	}
+	return ""
+}
+
+func hasAttribute(span sdktrace.ReadOnlySpan, expected attribute.KeyValue) bool {
+	for _, kv := range span.Attributes() {
+		if kv == expected {
+			return true
+		}
+	}
+	return false
+}
+
+func TestTracing(t *testing.T) {
+	newNamespace := func(name string) *corev1.Namespace {
+		return &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: name,
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		}
+	}
+
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset(newNamespace("violating-ns"), newNamespace("clean-ns"))
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		if name := action.(clienttesting.PatchAction).GetName(); name == "violating-ns" {
+			handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"restricted:latest\"", name))
+		}
+		return true, nil, nil
+	})
+
+	psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exporter := &recordingExporter{}
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		clock:           clock.RealClock{},
+		warningsHandler: handler,
+		dryRunVerified:  true,
+		psaEvaluator:    psaEvaluator,
+		config:          Config{Tracer: provider.Tracer("test")},
+	}
+
+	syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+	if err := controller.sync(context.TODO(), syncCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var syncSpan sdktrace.ReadOnlySpan
+	evaluations := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range exporter.spans {
+		switch span.Name() {
+		case syncSpanName:
+			if syncSpan != nil {
+				t.Fatal("expected a single sync span")
+			}
+			syncSpan = span
+		case evaluateNamespaceSpanName:
+			evaluations[spanNamespace(span)] = span
+		}
+	}
+	if syncSpan == nil {
+		t.Fatal("expected a sync span")
+	}
+	if syncSpan.Parent().IsValid() {
+		t.Errorf("expected the sync span to be a root span, got parent %v", syncSpan.Parent())
+	}
+
+	for _, namespace := range []string{"violating-ns", "clean-ns"} {
+		evaluation, ok := evaluations[namespace]
+		if !ok {
+			t.Errorf("expected an evaluation span for %s", namespace)
+			continue
+		}
+		if evaluation.Parent().SpanID() != syncSpan.SpanContext().SpanID() {
+			t.Errorf("expected the evaluation span of %s to be a child of the sync span", namespace)
+		}
+
+		violating := namespace == "violating-ns"
+		if !hasAttribute(evaluation, attribute.Bool("violating", violating)) {
+			t.Errorf("expected the evaluation span of %s to be violating=%v, got %v", namespace, violating, evaluation.Attributes())
+		}
+
+		children := map[string]int{}
+		for _, span := range exporter.spans {
+			if span.Parent().SpanID() == evaluation.SpanContext().SpanID() {
+				if spanNamespace(span) != namespace {
+					t.Errorf("expected the %s span to be of namespace %s, got %s", span.Name(), namespace, spanNamespace(span))
+				}
+				children[span.Name()]++
+			}
+		}
+		if children[applyNamespaceSpanName] == 0 {
+			t.Errorf("expected an Apply span for %s, got %v", namespace, children)
+		}
+		// Only the pods of violating namespaces are listed.
+		if (children[listPodsSpanName] > 0) != violating {
+			t.Errorf("expected pod list spans for %s: %v, got %v", namespace, violating, children)
+		}
+	}
+}
+
+func TestTracingDisabled(t *testing.T) {
+	controller := &PodSecurityReadinessController{kubeClient: fake.NewSimpleClientset()}
+
+	_, span := controller.startSpan(context.TODO(), syncSpanName)
+	if span.IsRecording() {
+		t.Error("expected a non-recording span without a tracer")
+	}
+	endSpan(span, fmt.Errorf("failure"))
+
+	if _, ok := controller.namespacesClient().(tracingNamespaceClient); ok {
+		t.Error("expected the namespace client not to be traced without a tracer")
+	}
+	if _, ok := controller.podsClient().(sharingPodClient).podClient.(tracingPodClient); ok {
+		t.Error("expected the pod client not to be traced without a tracer")
+	}
+}
@@ -0,0 +1,89 @@
+package podsecurityreadinesscontroller
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/klog/v2"
+	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+)
+
+// evaluationVersion returns the policy version the pods of the namespace are
+// evaluated against locally, which is the enforce version of the namespace
+// limited to the version of the cluster, see clampVersion.
+func (c *PodSecurityReadinessController) evaluationVersion(ns *corev1.Namespace) psapi.Version {
+	return c.clampVersion(enforceVersionForNamespace(ns))
+}
+
+// clampVersion lowers the latest version and versions newer than the cluster
+// to the version of the cluster, if the checks are newer than the cluster.
+// The apiserver enforces its own version for those, so evaluating them with
+// the newer checks would report violations the apiserver wouldn't.
+func (c *PodSecurityReadinessController) clampVersion(version psapi.Version) psapi.Version {
+	limit := c.versionLimit
+	if limit == (psapi.Version{}) {
+		return version
+	}
+	if version.Latest() || limit.Older(version) {
+		return limit
+	}
+
+	return version
+}
+
+// refreshVersionLimit sets the version limit to the version of the cluster if
+// the checks are newer than it, and clears it otherwise. The previous limit is
+// kept if the version of the cluster can't be determined.
+func (c *PodSecurityReadinessController) refreshVersionLimit() {
+	clusterVersion, err := serverVersion(c.kubeClient.Discovery())
+	if err != nil {
+		klog.V(2).ErrorS(err, "Failed to determine the Kubernetes version of the cluster")
+		return
+	}
+
+	limit := psapi.Version{}
+	if checksVersion := newestCheckVersion(c.config.Pods.policyChecks()); clusterVersion.Older(checksVersion) {
+		limit = clusterVersion
+	}
+	if limit != c.versionLimit && limit != (psapi.Version{}) {
+		klog.Warningf("The pod security checks of version %v are newer than the cluster, evaluating pods against version %v instead of newer versions",
+			newestCheckVersion(c.config.Pods.policyChecks()), limit)
+	}
+	c.versionLimit = limit
+}
+
+// serverVersion returns the Kubernetes version of the apiserver.
+func serverVersion(client discovery.ServerVersionInterface) (psapi.Version, error) {
+	info, err := client.ServerVersion()
+	if err != nil {
+		return psapi.Version{}, err
+	}
+
+	parsed, err := utilversion.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return psapi.Version{}, err
+	}
+	if parsed.Major() == 0 {
+		// Builds without version information report v0.0.0.
+		return psapi.Version{}, fmt.Errorf("unknown server version %q", info.GitVersion)
+	}
+
+	return psapi.MajorMinorVersion(int(parsed.Major()), int(parsed.Minor())), nil
+}
+
+// newestCheckVersion returns the newest version any of the checks changed in.
+func newestCheckVersion(checks []policy.Check) psapi.Version {
+	newest := psapi.Version{}
+	for _, check := range checks {
+		for _, versioned := range check.Versions {
+			if newest.Older(versioned.MinimumVersion) {
+				newest = versioned.MinimumVersion
+			}
+		}
+	}
+
+	return newest
+}
@@ -0,0 +1,172 @@
+package podsecurityreadinesscontroller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	securityv1 "github.com/openshift/api/security/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+)
+
+func TestClampVersion(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		limit    psapi.Version
+		version  psapi.Version
+		expected psapi.Version
+	}{
+		{
+			name:     "latest without limit",
+			version:  psapi.LatestVersion(),
+			expected: psapi.LatestVersion(),
+		},
+		{
+			name:     "latest",
+			limit:    psapi.MajorMinorVersion(1, 25),
+			version:  psapi.LatestVersion(),
+			expected: psapi.MajorMinorVersion(1, 25),
+		},
+		{
+			name:     "newer than the cluster",
+			limit:    psapi.MajorMinorVersion(1, 25),
+			version:  psapi.MajorMinorVersion(1, 30),
+			expected: psapi.MajorMinorVersion(1, 25),
+		},
+		{
+			name:     "cluster version",
+			limit:    psapi.MajorMinorVersion(1, 25),
+			version:  psapi.MajorMinorVersion(1, 25),
+			expected: psapi.MajorMinorVersion(1, 25),
+		},
+		{
+			name:     "older than the cluster",
+			limit:    psapi.MajorMinorVersion(1, 25),
+			version:  psapi.MajorMinorVersion(1, 20),
+			expected: psapi.MajorMinorVersion(1, 20),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &PodSecurityReadinessController{versionLimit: tt.limit}
+			if actual := controller.clampVersion(tt.version); actual != tt.expected {
+				t.Errorf("expected version %v, got %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRefreshVersionLimit(t *testing.T) {
+	// forbidSince130 only forbids pods from policy version v1.30 on.
+	forbidSince130 := policy.Check{
+		ID:    "forbidSince130",
+		Level: psapi.LevelBaseline,
+		Versions: []policy.VersionedCheck{
+			{
+				MinimumVersion: psapi.MajorMinorVersion(1, 0),
+				CheckPod: func(*metav1.ObjectMeta, *corev1.PodSpec) policy.CheckResult {
+					return policy.CheckResult{Allowed: true}
+				},
+			},
+			{
+				MinimumVersion: psapi.MajorMinorVersion(1, 30),
+				CheckPod: func(*metav1.ObjectMeta, *corev1.PodSpec) policy.CheckResult {
+					return policy.CheckResult{Allowed: false, ForbiddenReason: "forbidden"}
+				},
+			},
+		},
+	}
+	userPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "user-pod",
+			Namespace: "test-ns",
+			Annotations: map[string]string{
+				securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name          string
+		gitVersion    string
+		discoveryErr  error
+		previousLimit psapi.Version
+
+		expectedLimit   psapi.Version
+		expectViolating bool
+	}{
+		{
+			name:            "cluster older than the checks",
+			gitVersion:      "v1.29.5+0123456",
+			expectedLimit:   psapi.MajorMinorVersion(1, 29),
+			expectViolating: false,
+		},
+		{
+			name:            "cluster as new as the checks",
+			gitVersion:      "v1.30.0",
+			previousLimit:   psapi.MajorMinorVersion(1, 29),
+			expectViolating: true,
+		},
+		{
+			name:            "cluster newer than the checks",
+			gitVersion:      "v1.31.2",
+			expectViolating: true,
+		},
+		{
+			name:            "unknown cluster version keeps the previous limit",
+			gitVersion:      "v0.0.0-master+$Format:%H$",
+			previousLimit:   psapi.MajorMinorVersion(1, 29),
+			expectedLimit:   psapi.MajorMinorVersion(1, 29),
+			expectViolating: false,
+		},
+		{
+			name:            "discovery error keeps the previous limit",
+			discoveryErr:    errors.New("discovery failed"),
+			previousLimit:   psapi.MajorMinorVersion(1, 29),
+			expectedLimit:   psapi.MajorMinorVersion(1, 29),
+			expectViolating: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(userPod)
+			kubeClient.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: tt.gitVersion}
+			if tt.discoveryErr != nil {
+				kubeClient.PrependReactor("get", "version", func(clienttesting.Action) (bool, runtime.Object, error) {
+					return true, nil, tt.discoveryErr
+				})
+			}
+
+			controller := &PodSecurityReadinessController{
+				kubeClient:      kubeClient,
+				warningsHandler: &warningsHandler{},
+				versionLimit:    tt.previousLimit,
+				config:          Config{Pods: PodsConfig{PolicyChecks: []policy.Check{forbidSince130}}},
+			}
+			psaEvaluator, err := policy.NewEvaluator(controller.config.Pods.PolicyChecks)
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller.psaEvaluator = psaEvaluator
+
+			controller.refreshVersionLimit()
+			if controller.versionLimit != tt.expectedLimit {
+				t.Errorf("expected version limit %v, got %v", tt.expectedLimit, controller.versionLimit)
+			}
+
+			violating, err := controller.isUserViolation(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns"}}, "baseline")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if violating != tt.expectViolating {
+				t.Errorf("expected violating %v, got %v", tt.expectViolating, violating)
+			}
+		})
+	}
+}
@@ -2,66 +2,427 @@ package podsecurityreadinesscontroller
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	securityv1 "github.com/openshift/api/security/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/sets"
 	applyconfiguration "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/klog/v2"
 	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
 )
 
 const (
-	syncerControllerName = "pod-security-admission-label-synchronization-controller"
+	defaultSyncerControllerName = "pod-security-admission-label-synchronization-controller"
+	defaultUserSCCSubjectType   = "user"
+	readinessFieldManager       = "pod-security-readiness-controller"
+
+	// The families of the pod security checks, grouped by the kind of fix
+	// they need.
+	checkFamilyVolumes      = "volumes"
+	checkFamilyCapabilities = "capabilities"
+	checkFamilyRunAsNonRoot = "runAsNonRoot"
+	checkFamilySeccomp      = "seccomp"
+	checkFamilyOther        = "other"
+)
+
+// AlertLabelPreference selects which of the warn and audit labels determine
+// the enforce level of namespaces without the minimally sufficient pod
+// security annotation.
+type AlertLabelPreference string
+
+const (
+	// AlertLabelPreferenceStrictest uses the strictest of both labels.
+	AlertLabelPreferenceStrictest AlertLabelPreference = ""
+	// AlertLabelPreferenceAudit uses the audit label if it is valid and the
+	// warn label otherwise.
+	AlertLabelPreferenceAudit AlertLabelPreference = "PreferAudit"
+	// AlertLabelPreferenceWarn uses the warn label if it is valid and the
+	// audit label otherwise.
+	AlertLabelPreferenceWarn AlertLabelPreference = "PreferWarn"
+	// AlertLabelPreferenceAuditOnly ignores the warn label.
+	AlertLabelPreferenceAuditOnly AlertLabelPreference = "AuditOnly"
+	// AlertLabelPreferenceWarnOnly ignores the audit label.
+	AlertLabelPreferenceWarnOnly AlertLabelPreference = "WarnOnly"
+)
+
+// sources returns the alert labels that are considered at all.
+func (p AlertLabelPreference) sources() sets.Set[string] {
+	switch p {
+	case AlertLabelPreferenceAuditOnly:
+		return sets.New(psapi.AuditLevelLabel)
+	case AlertLabelPreferenceWarnOnly:
+		return sets.New(psapi.WarnLevelLabel)
+	default:
+		return alertLabels
+	}
+}
+
+// preferredLabel returns the alert label that takes precedence over the
+// other one, if any.
+func (p AlertLabelPreference) preferredLabel() string {
+	switch p {
+	case AlertLabelPreferenceAudit, AlertLabelPreferenceAuditOnly:
+		return psapi.AuditLevelLabel
+	case AlertLabelPreferenceWarn, AlertLabelPreferenceWarnOnly:
+		return psapi.WarnLevelLabel
+	default:
+		return ""
+	}
+}
+
+var (
+	// ErrNoLabels is returned if a namespace has none of the labels or
+	// annotations the enforce level is determined from.
+	ErrNoLabels = errors.New("unable to determine if the namespace is violating because no appropriate labels or annotations were found")
+	// ErrUnknownLevel is returned if the level a namespace would be
+	// evaluated against isn't a pod security level.
+	ErrUnknownLevel = errors.New("unknown pod security level")
 )
 
 var (
 	alertLabels = sets.New(psapi.WarnLevelLabel, psapi.AuditLevelLabel)
+
+	errUndeterminedUserViolation = errors.New("unable to determine if the violation is caused by a user SCC")
+	errApplyConflict             = errors.New("unable to determine if the namespace is violating because of a field ownership conflict")
 )
 
-func (c *PodSecurityReadinessController) isNamespaceViolating(ctx context.Context, ns *corev1.Namespace) (bool, error) {
-	nsApplyConfig, err := applyconfiguration.ExtractNamespace(ns, syncerControllerName)
+// EvaluationResult is the outcome of evaluating a namespace against the
+// enforce level the syncer would set.
+type EvaluationResult struct {
+	// Violating is set if the namespace would violate the enforce level.
+	Violating bool `json:"violating"`
+	// UserWorkload is set if any of the violating pods was admitted through
+	// a user-bound SCC.
+	UserWorkload bool `json:"userWorkload"`
+	// Inconclusive is set if the namespace is violating, but it couldn't be
+	// decided whether any of the violating pods are user workloads.
+	Inconclusive bool `json:"inconclusive"`
+	// Level is the enforce level the namespace was evaluated against.
+	Level string `json:"level,omitempty"`
+	// Reason explains a violating or inconclusive result.
+	Reason string `json:"reason,omitempty"`
+	// CheckFamily is the family of the checks the violating user workloads
+	// fail most often, e.g. "volumes" or "capabilities".
+	CheckFamily string `json:"checkFamily,omitempty"`
+	// VolumeOnly is set if the violating user workloads fail volume checks
+	// only, which need different fixes than the other checks.
+	VolumeOnly bool `json:"volumeOnly"`
+}
+
+// evaluateNamespaceViolation evaluates the namespace against the enforce level
+// the syncer would set.
+func (c *PodSecurityReadinessController) evaluateNamespaceViolation(ctx context.Context, ns *corev1.Namespace) (EvaluationResult, error) {
+	// Warnings left behind by an early return must not be attributed to the
+	// next namespace.
+	defer c.warningsHandler.PopAll()
+
+	nsApplyConfig, resolved, err := c.resolveEnforceLevelCached(ns)
 	if err != nil {
-		return false, err
+		return EvaluationResult{}, err
 	}
+	enforceLabel := resolved.level
+	result := EvaluationResult{Level: enforceLabel}
+
+	c.reportConflictingLevels(ns, nsApplyConfig, enforceLabel)
 
-	enforceLabel, err := determineEnforceLabelForNamespace(nsApplyConfig)
+	violating, warnings, err := c.violationDetector().Detect(ctx, ns, enforceLabel)
 	if err != nil {
-		return false, err
+		return EvaluationResult{}, err
 	}
 
-	nsApply := applyconfiguration.Namespace(ns.Name).WithLabels(map[string]string{
-		psapi.EnforceLevelLabel: enforceLabel,
+	if !violating {
+		if !c.config.Pods.Templates {
+			return result, nil
+		}
+
+		workload, err := c.violatingPodTemplate(ctx, ns, enforceLabel)
+		if err != nil {
+			return EvaluationResult{}, err
+		}
+		if len(workload) == 0 {
+			return result, nil
+		}
+
+		// Pod templates don't record the SCC their pods would be admitted
+		// through, so whether they are user workloads can't be decided.
+		klog.V(4).InfoS("Pod template would violate the enforce level", "namespace", ns.Name, "level", enforceLabel, "workload", workload)
+		result.Violating = true
+		result.Inconclusive = true
+		result.Reason = fmt.Sprintf("pod template of %s would violate the PodSecurity enforce level %q", workload, enforceLabel)
+		return result, nil
+	}
+	klog.V(4).InfoS("Namespace would violate the enforce level", "namespace", ns.Name, "level", enforceLabel, "source", resolved.source, "warnings", warnings)
+	result.Violating = true
+	result.Reason = fmt.Sprintf("namespace would violate the PodSecurity enforce level %q", enforceLabel)
+	if len(warnings) > 0 {
+		result.Reason = warnings[0]
+	}
+	if c.config.Pods.SkipUserSCCCheck || isOperatorNamespace(ns) {
+		// The pods of the operator aren't user workloads, whatever SCC
+		// they were admitted through.
+		return result, nil
+	}
+
+	families, err := c.userViolationFamilies(ctx, ns, enforceLabel)
+	if errors.Is(err, errUndeterminedUserViolation) || apierrors.IsForbidden(err) {
+		// The namespace is violating regardless, only the user SCC part can't
+		// be decided.
+		result.Inconclusive = true
+		result.Reason = err.Error()
+		return result, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	result.UserWorkload = len(families) > 0
+	result.CheckFamily = dominantCheckFamily(families)
+	result.VolumeOnly = len(families) == 1 && families[checkFamilyVolumes] > 0
+
+	return result, nil
+}
+
+// dryRunEnforceLevel applies the enforce level to the namespace with a dry run
+// and returns the warnings of pod security admission about existing pods.
+func (c *PodSecurityReadinessController) dryRunEnforceLevel(ctx context.Context, namespace, level string) ([]string, error) {
+	nsApply := applyconfiguration.Namespace(namespace).WithLabels(map[string]string{
+		psapi.EnforceLevelLabel: level,
 	})
 
-	_, err = c.kubeClient.CoreV1().
-		Namespaces().
+	// Only the warnings of this Apply may be attributed to the namespace.
+	c.warningsHandler.PopAll()
+
+	// The ownership isn't forced: the Apply only conflicts if another field
+	// manager, e.g. the syncer, set the enforce label since the namespace was
+	// read, in which case the caller re-reads it.
+	_, err := c.namespacesClient().
 		Apply(ctx, nsApply, metav1.ApplyOptions{
 			DryRun:       []string{metav1.DryRunAll},
-			FieldManager: "pod-security-readiness-controller",
+			FieldManager: c.config.Client.fieldManager(),
 		})
+	if apierrors.IsConflict(err) {
+		return nil, fmt.Errorf("%w: %w", errApplyConflict, err)
+	}
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
-	// If there are warnings, the namespace is violating.
-	return len(c.warningsHandler.PopAll()) > 0, nil
+	return uniqueWarnings(c.warningsHandler.PopAll()), nil
 }
 
-func determineEnforceLabelForNamespace(ns *applyconfiguration.NamespaceApplyConfiguration) (string, error) {
+// achievableLevel probes the levels that are less strict than the violated one,
+// strictest first, and returns the first one the namespace would satisfy.
+// Privileged is always satisfied.
+//
+// Pod security admission doesn't evaluate pods when a level is relaxed
+// relative to the current policy of the namespace, including the cluster
+// default, so such levels would be reported as satisfied.
+func (c *PodSecurityReadinessController) achievableLevel(ctx context.Context, ns *corev1.Namespace, violatedLevel string) (string, error) {
+	violated, err := psapi.ParseLevel(violatedLevel)
+	if err != nil {
+		return "", err
+	}
+
+	for _, level := range []psapi.Level{psapi.LevelRestricted, psapi.LevelBaseline} {
+		if psapi.CompareLevels(level, violated) >= 0 {
+			// Every level at least as strict as the violated one is
+			// violated as well.
+			continue
+		}
+
+		violating, _, err := c.violationDetector().Detect(ctx, ns, string(level))
+		if err != nil {
+			return "", err
+		}
+		if !violating {
+			return string(level), nil
+		}
+	}
+
+	return string(psapi.LevelPrivileged), nil
+}
+
+// clampLevel raises a valid level to the minimum level if it is weaker, and
+// lowers it to the maximum level if it is stricter. Empty bounds are ignored.
+func clampLevel(level string, minimum, maximum psapi.Level) string {
+	parsed, err := psapi.ParseLevel(level)
+	if err != nil {
+		return level
+	}
+	if len(minimum) > 0 && psapi.CompareLevels(parsed, minimum) < 0 {
+		return string(minimum)
+	}
+	if len(maximum) > 0 && psapi.CompareLevels(parsed, maximum) > 0 {
+		return string(maximum)
+	}
+
+	return level
+}
+
+// isUserViolation checks whether any pod in the namespace that was admitted
+// through a user-bound SCC would violate the given enforce level.
+func (c *PodSecurityReadinessController) isUserViolation(ctx context.Context, ns *corev1.Namespace, label string) (bool, error) {
+	families, err := c.userViolationFamilies(ctx, ns, label)
+	return len(families) > 0, err
+}
+
+// userViolationFamilies evaluates the pods in the namespace that were admitted
+// through a user-bound SCC against the given enforce level, and counts their
+// failed checks by family. It is empty if none of the pods is violating.
+func (c *PodSecurityReadinessController) userViolationFamilies(ctx context.Context, ns *corev1.Namespace, label string) (map[string]int, error) {
+	var enforcementLevel psapi.Level
+	switch strings.ToLower(label) {
+	case string(psapi.LevelRestricted):
+		enforcementLevel = psapi.LevelRestricted
+	case string(psapi.LevelBaseline):
+		enforcementLevel = psapi.LevelBaseline
+	case string(psapi.LevelPrivileged):
+		// If privileged is allowed, no violations are possible.
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownLevel, label)
+	}
+
+	allPods, err := c.podsClient().List(ctx, ns.Name, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// Pods in a terminal phase won't be restarted and don't represent an
+	// ongoing risk, so only user workloads in the evaluated pod phases are
+	// evaluated, and only they count towards the limit.
+	var evaluatedPods []*corev1.Pod
+	for i := range allPods.Items {
+		pod := &allPods.Items[i]
+		if c.isUserWorkload(pod) && c.isPodPhaseEvaluated(pod) {
+			evaluatedPods = append(evaluatedPods, pod)
+		}
+	}
+	if c.config.Pods.MaxEvaluated > 0 && len(evaluatedPods) > int(c.config.Pods.MaxEvaluated) {
+		klog.V(2).InfoS("Too many pods to evaluate for user SCC violations", "namespace", ns.Name, "limit", c.config.Pods.MaxEvaluated)
+		return nil, fmt.Errorf("%w: namespace has more than %d user workload pods", errUndeterminedUserViolation, c.config.Pods.MaxEvaluated)
+	}
+
+	enforcement := psapi.LevelVersion{
+		Level:   enforcementLevel,
+		Version: c.evaluationVersion(ns),
+	}
+
+	families := map[string]int{}
+	// completedJobs caches whether the Jobs of the pods finished, so that
+	// every Job is looked up once.
+	completedJobs := map[string]bool{}
+	for _, pod := range evaluatedPods {
+		// The pod is considered violating if any check fails.
+		var failed []policy.CheckResult
+		for _, result := range c.psaEvaluator.EvaluatePod(enforcement, &pod.ObjectMeta, &pod.Spec) {
+			if !result.Allowed {
+				failed = append(failed, result)
+			}
+		}
+		if len(failed) == 0 {
+			continue
+		}
+
+		// Only violating pods are worth looking up their Job for.
+		if c.config.Pods.SkipCompletedJobs {
+			completed, err := c.isOwnedByCompletedJob(ctx, pod, completedJobs)
+			if err != nil {
+				return nil, err
+			}
+			if completed {
+				continue
+			}
+		}
+
+		for _, result := range failed {
+			families[checkFamily(result)]++
+		}
+	}
+
+	return families, nil
+}
+
+// checkFamilyOfReason maps the forbidden reasons of the pod security checks to
+// their family. Checks that aren't listed belong to checkFamilyOther.
+var checkFamilyOfReason = map[string]string{
+	"hostPath volumes":          checkFamilyVolumes,
+	"restricted volume types":   checkFamilyVolumes,
+	"non-default capabilities":  checkFamilyCapabilities,
+	"unrestricted capabilities": checkFamilyCapabilities,
+	"runAsNonRoot != true":      checkFamilyRunAsNonRoot,
+	"runAsUser=0":               checkFamilyRunAsNonRoot,
+	"seccompProfile":            checkFamilySeccomp,
+}
+
+// checkFamily returns the family of a failed check.
+func checkFamily(result policy.CheckResult) string {
+	if family, ok := checkFamilyOfReason[result.ForbiddenReason]; ok {
+		return family
+	}
+
+	return checkFamilyOther
+}
+
+// dominantCheckFamily returns the family with the most failed checks, the
+// alphabetically first one on a tie.
+func dominantCheckFamily(families map[string]int) string {
+	dominant := ""
+	for family, count := range families {
+		if count > families[dominant] || (count == families[dominant] && family < dominant) {
+			dominant = family
+		}
+	}
+
+	return dominant
+}
+
+// EvaluatePodAtLevel evaluates the pod against the given level and policy
+// version of the pod security checks of the controller, like isUserViolation
+// does for the pods of a namespace, and returns the result of every check. The
+// pod is allowed if all results are. No checks apply at the privileged level.
+func (c *PodSecurityReadinessController) EvaluatePodAtLevel(pod *corev1.Pod, level psapi.Level, version psapi.Version) ([]policy.CheckResult, error) {
+	evaluator, err := policy.NewEvaluator(c.config.Pods.policyChecks())
+	if err != nil {
+		return nil, fmt.Errorf("invalid pod security checks: %w", err)
+	}
+
+	enforcement := psapi.LevelVersion{
+		Level:   level,
+		Version: version,
+	}
+
+	return evaluator.EvaluatePod(enforcement, &pod.ObjectMeta, &pod.Spec), nil
+}
+
+// determineEnforceLabelForNamespace returns the enforce level the syncer would
+// set from its annotation or alert labels. It fails with ErrNoLabels if there
+// are none, and with ErrUnknownLevel if the annotation or none of the labels
+// is a valid level.
+func determineEnforceLabelForNamespace(ns *applyconfiguration.NamespaceApplyConfiguration, preference AlertLabelPreference) (string, error) {
 	if label, ok := ns.Annotations[securityv1.MinimallySufficientPodSecurityStandard]; ok {
 		// This should generally exist and will be the only supported method of determining
 		// the enforce level going forward - however, we're keeping the label fallback for
 		// now to account for any workloads not yet annotated using a new enough version of
 		// the syncer, such as during upgrade scenarios.
-		return label, nil
+		level := normalizeLevel(label)
+		if _, err := psapi.ParseLevel(level); err != nil {
+			return "", fmt.Errorf("%w: %q", ErrUnknownLevel, label)
+		}
+		return level, nil
 	}
 
 	viableLabels := map[string]string{}
 
-	for alertLabel := range alertLabels {
+	for alertLabel := range preference.sources() {
 		if value, ok := ns.Labels[alertLabel]; ok {
 			viableLabels[alertLabel] = value
 		}
@@ -69,35 +430,432 @@ func determineEnforceLabelForNamespace(ns *applyconfiguration.NamespaceApplyConf
 
 	if len(viableLabels) == 0 {
 		// If there are no labels/annotations managed by the syncer, we can't make a decision.
-		return "", fmt.Errorf("unable to determine if the namespace is violating because no appropriate labels or annotations were found")
+		return "", ErrNoLabels
+	}
+
+	if preferred, ok := viableLabels[preference.preferredLabel()]; ok {
+		if level, err := parseAlertLevel(preferred); err == nil {
+			return string(level), nil
+		}
+	}
+
+	return pickStrictest(viableLabels)
+}
+
+// isEnforcedNamespaceRegressed checks whether any pod in a namespace that
+// already enforces pod security would be rejected by its current enforce
+// policy, e.g. because it was created by an exempt user. Re-applying the
+// current enforce label is a no-op for the admission plugin, so instead of a
+// dry run the pods are evaluated directly.
+func (c *PodSecurityReadinessController) isEnforcedNamespaceRegressed(ctx context.Context, ns *corev1.Namespace) (bool, error) {
+	_, resolved, err := c.resolveEnforceLevelCached(ns)
+	if err != nil {
+		return false, err
+	}
+	if resolved.level == string(psapi.LevelPrivileged) {
+		return false, nil
+	}
+	enforcement := psapi.LevelVersion{
+		Level:   psapi.Level(resolved.level),
+		Version: c.clampVersion(resolved.version),
+	}
+
+	pods, err := c.podsClient().List(ctx, ns.Name, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	for _, pod := range pods.Items {
+		if !c.isPodPhaseEvaluated(&pod) {
+			continue
+		}
+
+		for _, result := range c.psaEvaluator.EvaluatePod(enforcement, &pod.ObjectMeta, &pod.Spec) {
+			if !result.Allowed {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// violatingWorkloadKinds returns the kinds of the workloads that own pods in
+// the namespace that would violate the given enforce level. The pods are
+// listed like userViolationFamilies lists them, so that a shared listing is
+// reused, see withSharedPodLists. Every workload is evaluated and looked up
+// once, by its first pod.
+func (c *PodSecurityReadinessController) violatingWorkloadKinds(ctx context.Context, ns *corev1.Namespace, label string) ([]string, error) {
+	level, err := psapi.ParseLevel(label)
+	if err != nil {
+		return nil, err
+	}
+	if level == psapi.LevelPrivileged {
+		return nil, nil
+	}
+
+	pods, err := c.podsClient().List(ctx, ns.Name, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	enforcement := psapi.LevelVersion{
+		Level:   level,
+		Version: c.evaluationVersion(ns),
+	}
+
+	kinds := sets.New[string]()
+	violatingWorkloads := sets.New[string]()
+	evaluated := 0
+	for _, pod := range pods.Items {
+		if !c.isPodPhaseEvaluated(&pod) {
+			continue
+		}
+		// The kinds are only a hint, so evaluating part of the pods is good
+		// enough.
+		if c.config.Pods.MaxEvaluated > 0 && evaluated >= int(c.config.Pods.MaxEvaluated) {
+			break
+		}
+		evaluated++
+
+		workload := controllingWorkload(&pod)
+		if violatingWorkloads.Has(workload) {
+			continue
+		}
+
+		for _, result := range c.psaEvaluator.EvaluatePod(enforcement, &pod.ObjectMeta, &pod.Spec) {
+			if !result.Allowed {
+				violatingWorkloads.Insert(workload)
+				kinds.Insert(c.workloadKind(ctx, &pod))
+				break
+			}
+		}
+	}
+
+	return sets.List(kinds), nil
+}
+
+// workloadKind returns the kind of the workload that controls the pod. Pods of
+// a ReplicaSet are attributed to its Deployment, if any, as that is what has to
+// be reconfigured.
+func (c *PodSecurityReadinessController) workloadKind(ctx context.Context, pod *corev1.Pod) string {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "Pod"
+	}
+	if owner.Kind != "ReplicaSet" || owner.APIVersion != appsv1.SchemeGroupVersion.String() {
+		return owner.Kind
+	}
+
+	replicaSet, err := c.kubeClient.AppsV1().ReplicaSets(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	if err != nil {
+		klog.V(4).ErrorS(err, "Failed to get the owner of the pod", "namespace", pod.Namespace, "pod", pod.Name)
+		return owner.Kind
+	}
+	if replicaSetOwner := metav1.GetControllerOf(replicaSet); replicaSetOwner != nil {
+		return replicaSetOwner.Kind
+	}
+
+	return owner.Kind
+}
+
+// violatingPodTemplate returns the first Deployment, StatefulSet or DaemonSet
+// in the namespace whose pod template would violate the enforce level, as
+// "<kind> <name>", or an empty string if there is none. Unlike the dry run,
+// this also covers workloads that are scaled to zero.
+func (c *PodSecurityReadinessController) violatingPodTemplate(ctx context.Context, ns *corev1.Namespace, label string) (string, error) {
+	level, err := psapi.ParseLevel(label)
+	if err != nil {
+		return "", err
+	}
+	if level == psapi.LevelPrivileged {
+		return "", nil
+	}
+
+	enforcement := psapi.LevelVersion{
+		Level:   level,
+		Version: c.evaluationVersion(ns),
+	}
+	violates := func(template *corev1.PodTemplateSpec) bool {
+		for _, result := range c.psaEvaluator.EvaluatePod(enforcement, &template.ObjectMeta, &template.Spec) {
+			if !result.Allowed {
+				return true
+			}
+		}
+		return false
+	}
+
+	deployments, err := c.kubeClient.AppsV1().Deployments(ns.Name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for i := range deployments.Items {
+		if violates(&deployments.Items[i].Spec.Template) {
+			return fmt.Sprintf("Deployment %s", deployments.Items[i].Name), nil
+		}
+	}
+
+	statefulSets, err := c.kubeClient.AppsV1().StatefulSets(ns.Name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for i := range statefulSets.Items {
+		if violates(&statefulSets.Items[i].Spec.Template) {
+			return fmt.Sprintf("StatefulSet %s", statefulSets.Items[i].Name), nil
+		}
+	}
+
+	daemonSets, err := c.kubeClient.AppsV1().DaemonSets(ns.Name).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+	for i := range daemonSets.Items {
+		if violates(&daemonSets.Items[i].Spec.Template) {
+			return fmt.Sprintf("DaemonSet %s", daemonSets.Items[i].Name), nil
+		}
+	}
+
+	return "", nil
+}
+
+// enforceVersionForNamespace returns the policy version the apiserver would
+// enforce in the namespace once the enforce level is set, which is the
+// namespace's enforce version if it has one. Like the admission plugin, it
+// falls back to the latest version if the enforce version is invalid.
+func enforceVersionForNamespace(ns *corev1.Namespace) psapi.Version {
+	value, ok := ns.Labels[psapi.EnforceVersionLabel]
+	if !ok {
+		return psapi.LatestVersion()
+	}
+
+	version, err := psapi.ParseVersion(normalizeLevel(value))
+	if err != nil {
+		klog.V(4).InfoS("Invalid enforce version, falling back to the latest version", "namespace", ns.Name, "value", value, "err", err)
+		return psapi.LatestVersion()
+	}
+
+	return version
+}
+
+// isUserWorkload checks whether the pod was admitted through an SCC bound to
+// one of the subject types that count as user workloads.
+func (c *PodSecurityReadinessController) isUserWorkload(pod *corev1.Pod) bool {
+	subjectType, ok := pod.Annotations[securityv1.ValidatedSCCSubjectTypeAnnotation]
+	if !ok {
+		return c.config.Pods.IncludeUnannotated
+	}
+	// The annotation is set by the SCC admission plugin, but is only
+	// compared loosely to not skip user workloads over its formatting.
+	subjectType = normalizeSubjectType(subjectType)
+	if len(c.config.Pods.SubjectTypes) == 0 {
+		return subjectType == defaultUserSCCSubjectType
+	}
+
+	return slices.ContainsFunc(c.config.Pods.SubjectTypes, func(userSubjectType string) bool {
+		return normalizeSubjectType(userSubjectType) == subjectType
+	})
+}
+
+// normalizeSubjectType trims and lowercases an SCC subject type.
+func normalizeSubjectType(subjectType string) string {
+	return strings.ToLower(strings.TrimSpace(subjectType))
+}
+
+var (
+	// defaultEvaluatedPodPhases are the pod phases that are evaluated unless
+	// configured otherwise, see PodsConfig.Phases.
+	defaultEvaluatedPodPhases = sets.New(corev1.PodRunning, corev1.PodPending)
+
+	knownPodPhases = sets.New(corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed, corev1.PodUnknown)
+)
+
+// isPodPhaseEvaluated checks whether the pod is in one of the evaluated pod
+// phases. Terminated pods are evaluated as well with
+// PodsConfig.IncludeTerminated.
+func (c *PodSecurityReadinessController) isPodPhaseEvaluated(pod *corev1.Pod) bool {
+	if c.config.Pods.IncludeTerminated && isPodTerminated(pod) {
+		return true
+	}
+
+	phase := pod.Status.Phase
+	if len(phase) == 0 {
+		// The phase isn't set until the kubelet reports the status.
+		phase = corev1.PodPending
+	}
+
+	if len(c.config.Pods.Phases) == 0 {
+		return defaultEvaluatedPodPhases.Has(phase)
+	}
+
+	return slices.Contains(c.config.Pods.Phases, phase)
+}
+
+func isPodTerminated(pod *corev1.Pod) bool {
+	return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed
+}
+
+// isOwnedByCompletedJob checks whether the pod is controlled by a Job that
+// has already finished. completedJobs caches the outcome per Job name across
+// the pods of a namespace.
+func (c *PodSecurityReadinessController) isOwnedByCompletedJob(ctx context.Context, pod *corev1.Pod, completedJobs map[string]bool) (bool, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil || owner.Kind != "Job" || owner.APIVersion != batchv1.SchemeGroupVersion.String() {
+		return false, nil
+	}
+	if completed, ok := completedJobs[owner.Name]; ok {
+		return completed, nil
+	}
+
+	job, err := c.kubeClient.BatchV1().Jobs(pod.Namespace).Get(ctx, owner.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		completedJobs[owner.Name] = false
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	completed := false
+	for _, condition := range job.Status.Conditions {
+		if (condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed) && condition.Status == corev1.ConditionTrue {
+			completed = true
+			break
+		}
+	}
+	completedJobs[owner.Name] = completed
+
+	return completed, nil
+}
+
+// reportConflictingLevels logs conflicting alert levels of the namespace, see
+// conflictingAlertLevels, and records an event if enabled. A conflict is only
+// reported when it is first seen or when it changes, not on every sync.
+func (c *PodSecurityReadinessController) reportConflictingLevels(ns *corev1.Namespace, nsApplyConfig *applyconfiguration.NamespaceApplyConfiguration, enforceLabel string) {
+	warn, audit, ok := conflictingAlertLevels(nsApplyConfig)
+	if !ok {
+		delete(c.reportedConflicts, ns.Name)
+		return
+	}
+
+	conflict := fmt.Sprintf("warn=%s,audit=%s,chosen=%s", warn, audit, enforceLabel)
+	if c.reportedConflicts[ns.Name] == conflict {
+		return
+	}
+	if c.reportedConflicts == nil {
+		c.reportedConflicts = map[string]string{}
+	}
+	c.reportedConflicts[ns.Name] = conflict
+
+	klog.V(2).InfoS("Conflicting pod security alert levels", "namespace", ns.Name, "warn", warn, "audit", audit, "chosen", enforceLabel)
+	if c.config.Levels.ConflictingLevelEvents {
+		c.recorder.Warningf("PodSecurityConflictingLevels",
+			"Namespace %s has conflicting pod security levels warn=%s and audit=%s, evaluating against %s",
+			ns.Name, warn, audit, enforceLabel)
+	}
+}
+
+// conflictingAlertLevels returns the warn and audit levels managed by the
+// syncer if they are on opposite ends of the level range, which usually
+// indicates a mislabeled namespace. The labels are only consulted when the
+// namespace doesn't carry the syncer annotation.
+func conflictingAlertLevels(ns *applyconfiguration.NamespaceApplyConfiguration) (psapi.Level, psapi.Level, bool) {
+	if _, ok := ns.Annotations[securityv1.MinimallySufficientPodSecurityStandard]; ok {
+		return "", "", false
+	}
+
+	warn, err := parseAlertLevel(ns.Labels[psapi.WarnLevelLabel])
+	if err != nil {
+		return "", "", false
+	}
+
+	audit, err := parseAlertLevel(ns.Labels[psapi.AuditLevelLabel])
+	if err != nil {
+		return "", "", false
+	}
+
+	levels := sets.New(warn, audit)
+	if levels.Has(psapi.LevelPrivileged) && levels.Has(psapi.LevelRestricted) {
+		return warn, audit, true
+	}
+
+	return "", "", false
+}
+
+// isAnnotationStale checks whether the minimally sufficient pod security
+// annotation disagrees with the strictest of the current alert labels, e.g.
+// because the syncer hasn't reconciled the namespace yet. The annotation takes
+// precedence when determining the enforce level, so the evaluation might be
+// misleading.
+func isAnnotationStale(ns *corev1.Namespace) bool {
+	annotation, ok := ns.Annotations[securityv1.MinimallySufficientPodSecurityStandard]
+	if !ok {
+		return false
+	}
+
+	viableLabels := map[string]string{}
+	for alertLabel := range alertLabels {
+		if value, ok := ns.Labels[alertLabel]; ok {
+			viableLabels[alertLabel] = value
+		}
+	}
+	if len(viableLabels) == 0 {
+		return false
+	}
+
+	strictest, err := pickStrictest(viableLabels)
+	if err != nil {
+		// Labels without a valid level can't contradict the annotation.
+		return false
+	}
+
+	return strictest != normalizeLevel(annotation)
+}
+
+// normalizeLevel tolerates surrounding whitespace and mixed case in level and
+// version values set by admins.
+func normalizeLevel(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+// parseAlertLevel parses the level of an alert label value. The value may
+// carry a version after the level, e.g. "restricted:v1.24", which is parsed
+// separately and doesn't affect the level.
+func parseAlertLevel(value string) (psapi.Level, error) {
+	level, version, hasVersion := strings.Cut(normalizeLevel(value), ":")
+	if hasVersion {
+		if _, err := psapi.ParseVersion(strings.TrimSpace(version)); err != nil {
+			klog.V(4).InfoS("Ignoring invalid version of alert label value", "value", value, "err", err)
+		}
 	}
 
-	return pickStrictest(viableLabels), nil
+	return psapi.ParseLevel(strings.TrimSpace(level))
 }
 
-func pickStrictest(viableLabels map[string]string) string {
+// pickStrictest returns the strictest of the levels of the labels. It fails
+// with ErrUnknownLevel if none of them is a valid level, as such a namespace
+// is misconfigured rather than violating any particular level.
+func pickStrictest(viableLabels map[string]string) (string, error) {
 	targetLevel := ""
 	for label, value := range viableLabels {
-		level, err := psapi.ParseLevel(value)
+		level, err := parseAlertLevel(value)
 		if err != nil {
 			klog.V(4).InfoS("invalid level", "label", label, "value", value)
 			continue
 		}
 
 		if targetLevel == "" {
-			targetLevel = value
+			targetLevel = string(level)
 			continue
 		}
 
 		if psapi.CompareLevels(psapi.Level(targetLevel), level) < 0 {
-			targetLevel = value
+			targetLevel = string(level)
 		}
 	}
 
 	if targetLevel == "" {
-		// Global Config will set it to "restricted", but shouldn't happen.
-		return string(psapi.LevelRestricted)
+		return "", fmt.Errorf("%w: none of the labels %v has a valid level", ErrUnknownLevel, viableLabels)
 	}
 
-	return targetLevel
+	return targetLevel, nil
 }
@@ -2,37 +2,42 @@ package podsecurityreadinesscontroller
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
 	"testing"
 
+	operatorv1 "github.com/openshift/api/operator/v1"
 	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	applyconfiguration "k8s.io/client-go/applyconfigurations/core/v1"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/util/retry"
 	psapi "k8s.io/pod-security-admission/api"
+	"k8s.io/pod-security-admission/policy"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
 )
 
 // Need to add managed fields to mock namespaces, since violations are only checked for labels managed by the syncer
-var managedFields = []metav1.ManagedFieldsEntry{
-	{
-		Manager:   syncerControllerName,
-		Operation: "Apply",
-		FieldsV1: &metav1.FieldsV1{
-			Raw: []byte(
-				fmt.Sprintf(`{"f:metadata":{"f:annotations":{"f:%s":{}},"f:labels":{"f:%s":{},"f:%s":{},"f:%s":{}}}}`,
-					securityv1.MinimallySufficientPodSecurityStandard,
-					psapi.WarnLevelLabel,
-					psapi.AuditLevelLabel,
-					psapi.EnforceLevelLabel,
-				),
-			),
-		},
-	},
-}
-
-func TestIsNamespaceViolating(t *testing.T) {
+var managedFields = syncerManagedFields()
+
+func TestEvaluateNamespaceViolation(t *testing.T) {
 	tests := []struct {
 		name            string
 		namespace       *corev1.Namespace
@@ -113,54 +118,2337 @@ func TestIsNamespaceViolating(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			mockWarnings := &warningsHandler{
-				warnings: tc.warnings,
+			mockWarnings := &warningsHandler{}
+			kubeClient := tc.setupMockClient()
+			if mock, ok := kubeClient.(*mockKubeClientWithResponse); ok {
+				mock.warningsHandler = mockWarnings
+				mock.warnings = tc.warnings
 			}
 
 			controller := &PodSecurityReadinessController{
-				kubeClient:      tc.setupMockClient(),
+				kubeClient:      kubeClient,
 				warningsHandler: mockWarnings,
 			}
 
 			tc.namespace.ManagedFields = managedFields
 
-			violating, err := controller.isNamespaceViolating(context.Background(), tc.namespace)
+			result, err := controller.evaluateNamespaceViolation(context.Background(), tc.namespace)
 
 			if (err != nil) != tc.expectError {
-				t.Errorf("isNamespaceViolating() error = %v, expectError %v", err, tc.expectError)
+				t.Errorf("evaluateNamespaceViolation() error = %v, expectError %v", err, tc.expectError)
 				return
 			}
 
-			if violating != tc.expectViolating {
-				t.Errorf("isNamespaceViolating() violating = %v, expectViolating %v", violating, tc.expectViolating)
+			if result.Violating != tc.expectViolating {
+				t.Errorf("evaluateNamespaceViolation() violating = %v, expectViolating %v", result.Violating, tc.expectViolating)
 			}
 		})
 	}
 }
 
-type mockKubeClientWithResponse struct {
-	kubernetes.Interface
-	error error
-}
+func TestEvaluationResult(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Labels: map[string]string{
+				psapi.WarnLevelLabel: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	}
+	privileged := true
+	userPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "user-pod",
+			Namespace: "test-ns",
+			Annotations: map[string]string{
+				securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+			},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:            "privileged",
+				SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+			}},
+		},
+	}
+	warning := `existing pods in namespace "test-ns" violate the new PodSecurity enforce level "restricted:latest"`
 
-func (m *mockKubeClientWithResponse) CoreV1() typedcorev1.CoreV1Interface {
-	return &mockCoreV1WithResponse{error: m.error}
-}
+	for _, tt := range []struct {
+		name          string
+		warnings      []string
+		objects       []runtime.Object
+		forbidPodList bool
 
-type mockCoreV1WithResponse struct {
-	typedcorev1.CoreV1Interface
-	error error
+		expected EvaluationResult
+	}{
+		{
+			name:     "not violating",
+			expected: EvaluationResult{Level: "restricted"},
+		},
+		{
+			name:     "violating without user workloads",
+			warnings: []string{warning},
+			expected: EvaluationResult{Violating: true, Level: "restricted", Reason: warning},
+		},
+		{
+			name:     "violating user workload",
+			warnings: []string{warning},
+			objects:  []runtime.Object{userPod},
+			expected: EvaluationResult{Violating: true, UserWorkload: true, Level: "restricted", Reason: warning, CheckFamily: checkFamilyOther},
+		},
+		{
+			name:          "violating with forbidden pod list",
+			warnings:      []string{warning},
+			forbidPodList: true,
+			expected: EvaluationResult{
+				Violating:    true,
+				Inconclusive: true,
+				Level:        "restricted",
+				Reason:       "pods is forbidden: not allowed",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &warningsHandler{}
+			fakeClient := fake.NewSimpleClientset(tt.objects...)
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				for _, warning := range tt.warnings {
+					handler.HandleWarningHeader(299, "", warning)
+				}
+				return true, nil, nil
+			})
+			if tt.forbidPodList {
+				fakeClient.PrependReactor("list", "pods", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+					return true, nil, apierrors.NewForbidden(corev1.Resource("pods"), "", fmt.Errorf("not allowed"))
+				})
+			}
+
+			psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				psaEvaluator:    psaEvaluator,
+				warningsHandler: handler,
+			}
+
+			result, err := controller.evaluateNamespaceViolation(context.Background(), namespace)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected result %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
 }
 
-func (m *mockCoreV1WithResponse) Namespaces() typedcorev1.NamespaceInterface {
-	return &mockNamespaceInterfaceWithResponse{error: m.error}
+func TestIsUserViolation(t *testing.T) {
+	forbidAll := policy.Check{
+		ID:    "forbidAll",
+		Level: psapi.LevelBaseline,
+		Versions: []policy.VersionedCheck{
+			{
+				MinimumVersion: psapi.MajorMinorVersion(1, 0),
+				CheckPod: func(*metav1.ObjectMeta, *corev1.PodSpec) policy.CheckResult {
+					return policy.CheckResult{Allowed: false, ForbiddenReason: "forbidden"}
+				},
+			},
+		},
+	}
+
+	// forbidSince130 only forbids pods from policy version v1.30 on.
+	forbidSince130 := policy.Check{
+		ID:    "forbidSince130",
+		Level: psapi.LevelBaseline,
+		Versions: []policy.VersionedCheck{
+			{
+				MinimumVersion: psapi.MajorMinorVersion(1, 0),
+				CheckPod: func(*metav1.ObjectMeta, *corev1.PodSpec) policy.CheckResult {
+					return policy.CheckResult{Allowed: true}
+				},
+			},
+			{
+				MinimumVersion: psapi.MajorMinorVersion(1, 30),
+				CheckPod: func(*metav1.ObjectMeta, *corev1.PodSpec) policy.CheckResult {
+					return policy.CheckResult{Allowed: false, ForbiddenReason: "forbidden"}
+				},
+			},
+		},
+	}
+
+	userPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "user-pod",
+			Namespace: "test-ns",
+			Annotations: map[string]string{
+				securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+			},
+		},
+	}
+	serviceAccountPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "sa-pod",
+			Namespace: "test-ns",
+			Annotations: map[string]string{
+				securityv1.ValidatedSCCSubjectTypeAnnotation: "serviceaccount",
+			},
+		},
+	}
+
+	// subjectTypePod is a pod whose subject type annotation isn't formatted
+	// like the SCC admission plugin sets it.
+	subjectTypePod := func(subjectType string) *corev1.Pod {
+		pod := userPod.DeepCopy()
+		pod.Annotations[securityv1.ValidatedSCCSubjectTypeAnnotation] = subjectType
+		return pod
+	}
+
+	unannotatedPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unannotated-pod",
+			Namespace: "test-ns",
+		},
+	}
+
+	secondUserPod := userPod.DeepCopy()
+	secondUserPod.Name = "second-user-pod"
+
+	succeededUserPod := userPod.DeepCopy()
+	succeededUserPod.Name = "succeeded-user-pod"
+	succeededUserPod.Status.Phase = corev1.PodSucceeded
+
+	failedUserPod := userPod.DeepCopy()
+	failedUserPod.Status.Phase = corev1.PodFailed
+
+	unknownUserPod := userPod.DeepCopy()
+	unknownUserPod.Status.Phase = corev1.PodUnknown
+
+	completedJob := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "completed-job",
+			Namespace: "test-ns",
+		},
+		Status: batchv1.JobStatus{
+			Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+			},
+		},
+	}
+	jobOwnerReferences := []metav1.OwnerReference{
+		*metav1.NewControllerRef(completedJob, batchv1.SchemeGroupVersion.WithKind("Job")),
+	}
+
+	succeededJobPod := succeededUserPod.DeepCopy()
+	succeededJobPod.OwnerReferences = jobOwnerReferences
+
+	runningJobPod := userPod.DeepCopy()
+	runningJobPod.OwnerReferences = jobOwnerReferences
+	runningJobPod.Status.Phase = corev1.PodRunning
+
+	tests := []struct {
+		name            string
+		checks          []policy.Check
+		objects         []runtime.Object
+		pods            PodsConfig
+		namespaceLabels map[string]string
+		label           string
+		expectViolating bool
+		expectError     bool
+		// expectUndetermined is set if the error should only mark the user
+		// SCC check as inconclusive.
+		expectUndetermined bool
+	}{
+		{
+			name:            "default checks with compliant user pod",
+			checks:          policy.DefaultChecks(),
+			objects:         []runtime.Object{userPod},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with user pod",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{userPod},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with service account pod",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{serviceAccountPod},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with unannotated pod",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{unannotatedPod},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with unannotated pod and unannotated pod evaluation",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{unannotatedPod},
+			pods:            PodsConfig{IncludeUnannotated: true},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with service account pod and unannotated pod evaluation",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{serviceAccountPod},
+			pods:            PodsConfig{IncludeUnannotated: true},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with service account pod counted as user workload",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{serviceAccountPod},
+			pods:            PodsConfig{SubjectTypes: []string{"user", "serviceaccount"}},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with user pod not counted as user workload",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{userPod},
+			pods:            PodsConfig{SubjectTypes: []string{"serviceaccount"}},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with capitalized user pod",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{subjectTypePod("User")},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with upper case user pod and surrounding whitespace",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{subjectTypePod(" USER\t")},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with capitalized service account pod counted as user workload",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{subjectTypePod("ServiceAccount")},
+			pods:            PodsConfig{SubjectTypes: []string{" serviceAccount "}},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with user pod of a different subject type",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{subjectTypePod("users")},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with succeeded user pod",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{succeededUserPod},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with failed user pod",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{failedUserPod},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with succeeded user pod and terminated pod evaluation",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{succeededUserPod},
+			pods:            PodsConfig{IncludeTerminated: true},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with unknown user pod",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{unknownUserPod},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with unknown user pod and all pod phases evaluated",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{unknownUserPod},
+			pods:            PodsConfig{Phases: []corev1.PodPhase{corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed, corev1.PodUnknown}},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with failed user pod and failed pods evaluated",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{failedUserPod},
+			pods:            PodsConfig{Phases: []corev1.PodPhase{corev1.PodRunning, corev1.PodFailed}},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with completed job pod",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{completedJob, succeededJobPod},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with running pod of completed job",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{completedJob, runningJobPod},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with running pod of completed job and completed job pods skipped",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{completedJob, runningJobPod},
+			pods:            PodsConfig{SkipCompletedJobs: true},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with completed job pod and all filtering options",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{completedJob, succeededJobPod},
+			pods:            PodsConfig{IncludeTerminated: true, SkipCompletedJobs: true},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "custom check with user pod under the pod limit",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{userPod, secondUserPod},
+			pods:            PodsConfig{MaxEvaluated: 2},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "custom check with skipped pods over the pod limit",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{userPod, serviceAccountPod, succeededUserPod},
+			pods:            PodsConfig{MaxEvaluated: 1},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:        "custom check with user pods over the pod limit",
+			checks:      []policy.Check{forbidAll},
+			objects:     []runtime.Object{userPod, secondUserPod},
+			pods:        PodsConfig{MaxEvaluated: 1},
+			label:       "baseline",
+			expectError: true,
+
+			expectUndetermined: true,
+		},
+		{
+			name:            "custom check with privileged level",
+			checks:          []policy.Check{forbidAll},
+			objects:         []runtime.Object{userPod},
+			label:           "privileged",
+			expectViolating: false,
+		},
+		{
+			name:        "unknown level",
+			checks:      []policy.Check{forbidAll},
+			objects:     []runtime.Object{userPod},
+			label:       "unknown",
+			expectError: true,
+		},
+		{
+			name:            "versioned check without enforce version",
+			checks:          []policy.Check{forbidSince130},
+			objects:         []runtime.Object{userPod},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "versioned check with older enforce version",
+			checks:          []policy.Check{forbidSince130},
+			objects:         []runtime.Object{userPod},
+			namespaceLabels: map[string]string{psapi.EnforceVersionLabel: "v1.29"},
+			label:           "baseline",
+			expectViolating: false,
+		},
+		{
+			name:            "versioned check with newer enforce version",
+			checks:          []policy.Check{forbidSince130},
+			objects:         []runtime.Object{userPod},
+			namespaceLabels: map[string]string{psapi.EnforceVersionLabel: "v1.30"},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "versioned check with latest enforce version",
+			checks:          []policy.Check{forbidSince130},
+			objects:         []runtime.Object{userPod},
+			namespaceLabels: map[string]string{psapi.EnforceVersionLabel: "latest"},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "versioned check with mixed case latest enforce version",
+			checks:          []policy.Check{forbidSince130},
+			objects:         []runtime.Object{userPod},
+			namespaceLabels: map[string]string{psapi.EnforceVersionLabel: " Latest "},
+			label:           "baseline",
+			expectViolating: true,
+		},
+		{
+			name:            "invalid enforce version",
+			checks:          []policy.Check{forbidSince130},
+			objects:         []runtime.Object{userPod},
+			namespaceLabels: map[string]string{psapi.EnforceVersionLabel: "unknown"},
+			label:           "baseline",
+			expectViolating: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.pods.PolicyChecks = tc.checks
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fake.NewSimpleClientset(tc.objects...),
+				warningsHandler: &warningsHandler{},
+				config:          Config{Pods: tc.pods},
+			}
+
+			psaEvaluator, err := policy.NewEvaluator(controller.config.Pods.policyChecks())
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller.psaEvaluator = psaEvaluator
+
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns", Labels: tc.namespaceLabels}}
+			violating, err := controller.isUserViolation(context.Background(), namespace, tc.label)
+			if (err != nil) != tc.expectError {
+				t.Errorf("isUserViolation() error = %v, expectError %v", err, tc.expectError)
+				return
+			}
+
+			if errors.Is(err, errUndeterminedUserViolation) != tc.expectUndetermined {
+				t.Errorf("isUserViolation() error = %v, expectUndetermined %v", err, tc.expectUndetermined)
+			}
+
+			if violating != tc.expectViolating {
+				t.Errorf("isUserViolation() violating = %v, expectViolating %v", violating, tc.expectViolating)
+			}
+		})
+	}
 }
 
-type mockNamespaceInterfaceWithResponse struct {
-	typedcorev1.NamespaceInterface
-	error error
+func TestIsUserViolationWithDefaultChecks(t *testing.T) {
+	// restrictedPod is compliant with the restricted level of the latest
+	// policy version.
+	restrictedPod := func(mutate func(*corev1.Pod)) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "user-pod",
+				Namespace: "test-ns",
+				Annotations: map[string]string{
+					securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+				},
+			},
+			Spec: corev1.PodSpec{
+				SecurityContext: &corev1.PodSecurityContext{
+					RunAsNonRoot:   ptr.To(true),
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+				},
+				Containers: []corev1.Container{{
+					Name: "container",
+					SecurityContext: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: ptr.To(false),
+						Capabilities: &corev1.Capabilities{
+							Drop: []corev1.Capability{"ALL"},
+						},
+					},
+				}},
+			},
+		}
+		if mutate != nil {
+			mutate(pod)
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name             string
+		pod              *corev1.Pod
+		enforceVersion   string
+		expectRestricted bool
+		expectBaseline   bool
+	}{
+		{
+			name: "compliant pod",
+			pod:  restrictedPod(nil),
+		},
+		{
+			name: "unconfined seccomp profile",
+			pod: restrictedPod(func(pod *corev1.Pod) {
+				pod.Spec.SecurityContext.SeccompProfile.Type = corev1.SeccompProfileTypeUnconfined
+			}),
+			expectRestricted: true,
+			expectBaseline:   true,
+		},
+		{
+			name: "capabilities not dropped",
+			pod: restrictedPod(func(pod *corev1.Pod) {
+				pod.Spec.Containers[0].SecurityContext.Capabilities = nil
+			}),
+			expectRestricted: true,
+		},
+		{
+			name: "root user",
+			pod: restrictedPod(func(pod *corev1.Pod) {
+				pod.Spec.SecurityContext.RunAsUser = ptr.To[int64](0)
+			}),
+			expectRestricted: true,
+		},
+		{
+			name: "root user before the runAsUser check was added",
+			pod: restrictedPod(func(pod *corev1.Pod) {
+				pod.Spec.SecurityContext.RunAsUser = ptr.To[int64](0)
+			}),
+			enforceVersion: "v1.22",
+		},
+		{
+			name: "unconfined AppArmor profile",
+			pod: restrictedPod(func(pod *corev1.Pod) {
+				pod.Spec.SecurityContext.AppArmorProfile = &corev1.AppArmorProfile{Type: corev1.AppArmorProfileTypeUnconfined}
+			}),
+			expectRestricted: true,
+			expectBaseline:   true,
+		},
+		{
+			name: "unmasked proc mount in a user namespace",
+			pod: restrictedPod(func(pod *corev1.Pod) {
+				// The relaxation for user namespaces is behind the
+				// UserNamespacesPodSecurityStandards feature gate, which
+				// the evaluator doesn't enable.
+				pod.Spec.HostUsers = ptr.To(false)
+				pod.Spec.Containers[0].SecurityContext.ProcMount = ptr.To(corev1.UnmaskedProcMount)
+			}),
+			expectRestricted: true,
+			expectBaseline:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fake.NewSimpleClientset(tc.pod),
+				warningsHandler: &warningsHandler{},
+			}
+
+			psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller.psaEvaluator = psaEvaluator
+
+			namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns"}}
+			if len(tc.enforceVersion) > 0 {
+				namespace.Labels = map[string]string{psapi.EnforceVersionLabel: tc.enforceVersion}
+			}
+
+			for level, expected := range map[string]bool{
+				"restricted": tc.expectRestricted,
+				"baseline":   tc.expectBaseline,
+			} {
+				violating, err := controller.isUserViolation(context.Background(), namespace, level)
+				if err != nil {
+					t.Fatalf("unexpected error at level %s: %v", level, err)
+				}
+				if violating != expected {
+					t.Errorf("expected violating %v at level %s, got %v", expected, level, violating)
+				}
+			}
+		})
+	}
 }
 
-func (m *mockNamespaceInterfaceWithResponse) Apply(ctx context.Context, nsApply *applyconfiguration.NamespaceApplyConfiguration, opts metav1.ApplyOptions) (*corev1.Namespace, error) {
-	return nil, m.error
+func TestEvaluatePodAtLevel(t *testing.T) {
+	restrictedSpec := corev1.PodSpec{
+		SecurityContext: &corev1.PodSecurityContext{
+			RunAsNonRoot:   ptr.To(true),
+			SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+		},
+		Containers: []corev1.Container{{
+			Name: "container",
+			SecurityContext: &corev1.SecurityContext{
+				AllowPrivilegeEscalation: ptr.To(false),
+				Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+			},
+		}},
+	}
+
+	restrictedPod := &corev1.Pod{Spec: *restrictedSpec.DeepCopy()}
+	baselinePod := &corev1.Pod{Spec: *restrictedSpec.DeepCopy()}
+	baselinePod.Spec.Containers[0].SecurityContext.Capabilities = nil
+	privilegedPod := &corev1.Pod{Spec: *restrictedSpec.DeepCopy()}
+	privilegedPod.Spec.HostNetwork = true
+	rootPod := &corev1.Pod{Spec: *restrictedSpec.DeepCopy()}
+	rootPod.Spec.SecurityContext.RunAsUser = ptr.To[int64](0)
+
+	tests := []struct {
+		name    string
+		checks  []policy.Check
+		pod     *corev1.Pod
+		level   psapi.Level
+		version psapi.Version
+
+		expectedForbidden []string
+		expectError       bool
+	}{
+		{
+			name:  "restricted pod at restricted",
+			pod:   restrictedPod,
+			level: psapi.LevelRestricted,
+		},
+		{
+			name:              "baseline pod at restricted",
+			pod:               baselinePod,
+			level:             psapi.LevelRestricted,
+			expectedForbidden: []string{"unrestricted capabilities"},
+		},
+		{
+			name:  "baseline pod at baseline",
+			pod:   baselinePod,
+			level: psapi.LevelBaseline,
+		},
+		{
+			name:              "privileged pod at baseline",
+			pod:               privilegedPod,
+			level:             psapi.LevelBaseline,
+			expectedForbidden: []string{"host namespaces"},
+		},
+		{
+			name:              "privileged pod at restricted",
+			pod:               privilegedPod,
+			level:             psapi.LevelRestricted,
+			expectedForbidden: []string{"host namespaces"},
+		},
+		{
+			name:  "privileged pod at privileged",
+			pod:   privilegedPod,
+			level: psapi.LevelPrivileged,
+		},
+		{
+			name:              "root pod at restricted",
+			pod:               rootPod,
+			level:             psapi.LevelRestricted,
+			expectedForbidden: []string{"runAsUser=0"},
+		},
+		{
+			name:    "root pod at restricted before the runAsUser check was added",
+			pod:     rootPod,
+			level:   psapi.LevelRestricted,
+			version: psapi.MajorMinorVersion(1, 22),
+		},
+		{
+			name:   "invalid checks",
+			checks: []policy.Check{{ID: "invalid"}},
+			pod:    restrictedPod,
+			level:  psapi.LevelRestricted,
+
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			version := tc.version
+			if version == (psapi.Version{}) {
+				version = psapi.LatestVersion()
+			}
+			controller := &PodSecurityReadinessController{
+				config: Config{Pods: PodsConfig{PolicyChecks: tc.checks}},
+			}
+
+			results, err := controller.EvaluatePodAtLevel(tc.pod, tc.level, version)
+			if tc.expectError {
+				if err == nil {
+					t.Error("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var forbidden []string
+			for _, result := range results {
+				if !result.Allowed {
+					forbidden = append(forbidden, result.ForbiddenReason)
+				}
+			}
+
+			if !reflect.DeepEqual(forbidden, tc.expectedForbidden) {
+				t.Errorf("expected forbidden reasons %v, got %v", tc.expectedForbidden, forbidden)
+			}
+		})
+	}
+}
+
+func TestConflictingAlertLevels(t *testing.T) {
+	tests := []struct {
+		name           string
+		namespace      *corev1.Namespace
+		expectConflict bool
+	}{
+		{
+			name: "privileged audit and restricted warn",
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ns",
+					Labels: map[string]string{
+						psapi.AuditLevelLabel: "privileged",
+						psapi.WarnLevelLabel:  "restricted",
+					},
+				},
+			},
+			expectConflict: true,
+		},
+		{
+			name: "baseline audit and restricted warn",
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ns",
+					Labels: map[string]string{
+						psapi.AuditLevelLabel: "baseline",
+						psapi.WarnLevelLabel:  "restricted",
+					},
+				},
+			},
+			expectConflict: false,
+		},
+		{
+			name: "only warn label",
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ns",
+					Labels: map[string]string{
+						psapi.WarnLevelLabel: "restricted",
+					},
+				},
+			},
+			expectConflict: false,
+		},
+		{
+			name: "conflicting labels ignored in favor of annotation",
+			namespace: &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ns",
+					Annotations: map[string]string{
+						securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+					},
+					Labels: map[string]string{
+						psapi.AuditLevelLabel: "privileged",
+						psapi.WarnLevelLabel:  "restricted",
+					},
+				},
+			},
+			expectConflict: false,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.namespace.ManagedFields = managedFields
+
+			nsApplyConfig, err := applyconfiguration.ExtractNamespace(tc.namespace, defaultSyncerControllerName)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, _, conflict := conflictingAlertLevels(nsApplyConfig)
+			if conflict != tc.expectConflict {
+				t.Errorf("conflictingAlertLevels() conflict = %v, expectConflict %v", conflict, tc.expectConflict)
+			}
+		})
+	}
+
+	t.Run("emits event when enabled", func(t *testing.T) {
+		recorder := events.NewInMemoryRecorder("test", clock.RealClock{})
+		controller := &PodSecurityReadinessController{
+			kubeClient:      &mockKubeClientWithResponse{},
+			warningsHandler: &warningsHandler{},
+			recorder:        recorder,
+			config:          Config{Levels: LevelsConfig{ConflictingLevelEvents: true}},
+		}
+
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-ns",
+				Labels: map[string]string{
+					psapi.AuditLevelLabel: "privileged",
+					psapi.WarnLevelLabel:  "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		}
+
+		if _, err := controller.evaluateNamespaceViolation(context.Background(), namespace); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(recorder.Events()) != 1 || recorder.Events()[0].Reason != "PodSecurityConflictingLevels" {
+			t.Errorf("expected a single PodSecurityConflictingLevels event, got %v", recorder.Events())
+		}
+
+		// An unchanged conflict is only reported once.
+		if _, err := controller.evaluateNamespaceViolation(context.Background(), namespace); err != nil {
+			t.Fatal(err)
+		}
+		if len(recorder.Events()) != 1 {
+			t.Errorf("expected the conflict to be reported once, got %v", recorder.Events())
+		}
+
+		// A changed conflict is reported again.
+		namespace.Labels[psapi.AuditLevelLabel] = "restricted"
+		namespace.Labels[psapi.WarnLevelLabel] = "privileged"
+		if _, err := controller.evaluateNamespaceViolation(context.Background(), namespace); err != nil {
+			t.Fatal(err)
+		}
+		if len(recorder.Events()) != 2 {
+			t.Errorf("expected the changed conflict to be reported, got %v", recorder.Events())
+		}
+	})
+}
+
+func TestIsAnnotationStale(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		annotations map[string]string
+		labels      map[string]string
+		expected    bool
+	}{
+		{
+			name:        "annotation matches labels",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			labels:      map[string]string{psapi.WarnLevelLabel: "restricted", psapi.AuditLevelLabel: "restricted"},
+			expected:    false,
+		},
+		{
+			name:        "annotation matches strictest label",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			labels:      map[string]string{psapi.WarnLevelLabel: "restricted", psapi.AuditLevelLabel: "baseline"},
+			expected:    false,
+		},
+		{
+			name:        "annotation disagrees with labels",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "privileged"},
+			labels:      map[string]string{psapi.WarnLevelLabel: "restricted", psapi.AuditLevelLabel: "restricted"},
+			expected:    true,
+		},
+		{
+			name:        "annotation without labels",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			expected:    false,
+		},
+		{
+			name:     "labels without annotation",
+			labels:   map[string]string{psapi.WarnLevelLabel: "restricted"},
+			expected: false,
+		},
+		{
+			name:        "labels without valid level",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			labels:      map[string]string{psapi.WarnLevelLabel: "garbage", psapi.AuditLevelLabel: "garbage"},
+			expected:    false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-ns",
+					Annotations: tt.annotations,
+					Labels:      tt.labels,
+				},
+			}
+
+			if stale := isAnnotationStale(ns); stale != tt.expected {
+				t.Errorf("expected stale %v, got %v", tt.expected, stale)
+			}
+		})
+	}
+}
+
+func TestApplyConflict(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Labels: map[string]string{
+				psapi.WarnLevelLabel: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	}
+	conflict := apierrors.NewConflict(corev1.Resource("namespaces"), "test-ns", fmt.Errorf("conflict with \"admin\": .metadata.labels.pod-security.kubernetes.io/enforce"))
+
+	t.Run("ownership isn't forced", func(t *testing.T) {
+		fakeClient := fake.NewSimpleClientset()
+		fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			if force := action.(clienttesting.PatchActionImpl).PatchOptions.Force; force != nil && *force {
+				t.Errorf("expected the ownership not to be forced")
+			}
+			return true, nil, conflict
+		})
+
+		controller := &PodSecurityReadinessController{
+			kubeClient:      fakeClient,
+			warningsHandler: &warningsHandler{},
+		}
+
+		_, err := controller.evaluateNamespaceViolation(context.Background(), namespace)
+		if !errors.Is(err, errApplyConflict) || !apierrors.IsConflict(err) {
+			t.Errorf("expected an apply conflict error, got %v", err)
+		}
+	})
+
+	t.Run("conflicts are retried with the fresh namespace", func(t *testing.T) {
+		var patches int
+		fakeClient := fake.NewSimpleClientset(namespace)
+		fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			patches++
+			if patches == 1 {
+				return true, nil, conflict
+			}
+			return true, nil, nil
+		})
+
+		controller := &PodSecurityReadinessController{
+			kubeClient:      fakeClient,
+			operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			clock:           clock.RealClock{},
+			warningsHandler: &warningsHandler{},
+			dryRunVerified:  true,
+		}
+
+		syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+		if err := controller.sync(context.Background(), syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if patches != 2 {
+			t.Errorf("expected the conflict to be retried once, got %d dry runs", patches)
+		}
+		if conditions := controller.snapshot(); len(conditions.inconclusiveNamespaces) != 0 {
+			t.Errorf("expected no inconclusive namespaces, got %v", conditions.inconclusiveNamespaces)
+		}
+	})
+
+	t.Run("persistent conflicts are inconclusive", func(t *testing.T) {
+		var patches int
+		fakeClient := fake.NewSimpleClientset(namespace)
+		fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			patches++
+			return true, nil, conflict
+		})
+
+		controller := &PodSecurityReadinessController{
+			kubeClient:      fakeClient,
+			operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			clock:           clock.RealClock{},
+			warningsHandler: &warningsHandler{},
+			dryRunVerified:  true,
+		}
+
+		_, err := controller.evaluateNamespaceViolation(context.Background(), namespace)
+		if !errors.Is(err, errApplyConflict) {
+			t.Errorf("expected an apply conflict error, got %v", err)
+		}
+
+		patches = 0
+		syncCtx := factory.NewSyncContext("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+		if err := controller.sync(context.Background(), syncCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if patches != retry.DefaultBackoff.Steps {
+			t.Errorf("expected the conflict to be retried %d times, got %d dry runs", retry.DefaultBackoff.Steps, patches)
+		}
+		conditions := controller.snapshot()
+		if !reflect.DeepEqual(conditions.inconclusiveNamespaces, []string{"test-ns"}) {
+			t.Errorf("expected the namespace to be inconclusive, got %v", conditions.inconclusiveNamespaces)
+		}
+		if len(conditions.violatingNamespaces()) != 0 {
+			t.Errorf("expected no violating namespaces, got %v", conditions.violatingNamespaces())
+		}
+	})
+}
+
+func TestNormalizedLevels(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		annotations map[string]string
+		labels      map[string]string
+		expected    string
+	}{
+		{
+			name:        "padded mixed-case annotation",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: " Restricted "},
+			expected:    "restricted",
+		},
+		{
+			name:     "padded strictest label",
+			labels:   map[string]string{psapi.WarnLevelLabel: "Restricted ", psapi.AuditLevelLabel: "baseline"},
+			expected: "restricted",
+		},
+		{
+			name:     "upper-case labels",
+			labels:   map[string]string{psapi.WarnLevelLabel: "PRIVILEGED", psapi.AuditLevelLabel: "BASELINE"},
+			expected: "baseline",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := applyconfiguration.Namespace("test-ns").
+				WithAnnotations(tt.annotations).
+				WithLabels(tt.labels)
+
+			level, err := determineEnforceLabelForNamespace(ns, AlertLabelPreferenceStrictest)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if level != tt.expected {
+				t.Errorf("expected level %q, got %q", tt.expected, level)
+			}
+		})
+	}
+
+	t.Run("conflicting padded alert levels", func(t *testing.T) {
+		ns := applyconfiguration.Namespace("test-ns").WithLabels(map[string]string{
+			psapi.WarnLevelLabel:  " Restricted",
+			psapi.AuditLevelLabel: "Privileged ",
+		})
+
+		if _, _, ok := conflictingAlertLevels(ns); !ok {
+			t.Error("expected the alert levels to conflict")
+		}
+	})
+}
+
+func TestVersionedAlertLevels(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		labels     map[string]string
+		preference AlertLabelPreference
+		expected   string
+	}{
+		{
+			name:     "versioned strictest label",
+			labels:   map[string]string{psapi.WarnLevelLabel: "restricted:v1.24", psapi.AuditLevelLabel: "baseline"},
+			expected: "restricted",
+		},
+		{
+			name:     "latest version",
+			labels:   map[string]string{psapi.WarnLevelLabel: "privileged", psapi.AuditLevelLabel: "Baseline:latest"},
+			expected: "baseline",
+		},
+		{
+			name:     "invalid version",
+			labels:   map[string]string{psapi.WarnLevelLabel: "restricted:unknown", psapi.AuditLevelLabel: "baseline"},
+			expected: "restricted",
+		},
+		{
+			name:     "invalid level with version",
+			labels:   map[string]string{psapi.WarnLevelLabel: "unknown:v1.24", psapi.AuditLevelLabel: "baseline"},
+			expected: "baseline",
+		},
+		{
+			name:       "versioned preferred label",
+			labels:     map[string]string{psapi.WarnLevelLabel: "baseline:v1.30", psapi.AuditLevelLabel: "restricted"},
+			preference: AlertLabelPreferenceWarn,
+			expected:   "baseline",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := applyconfiguration.Namespace("test-ns").WithLabels(tt.labels)
+
+			level, err := determineEnforceLabelForNamespace(ns, tt.preference)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if level != tt.expected {
+				t.Errorf("expected level %q, got %q", tt.expected, level)
+			}
+		})
+	}
+
+	t.Run("conflicting versioned alert levels", func(t *testing.T) {
+		ns := applyconfiguration.Namespace("test-ns").WithLabels(map[string]string{
+			psapi.WarnLevelLabel:  "restricted:v1.24",
+			psapi.AuditLevelLabel: "privileged:latest",
+		})
+
+		if _, _, ok := conflictingAlertLevels(ns); !ok {
+			t.Error("expected the alert levels to conflict")
+		}
+	})
+}
+
+func TestLatestVersion(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		labels   map[string]string
+		expected psapi.Version
+	}{
+		{
+			name:     "no version label",
+			expected: psapi.LatestVersion(),
+		},
+		{
+			name:     "latest",
+			labels:   map[string]string{psapi.EnforceVersionLabel: "latest"},
+			expected: psapi.LatestVersion(),
+		},
+		{
+			name:     "mixed case latest with whitespace",
+			labels:   map[string]string{psapi.EnforceVersionLabel: " LATEST "},
+			expected: psapi.LatestVersion(),
+		},
+		{
+			name:     "minor version",
+			labels:   map[string]string{psapi.EnforceVersionLabel: "v1.29"},
+			expected: psapi.MajorMinorVersion(1, 29),
+		},
+		{
+			name:     "invalid version",
+			labels:   map[string]string{psapi.EnforceVersionLabel: "newest"},
+			expected: psapi.LatestVersion(),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns", Labels: tt.labels}}
+
+			if version := enforceVersionForNamespace(ns); version != tt.expected {
+				t.Errorf("expected version %v, got %v", tt.expected, version)
+			}
+		})
+	}
+
+	for _, tt := range []struct {
+		name        string
+		annotations map[string]string
+		labels      map[string]string
+
+		expected      string
+		expectedError error
+	}{
+		{
+			// The annotation only ever holds a level.
+			name:          "annotation with latest version",
+			annotations:   map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted:latest"},
+			expectedError: ErrUnknownLevel,
+		},
+		{
+			name:     "alert label with mixed case latest version",
+			labels:   map[string]string{psapi.WarnLevelLabel: "restricted:LATEST"},
+			expected: "restricted",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := applyconfiguration.Namespace("test-ns").WithAnnotations(tt.annotations).WithLabels(tt.labels)
+
+			level, err := determineEnforceLabelForNamespace(ns, AlertLabelPreferenceStrictest)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if level != tt.expected {
+				t.Errorf("expected level %q, got %q", tt.expected, level)
+			}
+		})
+	}
+}
+
+func TestAlertLabelPreference(t *testing.T) {
+	bothLabels := map[string]string{
+		psapi.WarnLevelLabel:  "restricted",
+		psapi.AuditLevelLabel: "baseline",
+	}
+
+	for _, tt := range []struct {
+		name        string
+		preference  AlertLabelPreference
+		annotations map[string]string
+		labels      map[string]string
+
+		expected      string
+		expectedError error
+	}{
+		{
+			name:       "strictest of both",
+			preference: AlertLabelPreferenceStrictest,
+			labels:     bothLabels,
+			expected:   "restricted",
+		},
+		{
+			name:       "prefer audit",
+			preference: AlertLabelPreferenceAudit,
+			labels:     bothLabels,
+			expected:   "baseline",
+		},
+		{
+			name:       "prefer audit without audit label",
+			preference: AlertLabelPreferenceAudit,
+			labels:     map[string]string{psapi.WarnLevelLabel: "restricted"},
+			expected:   "restricted",
+		},
+		{
+			name:       "prefer audit with invalid audit label",
+			preference: AlertLabelPreferenceAudit,
+			labels:     map[string]string{psapi.WarnLevelLabel: "privileged", psapi.AuditLevelLabel: "unknown"},
+			expected:   "privileged",
+		},
+		{
+			name:       "prefer warn",
+			preference: AlertLabelPreferenceWarn,
+			labels:     map[string]string{psapi.WarnLevelLabel: "baseline", psapi.AuditLevelLabel: "restricted"},
+			expected:   "baseline",
+		},
+		{
+			name:       "prefer warn without warn label",
+			preference: AlertLabelPreferenceWarn,
+			labels:     map[string]string{psapi.AuditLevelLabel: "baseline"},
+			expected:   "baseline",
+		},
+		{
+			name:       "audit only",
+			preference: AlertLabelPreferenceAuditOnly,
+			labels:     bothLabels,
+			expected:   "baseline",
+		},
+		{
+			name:          "audit only without audit label",
+			preference:    AlertLabelPreferenceAuditOnly,
+			labels:        map[string]string{psapi.WarnLevelLabel: "restricted"},
+			expectedError: ErrNoLabels,
+		},
+		{
+			name:       "warn only",
+			preference: AlertLabelPreferenceWarnOnly,
+			labels:     map[string]string{psapi.WarnLevelLabel: "privileged", psapi.AuditLevelLabel: "restricted"},
+			expected:   "privileged",
+		},
+		{
+			name:          "warn only without warn label",
+			preference:    AlertLabelPreferenceWarnOnly,
+			labels:        map[string]string{psapi.AuditLevelLabel: "restricted"},
+			expectedError: ErrNoLabels,
+		},
+		{
+			name:        "annotation takes precedence",
+			preference:  AlertLabelPreferenceAuditOnly,
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "privileged"},
+			labels:      bothLabels,
+			expected:    "privileged",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := applyconfiguration.Namespace("test-ns").
+				WithAnnotations(tt.annotations).
+				WithLabels(tt.labels)
+
+			level, err := determineEnforceLabelForNamespace(ns, tt.preference)
+			if !errors.Is(err, tt.expectedError) {
+				t.Fatalf("expected error %v, got %v", tt.expectedError, err)
+			}
+			if level != tt.expected {
+				t.Errorf("expected level %q, got %q", tt.expected, level)
+			}
+		})
+	}
+}
+
+func TestEnforceLabelErrors(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		annotations map[string]string
+		labels      map[string]string
+
+		expectError error
+	}{
+		{
+			name:        "no labels",
+			expectError: ErrNoLabels,
+		},
+		{
+			name:        "unrelated labels",
+			labels:      map[string]string{psapi.EnforceVersionLabel: "latest"},
+			expectError: ErrNoLabels,
+		},
+		{
+			name:        "unknown annotation level",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricetd"},
+			expectError: ErrUnknownLevel,
+		},
+		{
+			name:        "unknown versioned annotation level",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricetd:v1.25"},
+			expectError: ErrUnknownLevel,
+		},
+		{
+			name:        "empty annotation",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: ""},
+			expectError: ErrUnknownLevel,
+		},
+		{
+			name:        "all labels invalid",
+			labels:      map[string]string{psapi.WarnLevelLabel: "restricetd", psapi.AuditLevelLabel: "garbage"},
+			expectError: ErrUnknownLevel,
+		},
+		{
+			name:        "invalid preferred label only",
+			labels:      map[string]string{psapi.WarnLevelLabel: ""},
+			expectError: ErrUnknownLevel,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ns := applyconfiguration.Namespace("test-ns").
+				WithAnnotations(tt.annotations).
+				WithLabels(tt.labels)
+
+			_, err := determineEnforceLabelForNamespace(ns, AlertLabelPreferenceStrictest)
+			if !errors.Is(err, tt.expectError) {
+				t.Errorf("expected error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+
+	t.Run("unknown evaluated level", func(t *testing.T) {
+		controller := &PodSecurityReadinessController{kubeClient: fake.NewSimpleClientset()}
+
+		_, err := controller.userViolationFamilies(context.TODO(), &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-ns"}}, "unknown")
+		if !errors.Is(err, ErrUnknownLevel) {
+			t.Errorf("expected error %v, got %v", ErrUnknownLevel, err)
+		}
+	})
+}
+
+func TestLevelBounds(t *testing.T) {
+	for _, tt := range []struct {
+		name                string
+		minimumLevel        psapi.Level
+		maximumLevel        psapi.Level
+		annotations         map[string]string
+		labels              map[string]string
+		clusterDefaultLevel string
+
+		expected    string
+		expectError error
+	}{
+		{
+			name:        "privileged annotation without minimum level",
+			annotations: map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "privileged"},
+			expected:    "privileged",
+		},
+		{
+			name:         "privileged annotation with baseline minimum level",
+			minimumLevel: psapi.LevelBaseline,
+			annotations:  map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "privileged"},
+			expected:     "baseline",
+		},
+		{
+			name:         "privileged alert labels with baseline minimum level",
+			minimumLevel: psapi.LevelBaseline,
+			labels:       map[string]string{psapi.WarnLevelLabel: "privileged", psapi.AuditLevelLabel: "privileged"},
+			expected:     "baseline",
+		},
+		{
+			name:         "restricted annotation with baseline minimum level",
+			minimumLevel: psapi.LevelBaseline,
+			annotations:  map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			expected:     "restricted",
+		},
+		{
+			name:                "privileged cluster default with baseline minimum level",
+			minimumLevel:        psapi.LevelBaseline,
+			clusterDefaultLevel: "privileged",
+			expected:            "baseline",
+		},
+		{
+			name:         "restricted annotation with baseline maximum level",
+			maximumLevel: psapi.LevelBaseline,
+			annotations:  map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			expected:     "baseline",
+		},
+		{
+			name:         "restricted alert labels with baseline maximum level",
+			maximumLevel: psapi.LevelBaseline,
+			labels:       map[string]string{psapi.WarnLevelLabel: "restricted", psapi.AuditLevelLabel: "baseline"},
+			expected:     "baseline",
+		},
+		{
+			name:         "privileged annotation with baseline maximum level",
+			maximumLevel: psapi.LevelBaseline,
+			annotations:  map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "privileged"},
+			expected:     "privileged",
+		},
+		{
+			name:         "restricted annotation with baseline minimum and maximum level",
+			minimumLevel: psapi.LevelBaseline,
+			maximumLevel: psapi.LevelBaseline,
+			annotations:  map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "restricted"},
+			expected:     "baseline",
+		},
+		{
+			name:         "invalid annotation with baseline minimum level",
+			minimumLevel: psapi.LevelBaseline,
+			annotations:  map[string]string{securityv1.MinimallySufficientPodSecurityStandard: "unknown"},
+			expectError:  ErrUnknownLevel,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &PodSecurityReadinessController{
+				config:                     Config{Levels: LevelsConfig{Minimum: tt.minimumLevel, Maximum: tt.maximumLevel}},
+				clusterDefaultEnforceLevel: tt.clusterDefaultLevel,
+			}
+
+			ns := &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:          "test-ns",
+					Annotations:   tt.annotations,
+					Labels:        tt.labels,
+					ManagedFields: managedFields,
+				},
+			}
+
+			_, resolved, err := controller.resolveEnforceLevel(ns)
+			if !errors.Is(err, tt.expectError) {
+				t.Fatalf("expected error %v, got %v", tt.expectError, err)
+			}
+			if resolved.level != tt.expected {
+				t.Errorf("expected level %q, got %q", tt.expected, resolved.level)
+			}
+		})
+	}
+}
+
+func TestIsPodPhaseEvaluated(t *testing.T) {
+	allPhases := []corev1.PodPhase{"", corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed, corev1.PodUnknown}
+
+	for _, tt := range []struct {
+		name string
+		pods PodsConfig
+
+		expected sets.Set[corev1.PodPhase]
+	}{
+		{
+			name:     "default",
+			expected: sets.New[corev1.PodPhase]("", corev1.PodPending, corev1.PodRunning),
+		},
+		{
+			name:     "terminated pod evaluation",
+			pods:     PodsConfig{IncludeTerminated: true},
+			expected: sets.New[corev1.PodPhase]("", corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed),
+		},
+		{
+			name:     "running only",
+			pods:     PodsConfig{Phases: []corev1.PodPhase{corev1.PodRunning}},
+			expected: sets.New(corev1.PodRunning),
+		},
+		{
+			name:     "pending only",
+			pods:     PodsConfig{Phases: []corev1.PodPhase{corev1.PodPending}},
+			expected: sets.New[corev1.PodPhase]("", corev1.PodPending),
+		},
+		{
+			name:     "running only with terminated pod evaluation",
+			pods:     PodsConfig{Phases: []corev1.PodPhase{corev1.PodRunning}, IncludeTerminated: true},
+			expected: sets.New(corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed),
+		},
+		{
+			name:     "all phases",
+			pods:     PodsConfig{Phases: []corev1.PodPhase{corev1.PodPending, corev1.PodRunning, corev1.PodSucceeded, corev1.PodFailed, corev1.PodUnknown}},
+			expected: sets.New(allPhases...),
+		},
+		{
+			name:     "empty phases fall back to the default",
+			pods:     PodsConfig{Phases: []corev1.PodPhase{}},
+			expected: sets.New[corev1.PodPhase]("", corev1.PodPending, corev1.PodRunning),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &PodSecurityReadinessController{config: Config{Pods: tt.pods}}
+
+			for _, phase := range allPhases {
+				pod := &corev1.Pod{Status: corev1.PodStatus{Phase: phase}}
+				if evaluated := controller.isPodPhaseEvaluated(pod); evaluated != tt.expected.Has(phase) {
+					t.Errorf("expected phase %q evaluated %v, got %v", phase, tt.expected.Has(phase), evaluated)
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluatedPodPhasesValidation(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		phases []corev1.PodPhase
+
+		expectError bool
+	}{
+		{
+			name:   "known phases",
+			phases: []corev1.PodPhase{corev1.PodRunning, corev1.PodUnknown},
+		},
+		{
+			name:        "unknown phase",
+			phases:      []corev1.PodPhase{corev1.PodRunning, "Completed"},
+			expectError: true,
+		},
+		{
+			name:        "lower case phase",
+			phases:      []corev1.PodPhase{"running"},
+			expectError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewPodSecurityReadinessController(
+				&rest.Config{Host: "https://localhost:6443"},
+				v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				events.NewInMemoryRecorder("test", clock.RealClock{}),
+				NewWarningsHandler(),
+				Config{Pods: PodsConfig{Phases: tt.phases}},
+			)
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestLevelBoundsValidation(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		levels LevelsConfig
+
+		expectError bool
+	}{
+		{
+			name:   "equal bounds",
+			levels: LevelsConfig{Minimum: psapi.LevelBaseline, Maximum: psapi.LevelBaseline},
+		},
+		{
+			name:        "minimum stricter than maximum",
+			levels:      LevelsConfig{Minimum: psapi.LevelRestricted, Maximum: psapi.LevelBaseline},
+			expectError: true,
+		},
+		{
+			name:        "invalid maximum",
+			levels:      LevelsConfig{Maximum: "unknown"},
+			expectError: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := NewPodSecurityReadinessController(
+				&rest.Config{Host: "https://localhost:6443"},
+				v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				events.NewInMemoryRecorder("test", clock.RealClock{}),
+				NewWarningsHandler(),
+				Config{Levels: tt.levels},
+			)
+			if (err != nil) != tt.expectError {
+				t.Errorf("expected error %v, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestWarningsDrainedOnError(t *testing.T) {
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		handler.HandleWarningHeader(299, "", `existing pods in namespace "test-ns" violate the new PodSecurity enforce level "restricted:latest"`)
+		return true, nil, fmt.Errorf("connection reset")
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		warningsHandler: handler,
+	}
+
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Labels: map[string]string{
+				psapi.WarnLevelLabel: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	}
+	if _, err := controller.evaluateNamespaceViolation(context.Background(), namespace); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(handler.warnings) != 0 {
+		t.Errorf("expected the warnings to be drained, got %v", handler.warnings)
+	}
+}
+
+func TestStaleWarningsDiscarded(t *testing.T) {
+	handler := &warningsHandler{}
+	fakeClient := fake.NewSimpleClientset()
+	fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		return true, nil, nil
+	})
+
+	controller := &PodSecurityReadinessController{
+		kubeClient:      fakeClient,
+		warningsHandler: handler,
+	}
+
+	// A warning received outside of an evaluation, e.g. by a request of
+	// another component sharing the handler, must not be attributed to the
+	// next namespace.
+	handler.HandleWarningHeader(299, "", `existing pods in namespace "other-ns" violate the new PodSecurity enforce level "restricted:latest"`)
+
+	warnings, err := controller.dryRunEnforceLevel(context.Background(), "test-ns", "restricted")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(warnings) != 0 {
+		t.Errorf("expected no warnings, got %v", warnings)
+	}
+}
+
+func TestCustomSyncerControllerName(t *testing.T) {
+	customSyncerName := "custom-label-syncer"
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: []metav1.ManagedFieldsEntry{
+				{
+					Manager:   customSyncerName,
+					Operation: metav1.ManagedFieldsOperationApply,
+					FieldsV1: &metav1.FieldsV1{
+						Raw: []byte(fmt.Sprintf(`{"f:metadata":{"f:annotations":{"f:%s":{}}}}`, securityv1.MinimallySufficientPodSecurityStandard)),
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name        string
+		syncerName  string
+		expectError bool
+	}{
+		{
+			name:        "default syncer name doesn't own the annotation",
+			syncerName:  defaultSyncerControllerName,
+			expectError: true,
+		},
+		{
+			name:        "custom syncer name owns the annotation",
+			syncerName:  customSyncerName,
+			expectError: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := &PodSecurityReadinessController{
+				kubeClient:      &mockKubeClientWithResponse{},
+				warningsHandler: &warningsHandler{},
+				config:          Config{Levels: LevelsConfig{SyncerControllerName: tt.syncerName}},
+			}
+
+			_, err := controller.evaluateNamespaceViolation(context.Background(), namespace)
+			if (err != nil) != tt.expectError {
+				t.Errorf("evaluateNamespaceViolation() error = %v, expectError %v", err, tt.expectError)
+			}
+		})
+	}
+
+	t.Run("empty syncer name falls back to the default", func(t *testing.T) {
+		controller, err := newPodSecurityReadinessController(
+			v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			events.NewInMemoryRecorder("test", clock.RealClock{}),
+			NewWarningsHandler(),
+			Config{Levels: LevelsConfig{SyncerControllerName: ""}},
+		)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if name := controller.config.Levels.syncerControllerName(); name != defaultSyncerControllerName {
+			t.Errorf("expected the syncer controller name %q, got %q", defaultSyncerControllerName, name)
+		}
+	})
+}
+
+func TestAchievableLevel(t *testing.T) {
+	for _, tt := range []struct {
+		name           string
+		violatedLevel  string
+		violatedLevels []string
+
+		expectedLevel  string
+		expectedProbes []string
+	}{
+		{
+			name:           "restricted violated, baseline satisfied",
+			violatedLevel:  "restricted",
+			violatedLevels: []string{"restricted"},
+			expectedLevel:  "baseline",
+			expectedProbes: []string{"baseline"},
+		},
+		{
+			name:           "restricted and baseline violated",
+			violatedLevel:  "restricted",
+			violatedLevels: []string{"restricted", "baseline"},
+			expectedLevel:  "privileged",
+			expectedProbes: []string{"baseline"},
+		},
+		{
+			name:           "baseline violated",
+			violatedLevel:  "baseline",
+			violatedLevels: []string{"restricted", "baseline"},
+			expectedLevel:  "privileged",
+			expectedProbes: nil,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &warningsHandler{}
+			var probes []string
+			fakeClient := fake.NewSimpleClientset()
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				ns := &corev1.Namespace{}
+				if err := json.Unmarshal(action.(clienttesting.PatchAction).GetPatch(), ns); err != nil {
+					return true, nil, err
+				}
+
+				level := ns.Labels[psapi.EnforceLevelLabel]
+				probes = append(probes, level)
+				if slices.Contains(tt.violatedLevels, level) {
+					handler.HandleWarningHeader(299, "", fmt.Sprintf("existing pods in namespace %q violate the new PodSecurity enforce level \"%s:latest\"", ns.Name, level))
+				}
+
+				return true, nil, nil
+			})
+
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				warningsHandler: handler,
+			}
+
+			level, err := controller.achievableLevel(context.TODO(), &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ns"},
+			}, tt.violatedLevel)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if level != tt.expectedLevel {
+				t.Errorf("expected achievable level %q, got %q", tt.expectedLevel, level)
+			}
+			if !slices.Equal(probes, tt.expectedProbes) {
+				t.Errorf("expected probed levels %v, got %v", tt.expectedProbes, probes)
+			}
+		})
+	}
+}
+
+func TestCompletedJobLookups(t *testing.T) {
+	privileged := true
+	newJob := func(name string) *batchv1.Job {
+		return &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"}}
+	}
+	newJobPod := func(name string, job *batchv1.Job, violating bool) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				Namespace:       "test-ns",
+				Annotations:     map[string]string{securityv1.ValidatedSCCSubjectTypeAnnotation: "user"},
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(job, batchv1.SchemeGroupVersion.WithKind("Job"))},
+			},
+			Spec:   corev1.PodSpec{Containers: []corev1.Container{{Name: "job"}}},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		}
+		if violating {
+			pod.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{Privileged: &privileged}
+		}
+		return pod
+	}
+
+	violatingJob, compliantJob := newJob("violating"), newJob("compliant")
+	fakeClient := fake.NewSimpleClientset(
+		violatingJob,
+		compliantJob,
+		newJobPod("violating-1", violatingJob, true),
+		newJobPod("violating-2", violatingJob, true),
+		newJobPod("violating-3", violatingJob, true),
+		newJobPod("compliant-1", compliantJob, false),
+	)
+	lookups := map[string]int{}
+	fakeClient.PrependReactor("get", "jobs", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+		lookups[action.(clienttesting.GetAction).GetName()]++
+		return false, nil, nil
+	})
+
+	psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+	if err != nil {
+		t.Fatal(err)
+	}
+	controller := &PodSecurityReadinessController{
+		kubeClient:   fakeClient,
+		psaEvaluator: psaEvaluator,
+		config:       Config{Pods: PodsConfig{SkipCompletedJobs: true}},
+	}
+
+	families, err := controller.userViolationFamilies(context.TODO(), &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-ns"},
+	}, "baseline")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(families) == 0 {
+		t.Errorf("expected the pods of the running Job to be violating")
+	}
+	if expected := map[string]int{"violating": 1}; !reflect.DeepEqual(lookups, expected) {
+		t.Errorf("expected Job lookups %v, got %v", expected, lookups)
+	}
+}
+
+func TestViolatingWorkloadKinds(t *testing.T) {
+	privileged := true
+	privilegedSpec := corev1.PodSpec{
+		Containers: []corev1.Container{{
+			Name:            "privileged",
+			SecurityContext: &corev1.SecurityContext{Privileged: &privileged},
+		}},
+	}
+	compliantSpec := corev1.PodSpec{
+		Containers: []corev1.Container{{Name: "compliant"}},
+	}
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "deployment", Namespace: "test-ns"},
+	}
+	replicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "deployment-1234",
+			Namespace:       "test-ns",
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(deployment, appsv1.SchemeGroupVersion.WithKind("Deployment"))},
+		},
+	}
+	orphanedReplicaSet := &appsv1.ReplicaSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "orphaned", Namespace: "test-ns"},
+	}
+	statefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "statefulset", Namespace: "test-ns"},
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "daemonset", Namespace: "test-ns"},
+	}
+
+	newPod := func(name string, spec corev1.PodSpec, owner metav1.Object, kind string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "test-ns"},
+			Spec:       spec,
+		}
+		if owner != nil {
+			pod.OwnerReferences = []metav1.OwnerReference{*metav1.NewControllerRef(owner, appsv1.SchemeGroupVersion.WithKind(kind))}
+		}
+		return pod
+	}
+
+	for _, tt := range []struct {
+		name     string
+		objects  []runtime.Object
+		label    string
+		expected []string
+		// expectedLookups is the number of ReplicaSets that are looked up.
+		expectedLookups int
+	}{
+		{
+			name: "mixed owner kinds",
+			objects: []runtime.Object{
+				replicaSet,
+				orphanedReplicaSet,
+				newPod("deployment-pod", privilegedSpec, replicaSet, "ReplicaSet"),
+				newPod("replicaset-pod", privilegedSpec, orphanedReplicaSet, "ReplicaSet"),
+				newPod("statefulset-pod", privilegedSpec, statefulSet, "StatefulSet"),
+				newPod("bare-pod", privilegedSpec, nil, ""),
+				newPod("daemonset-pod", compliantSpec, daemonSet, "DaemonSet"),
+			},
+			label:           "baseline",
+			expected:        []string{"Deployment", "Pod", "ReplicaSet", "StatefulSet"},
+			expectedLookups: 2,
+		},
+		{
+			name: "pods of the same workload",
+			objects: []runtime.Object{
+				replicaSet,
+				newPod("deployment-pod-1", privilegedSpec, replicaSet, "ReplicaSet"),
+				newPod("deployment-pod-2", privilegedSpec, replicaSet, "ReplicaSet"),
+				newPod("deployment-pod-3", privilegedSpec, replicaSet, "ReplicaSet"),
+			},
+			label:           "baseline",
+			expected:        []string{"Deployment"},
+			expectedLookups: 1,
+		},
+		{
+			name: "only compliant pods",
+			objects: []runtime.Object{
+				newPod("daemonset-pod", compliantSpec, daemonSet, "DaemonSet"),
+			},
+			label:    "baseline",
+			expected: []string{},
+		},
+		{
+			name: "privileged level",
+			objects: []runtime.Object{
+				newPod("bare-pod", privilegedSpec, nil, ""),
+			},
+			label:    "privileged",
+			expected: nil,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			fakeClient := fake.NewSimpleClientset(tt.objects...)
+			lookups := 0
+			fakeClient.PrependReactor("get", "replicasets", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				lookups++
+				return false, nil, nil
+			})
+			controller := &PodSecurityReadinessController{
+				kubeClient:   fakeClient,
+				psaEvaluator: psaEvaluator,
+			}
+
+			kinds, err := controller.violatingWorkloadKinds(context.TODO(), &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-ns"},
+			}, tt.label)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(kinds, tt.expected) {
+				t.Errorf("expected workload kinds %v, got %v", tt.expected, kinds)
+			}
+			if lookups != tt.expectedLookups {
+				t.Errorf("expected %d ReplicaSet lookups, got %d", tt.expectedLookups, lookups)
+			}
+		})
+	}
+}
+
+func TestCheckFamilies(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	}
+	// restrictedPod satisfies the restricted level, the test cases each
+	// break one family of checks.
+	restrictedPod := func(name string, mutate func(spec *corev1.PodSpec)) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "test-ns",
+				Annotations: map[string]string{
+					securityv1.ValidatedSCCSubjectTypeAnnotation: "user",
+				},
+			},
+			Spec: corev1.PodSpec{
+				SecurityContext: &corev1.PodSecurityContext{
+					RunAsNonRoot:   ptr.To(true),
+					SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+				},
+				Containers: []corev1.Container{{
+					Name: "restricted",
+					SecurityContext: &corev1.SecurityContext{
+						AllowPrivilegeEscalation: ptr.To(false),
+						Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+					},
+				}},
+			},
+		}
+		mutate(&pod.Spec)
+		return pod
+	}
+	hostPath := func(spec *corev1.PodSpec) {
+		spec.Volumes = append(spec.Volumes, corev1.Volume{
+			Name:         "host",
+			VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/var/log"}},
+		})
+	}
+	capabilities := func(spec *corev1.PodSpec) {
+		spec.Containers[0].SecurityContext.Capabilities.Add = []corev1.Capability{"NET_ADMIN"}
+	}
+	runAsRoot := func(spec *corev1.PodSpec) {
+		spec.SecurityContext.RunAsNonRoot = nil
+	}
+	seccomp := func(spec *corev1.PodSpec) {
+		spec.SecurityContext.SeccompProfile = nil
+	}
+	privileged := func(spec *corev1.PodSpec) {
+		spec.Containers[0].SecurityContext.Privileged = ptr.To(true)
+	}
+
+	for _, tt := range []struct {
+		name string
+		pods []runtime.Object
+
+		expectedFamily     string
+		expectedVolumeOnly bool
+	}{
+		{
+			name:               "hostPath volume",
+			pods:               []runtime.Object{restrictedPod("pod", hostPath)},
+			expectedFamily:     checkFamilyVolumes,
+			expectedVolumeOnly: true,
+		},
+		{
+			name:           "capabilities",
+			pods:           []runtime.Object{restrictedPod("pod", capabilities)},
+			expectedFamily: checkFamilyCapabilities,
+		},
+		{
+			name:           "runAsNonRoot",
+			pods:           []runtime.Object{restrictedPod("pod", runAsRoot)},
+			expectedFamily: checkFamilyRunAsNonRoot,
+		},
+		{
+			name:           "seccomp",
+			pods:           []runtime.Object{restrictedPod("pod", seccomp)},
+			expectedFamily: checkFamilySeccomp,
+		},
+		{
+			name:           "privileged",
+			pods:           []runtime.Object{restrictedPod("pod", privileged)},
+			expectedFamily: checkFamilyOther,
+		},
+		{
+			name: "most common family",
+			pods: []runtime.Object{
+				restrictedPod("pod-a", hostPath),
+				restrictedPod("pod-b", seccomp),
+				restrictedPod("pod-c", seccomp),
+			},
+			expectedFamily: checkFamilySeccomp,
+		},
+		{
+			name: "tie",
+			pods: []runtime.Object{
+				restrictedPod("pod-a", seccomp),
+				restrictedPod("pod-b", capabilities),
+			},
+			expectedFamily: checkFamilyCapabilities,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &warningsHandler{}
+			fakeClient := fake.NewSimpleClientset(tt.pods...)
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				handleWarnings(handler, []string{"existing pods violate the new PodSecurity enforce level"})
+				return true, nil, nil
+			})
+
+			psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				psaEvaluator:    psaEvaluator,
+				warningsHandler: handler,
+			}
+
+			result, err := controller.evaluateNamespaceViolation(context.Background(), namespace)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.UserWorkload {
+				t.Fatalf("expected a user workload violation, got %+v", result)
+			}
+			if result.CheckFamily != tt.expectedFamily {
+				t.Errorf("expected check family %q, got %q", tt.expectedFamily, result.CheckFamily)
+			}
+			if result.VolumeOnly != tt.expectedVolumeOnly {
+				t.Errorf("expected volume only %v, got %v", tt.expectedVolumeOnly, result.VolumeOnly)
+			}
+		})
+	}
+}
+
+func TestPodTemplateEvaluation(t *testing.T) {
+	namespace := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "test-ns",
+			Annotations: map[string]string{
+				securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+			},
+			ManagedFields: managedFields,
+		},
+	}
+	privilegedTemplate := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name:            "privileged",
+				SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+			}},
+		},
+	}
+	restrictedTemplate := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			SecurityContext: &corev1.PodSecurityContext{
+				RunAsNonRoot:   ptr.To(true),
+				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+			},
+			Containers: []corev1.Container{{
+				Name: "restricted",
+				SecurityContext: &corev1.SecurityContext{
+					AllowPrivilegeEscalation: ptr.To(false),
+					Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+				},
+			}},
+		},
+	}
+	scaledDownDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "scaled-down", Namespace: "test-ns"},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](0),
+			Template: privilegedTemplate,
+		},
+	}
+	restrictedStatefulSet := &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "restricted", Namespace: "test-ns"},
+		Spec:       appsv1.StatefulSetSpec{Template: restrictedTemplate},
+	}
+	privilegedDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "privileged", Namespace: "test-ns"},
+		Spec:       appsv1.DaemonSetSpec{Template: privilegedTemplate},
+	}
+
+	for _, tt := range []struct {
+		name              string
+		objects           []runtime.Object
+		evaluateTemplates bool
+
+		expected EvaluationResult
+	}{
+		{
+			name:     "scaled down deployment without template evaluation",
+			objects:  []runtime.Object{scaledDownDeployment},
+			expected: EvaluationResult{Level: "restricted"},
+		},
+		{
+			name:              "scaled down deployment",
+			objects:           []runtime.Object{scaledDownDeployment},
+			evaluateTemplates: true,
+			expected: EvaluationResult{
+				Violating:    true,
+				Inconclusive: true,
+				Level:        "restricted",
+				Reason:       `pod template of Deployment scaled-down would violate the PodSecurity enforce level "restricted"`,
+			},
+		},
+		{
+			name:              "compliant statefulset",
+			objects:           []runtime.Object{restrictedStatefulSet},
+			evaluateTemplates: true,
+			expected:          EvaluationResult{Level: "restricted"},
+		},
+		{
+			name:              "violating daemonset",
+			objects:           []runtime.Object{restrictedStatefulSet, privilegedDaemonSet},
+			evaluateTemplates: true,
+			expected: EvaluationResult{
+				Violating:    true,
+				Inconclusive: true,
+				Level:        "restricted",
+				Reason:       `pod template of DaemonSet privileged would violate the PodSecurity enforce level "restricted"`,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset(tt.objects...)
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, nil, nil
+			})
+
+			psaEvaluator, err := policy.NewEvaluator(policy.DefaultChecks())
+			if err != nil {
+				t.Fatal(err)
+			}
+			controller := &PodSecurityReadinessController{
+				kubeClient:      fakeClient,
+				psaEvaluator:    psaEvaluator,
+				warningsHandler: &warningsHandler{},
+				config:          Config{Pods: PodsConfig{Templates: tt.evaluateTemplates}},
+			}
+
+			result, err := controller.evaluateNamespaceViolation(context.Background(), namespace)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected result %+v, got %+v", tt.expected, result)
+			}
+		})
+	}
+}
+
+// mockKubeClientWithResponse returns the error for every namespace Apply and
+// passes the warnings to the handler, if any.
+type mockKubeClientWithResponse struct {
+	kubernetes.Interface
+	error           error
+	warningsHandler rest.WarningHandler
+	warnings        []string
+}
+
+func (m *mockKubeClientWithResponse) CoreV1() typedcorev1.CoreV1Interface {
+	return &mockCoreV1WithResponse{client: m}
+}
+
+type mockCoreV1WithResponse struct {
+	typedcorev1.CoreV1Interface
+	client *mockKubeClientWithResponse
+}
+
+func (m *mockCoreV1WithResponse) Namespaces() typedcorev1.NamespaceInterface {
+	return &mockNamespaceInterfaceWithResponse{client: m.client}
+}
+
+func (m *mockCoreV1WithResponse) Pods(namespace string) typedcorev1.PodInterface {
+	return &mockPodInterfaceWithResponse{}
+}
+
+type mockPodInterfaceWithResponse struct {
+	typedcorev1.PodInterface
+}
+
+func (m *mockPodInterfaceWithResponse) List(ctx context.Context, opts metav1.ListOptions) (*corev1.PodList, error) {
+	return &corev1.PodList{}, nil
+}
+
+type mockNamespaceInterfaceWithResponse struct {
+	typedcorev1.NamespaceInterface
+	client *mockKubeClientWithResponse
+}
+
+func (m *mockNamespaceInterfaceWithResponse) Apply(ctx context.Context, nsApply *applyconfiguration.NamespaceApplyConfiguration, opts metav1.ApplyOptions) (*corev1.Namespace, error) {
+	if m.client.warningsHandler != nil {
+		handleWarnings(m.client.warningsHandler, m.client.warnings)
+	}
+	return nil, m.client.error
 }
@@ -1,14 +1,57 @@
 package podsecurityreadinesscontroller
 
-// warningsHandler collects the warnings and makes them available.
+import (
+	"regexp"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+)
+
+// maxBufferedWarnings bounds the warnings kept between two PopAll calls. Pod
+// security admission aggregates its warnings per namespace, so legitimate
+// responses stay far below it.
+const maxBufferedWarnings = 100
+
+// defaultViolationWarningPattern matches the warning pod security admission
+// returns when existing pods violate a new enforce level, e.g.
+// `existing pods in namespace "foo" violate the new PodSecurity enforce level "restricted:latest"`.
+const defaultViolationWarningPattern = `violate the new PodSecurity enforce level`
+
+var defaultViolationWarning = regexp.MustCompile(defaultViolationWarningPattern)
+
+// WarningsHandler receives the warnings returned by the apiserver for the
+// controller's requests and makes them available. Violations are detected
+// solely through these warnings, so substituting the handler drives the
+// detection.
+type WarningsHandler interface {
+	rest.WarningHandler
+
+	// PopAll returns the warnings received since the last call.
+	PopAll() []string
+}
+
+// NewWarningsHandler returns a handler that collects the warnings of the
+// client it is set as the rest.WarningHandler of.
+func NewWarningsHandler() WarningsHandler {
+	return &warningsHandler{}
+}
+
+// warningsHandler collects the warnings and makes them available. The client
+// calls HandleWarningHeader from the goroutines of its requests, so the
+// warnings are guarded by a lock.
 type warningsHandler struct {
+	lock     sync.Mutex
 	warnings []string
 }
 
 // HandleWarningHeader implements the WarningHandler interface. It stores the
 // warning headers.
 func (w *warningsHandler) HandleWarningHeader(code int, agent string, text string) {
-	if text == "" {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if text == "" || len(w.warnings) >= maxBufferedWarnings {
 		return
 	}
 
@@ -17,8 +60,41 @@ func (w *warningsHandler) HandleWarningHeader(code int, agent string, text strin
 
 // PopAll returns all warnings and clears the slice.
 func (w *warningsHandler) PopAll() []string {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
 	warnings := w.warnings
 	w.warnings = []string{}
 
 	return warnings
 }
+
+// uniqueWarnings returns the warnings without duplicates, keeping the order in
+// which they were first received.
+func uniqueWarnings(warnings []string) []string {
+	seen := sets.New[string]()
+	unique := make([]string, 0, len(warnings))
+	for _, warning := range warnings {
+		if seen.Has(warning) {
+			continue
+		}
+
+		seen.Insert(warning)
+		unique = append(unique, warning)
+	}
+
+	return unique
+}
+
+// violationWarnings returns the warnings if any of them matches the pattern,
+// and none otherwise. The warnings that don't match, e.g. the per-pod details
+// following the violation warning, are kept as they explain the violation.
+func violationWarnings(pattern *regexp.Regexp, warnings []string) []string {
+	for _, warning := range warnings {
+		if pattern.MatchString(warning) {
+			return warnings
+		}
+	}
+
+	return nil
+}
@@ -1,8 +1,26 @@
 package podsecurityreadinesscontroller
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	securityv1 "github.com/openshift/api/security/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
 )
 
 func TestWarningHandler(t *testing.T) {
@@ -19,3 +37,288 @@ func TestWarningHandler(t *testing.T) {
 		t.Error("Expected PopAll to return an empty slice")
 	}
 }
+
+func TestWarningHandlerLimit(t *testing.T) {
+	w := warningsHandler{}
+	for i := 0; i < maxBufferedWarnings+10; i++ {
+		w.HandleWarningHeader(299, "", fmt.Sprintf("warning %d", i))
+	}
+
+	warnings := w.PopAll()
+	if len(warnings) != maxBufferedWarnings {
+		t.Fatalf("expected %d warnings, got %d", maxBufferedWarnings, len(warnings))
+	}
+	if warnings[0] != "warning 0" {
+		t.Errorf("expected the oldest warnings to be kept, got %q", warnings[0])
+	}
+}
+
+func TestWarningHandlerConcurrency(t *testing.T) {
+	w := &warningsHandler{}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 10; j++ {
+				w.HandleWarningHeader(299, "", "warning")
+			}
+		}()
+	}
+
+	received := 0
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	for finished := false; !finished; {
+		select {
+		case <-done:
+			finished = true
+		default:
+		}
+		received += len(w.PopAll())
+	}
+
+	if received != 100 {
+		t.Errorf("expected 100 warnings, got %d", received)
+	}
+}
+
+func TestUniqueWarnings(t *testing.T) {
+	warnings := []string{
+		"existing pods in namespace \"ns\" violate the new PodSecurity enforce level \"restricted:latest\"",
+		"pod-a: allowPrivilegeEscalation != false",
+		"existing pods in namespace \"ns\" violate the new PodSecurity enforce level \"restricted:latest\"",
+		"pod-a: allowPrivilegeEscalation != false",
+		"pod-b: runAsNonRoot != true",
+	}
+	expected := []string{
+		"existing pods in namespace \"ns\" violate the new PodSecurity enforce level \"restricted:latest\"",
+		"pod-a: allowPrivilegeEscalation != false",
+		"pod-b: runAsNonRoot != true",
+	}
+
+	actual := uniqueWarnings(warnings)
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected unique warnings to be %q, got %q", expected, actual)
+	}
+}
+
+// scriptedWarningsHandler returns a fixed set of warnings on every PopAll,
+// independently of the warnings of the client.
+type scriptedWarningsHandler struct {
+	warnings []string
+}
+
+func (s *scriptedWarningsHandler) HandleWarningHeader(code int, agent string, text string) {}
+
+func (s *scriptedWarningsHandler) PopAll() []string {
+	return s.warnings
+}
+
+// countingWarningsHandler counts the warnings it receives from the client.
+type countingWarningsHandler struct {
+	scriptedWarningsHandler
+	received int
+}
+
+func (c *countingWarningsHandler) HandleWarningHeader(code int, agent string, text string) {
+	c.received++
+}
+
+func TestWarningsHandlerSubstitution(t *testing.T) {
+	t.Run("drives the violation detection", func(t *testing.T) {
+		fakeClient := fake.NewSimpleClientset()
+		fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+			return true, nil, nil
+		})
+
+		controller := &PodSecurityReadinessController{
+			kubeClient: fakeClient,
+			warningsHandler: &scriptedWarningsHandler{
+				warnings: []string{"existing pods in namespace \"test-ns\" violate the new PodSecurity enforce level \"restricted:latest\""},
+			},
+		}
+
+		result, err := controller.evaluateNamespaceViolation(context.TODO(), &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test-ns",
+				Annotations: map[string]string{
+					securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+				},
+				ManagedFields: managedFields,
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Violating {
+			t.Error("expected the scripted warnings to make the namespace violating")
+		}
+	})
+
+	t.Run("accepted by the constructor", func(t *testing.T) {
+		_, _, err := NewPodSecurityReadinessController(
+			&rest.Config{Host: "https://localhost:6443"},
+			v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			events.NewInMemoryRecorder("test", clock.RealClock{}),
+			&scriptedWarningsHandler{},
+			Config{},
+		)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("wired to the client", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Add("Warning", `299 - "substituted handler warning"`)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"kind":"NamespaceList","apiVersion":"v1","items":[]}`)
+		}))
+		defer server.Close()
+
+		handler := &countingWarningsHandler{}
+		kubeClient, err := newWarningAwareKubeClient(handler, &rest.Config{Host: server.URL}, newBackpressureLimiter(100, 100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := kubeClient.CoreV1().Namespaces().List(context.TODO(), metav1.ListOptions{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if handler.received != 1 {
+			t.Errorf("expected the substituted handler to receive 1 warning, got %d", handler.received)
+		}
+	})
+
+	t.Run("nil rejected by the constructor", func(t *testing.T) {
+		_, _, err := NewPodSecurityReadinessController(
+			&rest.Config{Host: "https://localhost:6443"},
+			v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			events.NewInMemoryRecorder("test", clock.RealClock{}),
+			nil,
+			Config{},
+		)
+		if err == nil {
+			t.Error("expected an error for a nil warnings handler")
+		}
+	})
+}
+
+func TestViolationWarningPattern(t *testing.T) {
+	const (
+		defaultWording = `existing pods in namespace "test-ns" violate the new PodSecurity enforce level "restricted:latest"`
+		customWording  = `namespace "test-ns": 2 pods would be rejected by the pod security policy "restricted"`
+		podDetails     = "pod-a: allowPrivilegeEscalation != false"
+		deprecation    = "spec.nodeSelector[beta.kubernetes.io/os]: deprecated since v1.14"
+	)
+
+	for _, tt := range []struct {
+		name            string
+		pattern         string
+		warnings        []string
+		expectViolating bool
+		expectReason    string
+		expectEvent     bool
+	}{
+		{
+			name:            "default pattern matches the default wording",
+			warnings:        []string{defaultWording, podDetails},
+			expectViolating: true,
+			expectReason:    defaultWording,
+		},
+		{
+			name:            "unrecognized warnings are treated as violations",
+			warnings:        []string{deprecation},
+			expectViolating: true,
+			expectReason:    deprecation,
+			expectEvent:     true,
+		},
+		{
+			name:            "default pattern doesn't recognize alternative wordings",
+			warnings:        []string{customWording},
+			expectViolating: true,
+			expectReason:    customWording,
+			expectEvent:     true,
+		},
+		{
+			name: "no warnings",
+		},
+		{
+			name:            "custom pattern matches an alternative wording",
+			pattern:         `would be rejected by the pod security policy`,
+			warnings:        []string{customWording, podDetails},
+			expectViolating: true,
+			expectReason:    customWording,
+		},
+		{
+			name:            "custom pattern replaces the default one",
+			pattern:         `would be rejected by the pod security policy`,
+			warnings:        []string{defaultWording},
+			expectViolating: true,
+			expectReason:    defaultWording,
+			expectEvent:     true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeClient := fake.NewSimpleClientset()
+			fakeClient.PrependReactor("patch", "namespaces", func(action clienttesting.Action) (handled bool, ret runtime.Object, err error) {
+				return true, nil, nil
+			})
+
+			recorder := events.NewInMemoryRecorder("test", clock.RealClock{})
+			controller, err := newPodSecurityReadinessController(
+				v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+				recorder,
+				&scriptedWarningsHandler{warnings: tt.warnings},
+				Config{Evaluation: EvaluationConfig{ViolationWarningPattern: tt.pattern}},
+			)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			controller.kubeClient = fakeClient
+
+			result, err := controller.evaluateNamespaceViolation(context.TODO(), &corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "test-ns",
+					Annotations: map[string]string{
+						securityv1.MinimallySufficientPodSecurityStandard: "restricted",
+					},
+					ManagedFields: managedFields,
+				},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Violating != tt.expectViolating {
+				t.Errorf("expected violating %v, got %v", tt.expectViolating, result.Violating)
+			}
+			if result.Reason != tt.expectReason {
+				t.Errorf("expected reason %q, got %q", tt.expectReason, result.Reason)
+			}
+			unrecognized := false
+			for _, event := range recorder.Events() {
+				unrecognized = unrecognized || event.Reason == "PodSecurityUnrecognizedWarnings"
+			}
+			if unrecognized != tt.expectEvent {
+				t.Errorf("expected an unrecognized warnings event %v, got %v", tt.expectEvent, unrecognized)
+			}
+		})
+	}
+
+	t.Run("invalid pattern rejected by the constructor", func(t *testing.T) {
+		_, err := newPodSecurityReadinessController(
+			v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+			events.NewInMemoryRecorder("test", clock.RealClock{}),
+			&scriptedWarningsHandler{},
+			Config{Evaluation: EvaluationConfig{ViolationWarningPattern: `violate the new PodSecurity enforce level (`}},
+		)
+		if err == nil || !strings.Contains(err.Error(), "invalid violation warning pattern") {
+			t.Errorf("expected an invalid pattern error, got %v", err)
+		}
+	})
+}
@@ -80,7 +80,17 @@ import (
 	migrationv1alpha1informer "sigs.k8s.io/kube-storage-version-migrator/pkg/clients/informer"
 )
 
-func RunOperator(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+// NewRunOperator returns RunOperator with the health checker the pod security
+// readiness controller reports its syncs to. The checker has to be registered
+// with the health checks of the controller command, which are installed
+// before the operator runs.
+func NewRunOperator(podSecurityReadinessHealthChecker *podsecurityreadinesscontroller.SyncHealthChecker) controllercmd.StartFunc {
+	return func(ctx context.Context, controllerContext *controllercmd.ControllerContext) error {
+		return RunOperator(ctx, controllerContext, podSecurityReadinessHealthChecker)
+	}
+}
+
+func RunOperator(ctx context.Context, controllerContext *controllercmd.ControllerContext, podSecurityReadinessHealthChecker *podsecurityreadinesscontroller.SyncHealthChecker) error {
 	// This kube client use protobuf, do not use it for CR
 	kubeClient, err := kubernetes.NewForConfig(controllerContext.ProtoKubeConfig)
 	if err != nil {
@@ -497,14 +507,19 @@ func RunOperator(ctx context.Context, controllerContext *controllercmd.Controlle
 		controllerContext.EventRecorder,
 	)
 
-	podSecurityReadinessController, err := podsecurityreadinesscontroller.NewPodSecurityReadinessController(
+	podSecurityReadinessController, podSecurityReadiness, err := podsecurityreadinesscontroller.NewPodSecurityReadinessController(
 		controllerContext.ProtoKubeConfig,
 		operatorClient,
 		controllerContext.EventRecorder,
+		podsecurityreadinesscontroller.NewWarningsHandler(),
+		podsecurityreadinesscontroller.Config{HealthChecker: podSecurityReadinessHealthChecker},
 	)
 	if err != nil {
 		return err
 	}
+	if controllerContext.Server != nil {
+		controllerContext.Server.Handler.NonGoRestfulMux.HandlePrefix(podsecurityreadinesscontroller.DebugPath+"/", podSecurityReadiness.DebugHandler())
+	}
 
 	// register termination metrics
 	terminationobserver.RegisterMetrics()